@@ -0,0 +1,251 @@
+// Package backupapp 把存档与服务器配置打包为 tar.gz，并按数量保留最近的备份。
+package backupapp
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"pz-web-backend/internal/application/restartapp"
+)
+
+// DefaultKeep 默认保留的备份数量。
+const DefaultKeep = 24
+
+// DefaultSaveTimeout 默认等待保存完成的时间。
+const DefaultSaveTimeout = 2 * time.Minute
+
+// Saver 打包前让服务器先落盘。
+type Saver interface {
+	Save() (string, error)
+}
+
+// Backup 一个备份文件。
+type Backup struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Service struct {
+	// BaseDataDir Zomboid 数据目录，包含 Saves/Multiplayer/<ServerName> 与 Server/<ServerName>*。
+	BaseDataDir string
+	ServerName  string
+	// OutDir 备份输出目录。
+	OutDir string
+	// Keep 保留的备份数量，<= 0 时使用 DefaultKeep。
+	Keep int
+	// RCON 为空或服务器未运行时直接打包当前文件。
+	RCON Saver
+	// Saves 非空时 save 之后等待日志中的保存完成标记再打包。
+	Saves restartapp.SaveWatcher
+	// SaveTimeout 等待保存完成的时间，<= 0 时使用 DefaultSaveTimeout。
+	SaveTimeout time.Duration
+	Now         func() time.Time
+}
+
+// Create 先执行 save 并等待保存完成，再打包存档目录与服务器配置；写入临时文件后改名，失败时不留下半个备份。
+func (s Service) Create(ctx context.Context) (Backup, error) {
+	if err := s.save(ctx); err != nil {
+		return Backup{}, err
+	}
+
+	now := s.now()
+	name := fmt.Sprintf("%s-%s.tar.gz", s.ServerName, now.UTC().Format("20060102-150405"))
+	if err := os.MkdirAll(s.OutDir, 0o755); err != nil {
+		return Backup{}, err
+	}
+	tmp, err := os.CreateTemp(s.OutDir, "."+name+".tmp-*")
+	if err != nil {
+		return Backup{}, err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	if err := s.writeArchive(ctx, tmp); err != nil {
+		_ = tmp.Close()
+		return Backup{}, err
+	}
+	if err := tmp.Close(); err != nil {
+		return Backup{}, err
+	}
+	path := filepath.Join(s.OutDir, name)
+	if err := os.Rename(tmpName, path); err != nil {
+		return Backup{}, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return Backup{}, err
+	}
+	if err := s.prune(); err != nil {
+		return Backup{}, fmt.Errorf("prune old backups: %w", err)
+	}
+	return Backup{Name: name, Size: info.Size(), CreatedAt: now}, nil
+}
+
+// save 服务器未运行或未开启 RCON 时磁盘上的存档已是最新，忽略 save 的错误；
+// save 成功后保存未在超时内完成则放弃本次备份，避免打包写到一半的存档。
+func (s Service) save(ctx context.Context) error {
+	if s.RCON == nil {
+		return nil
+	}
+	timeout := s.SaveTimeout
+	if timeout <= 0 {
+		timeout = DefaultSaveTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// 在发出 save 之前开始监听，以免错过完成标记。
+	var wait func() error
+	var watchErr error
+	if s.Saves != nil {
+		wait, watchErr = s.Saves.WatchSave(ctx)
+	}
+	if _, err := s.RCON.Save(); err != nil {
+		return nil
+	}
+	switch {
+	case watchErr != nil:
+		return fmt.Errorf("watch save: %w", watchErr)
+	case wait == nil:
+		return nil
+	}
+	if err := wait(); err != nil {
+		return fmt.Errorf("wait for save: %w", err)
+	}
+	return nil
+}
+
+// List 按时间倒序返回备份（文件名中带有时间戳）。
+func (s Service) List() ([]Backup, error) {
+	entries, err := os.ReadDir(s.OutDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Backup{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Backup, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") || !strings.HasSuffix(e.Name(), ".tar.gz") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		out = append(out, Backup{Name: e.Name(), Size: info.Size(), CreatedAt: info.ModTime()})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name > out[j].Name })
+	return out, nil
+}
+
+func (s Service) writeArchive(ctx context.Context, w io.Writer) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	saves := filepath.Join("Saves", "Multiplayer", s.ServerName)
+	if err := s.addTree(ctx, tw, saves); err != nil {
+		return err
+	}
+	// servertest.ini、servertest_SandboxVars.lua 等；不匹配 servertest2.ini 这类其他服务器的文件。
+	var configs []string
+	for _, pattern := range []string{s.ServerName + ".*", s.ServerName + "_*"} {
+		matches, err := filepath.Glob(filepath.Join(s.BaseDataDir, "Server", pattern))
+		if err != nil {
+			return err
+		}
+		configs = append(configs, matches...)
+	}
+	for _, p := range configs {
+		rel, _ := filepath.Rel(s.BaseDataDir, p)
+		if err := s.addTree(ctx, tw, rel); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// addTree 把 BaseDataDir 下的 rel（文件或目录）加入归档，不存在时跳过。
+func (s Service) addTree(ctx context.Context, tw *tar.Writer, rel string) error {
+	root := filepath.Join(s.BaseDataDir, rel)
+	if _, err := os.Stat(root); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() && !info.IsDir() {
+			return nil
+		}
+		name, _ := filepath.Rel(s.BaseDataDir, path)
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(name)
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		// 服务器运行中文件可能仍在增长，只写入头部声明的长度。
+		_, err = io.CopyN(tw, f, hdr.Size)
+		return err
+	})
+}
+
+func (s Service) prune() error {
+	keep := s.Keep
+	if keep <= 0 {
+		keep = DefaultKeep
+	}
+	all, err := s.List()
+	if err != nil {
+		return err
+	}
+	for i := keep; i < len(all); i++ {
+		if err := os.Remove(filepath.Join(s.OutDir, all[i].Name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s Service) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
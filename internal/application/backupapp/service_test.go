@@ -0,0 +1,148 @@
+package backupapp
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+type fakeSaver struct{ calls int }
+
+func (f *fakeSaver) Save() (string, error) {
+	f.calls++
+	return "", nil
+}
+
+func TestService_CreateArchivesSavesAndConfigAndPrunes(t *testing.T) {
+	dir := t.TempDir()
+	write := func(rel, content string) {
+		p := filepath.Join(dir, rel)
+		_ = os.MkdirAll(filepath.Dir(p), 0o755)
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("Saves/Multiplayer/servertest/map_t.bin", "map")
+	write("Saves/Multiplayer/servertest/chunkdata/1.bin", "chunk")
+	write("Saves/Multiplayer/other/map_t.bin", "other")
+	write("Server/servertest.ini", "PVP=true")
+	write("Server/servertest_SandboxVars.lua", "SandboxVars = {}")
+	write("Server/servertest2.ini", "")
+
+	now := time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC)
+	saver := &fakeSaver{}
+	s := Service{
+		BaseDataDir: dir,
+		ServerName:  "servertest",
+		OutDir:      filepath.Join(dir, "backups"),
+		Keep:        2,
+		RCON:        saver,
+		Now:         func() time.Time { return now },
+	}
+
+	var last Backup
+	for i := 0; i < 3; i++ {
+		b, err := s.Create(context.Background())
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		last = b
+		now = now.Add(time.Hour)
+	}
+	if saver.calls != 3 || last.Name != "servertest-20240101-050000.tar.gz" || last.Size == 0 {
+		t.Fatalf("calls=%d last=%+v", saver.calls, last)
+	}
+	list, _ := s.List()
+	if len(list) != 2 || list[0].Name != last.Name {
+		t.Fatalf("list=%+v", list)
+	}
+
+	f, err := os.Open(filepath.Join(s.OutDir, last.Name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var files []string
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			break
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			files = append(files, hdr.Name)
+		}
+	}
+	sort.Strings(files)
+	want := []string{
+		"Saves/Multiplayer/servertest/chunkdata/1.bin",
+		"Saves/Multiplayer/servertest/map_t.bin",
+		"Server/servertest.ini",
+		"Server/servertest_SandboxVars.lua",
+	}
+	if !reflect.DeepEqual(files, want) {
+		t.Fatalf("files=%v", files)
+	}
+}
+
+type fakeSaves struct {
+	saver   *fakeSaver
+	watched bool
+	err     error
+}
+
+func (f *fakeSaves) WatchSave(ctx context.Context) (func() error, error) {
+	f.watched = true
+	return func() error {
+		if f.saver.calls == 0 {
+			return errors.New("waited before save")
+		}
+		return f.err
+	}, nil
+}
+
+type downSaver struct{}
+
+func (downSaver) Save() (string, error) { return "", errors.New("connection refused") }
+
+type brokenSaves struct{}
+
+func (brokenSaves) WatchSave(ctx context.Context) (func() error, error) {
+	return nil, errors.New("log not found")
+}
+
+func TestService_CreateWaitsForSave(t *testing.T) {
+	dir := t.TempDir()
+	saver := &fakeSaver{}
+	saves := &fakeSaves{saver: saver}
+	s := Service{BaseDataDir: dir, ServerName: "servertest", OutDir: filepath.Join(dir, "backups"), RCON: saver, Saves: saves}
+	if _, err := s.Create(context.Background()); err != nil || !saves.watched {
+		t.Fatalf("err=%v watched=%v", err, saves.watched)
+	}
+
+	// 保存没有完成时不打包。
+	saves.err = context.DeadlineExceeded
+	if _, err := s.Create(context.Background()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err=%v", err)
+	}
+	if list, _ := s.List(); len(list) != 1 {
+		t.Fatalf("list=%+v", list)
+	}
+
+	// 服务器未运行时日志可能不存在，直接打包。
+	s.RCON, s.Saves = downSaver{}, brokenSaves{}
+	if _, err := s.Create(context.Background()); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+}
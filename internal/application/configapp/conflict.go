@@ -0,0 +1,80 @@
+package configapp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+
+	"pz-web-backend/internal/textdiff"
+)
+
+// ErrETagRequired 保存与回滚必须带上读取时的 ETag（或显式 Force），避免覆盖他人同时的修改。
+var ErrETagRequired = errors.New("etag required: reload the config and save again")
+
+// AbsentETag 文件尚不存在时的 ETag；以它保存表示创建新文件，期间文件已被他人创建则冲突。
+const AbsentETag = "absent"
+
+// ContentETag 返回文件内容的 ETag（SHA-256 十六进制）；文件不存在（data 为 nil）时为 AbsentETag。
+func ContentETag(data []byte) string {
+	if data == nil {
+		return AbsentETag
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// ConflictError 保存时发现文件在读取之后已被修改。
+type ConflictError struct {
+	// CurrentETag 磁盘上当前文件的 ETag。
+	CurrentETag string
+	// Diff 读取时的版本到当前文件的差异；历史中找不到读取时的版本时，
+	// 为当前文件到本次提交内容的差异。
+	Diff string
+}
+
+func (e *ConflictError) Error() string {
+	return "config file was modified by someone else, reload and try again"
+}
+
+// checkETag 比较 expect 与当前文件；expect 为空时不检查（预览、Force 保存）。
+// mine 返回本次要写入的内容，找不到读取时的版本时用于展示差异。
+func (s Service) checkETag(kind SaveKind, expect string, original []byte, mine func() (string, error)) error {
+	if expect == "" {
+		return nil
+	}
+	current := ContentETag(original)
+	if current == expect {
+		return nil
+	}
+
+	conflict := &ConflictError{CurrentETag: current}
+	if base, ok := s.snapshotByHash(kind, expect); ok {
+		conflict.Diff = MaskSecrets(kind, textdiff.Unified("loaded", "current", string(base), string(original), textdiff.DefaultContext))
+		return conflict
+	}
+
+	// 找不到基线时，展示本次提交会对当前文件造成的改动。
+	if content, err := mine(); err == nil {
+		conflict.Diff = MaskSecrets(kind, textdiff.Unified("current", "yours", string(original), content, textdiff.DefaultContext))
+	}
+	return conflict
+}
+
+func (s Service) snapshotByHash(kind SaveKind, hash string) ([]byte, bool) {
+	if s.History == nil {
+		return nil, false
+	}
+	snaps, err := s.History.List(string(kind))
+	if err != nil {
+		return nil, false
+	}
+	for _, snap := range snaps {
+		if snap.Hash != hash {
+			continue
+		}
+		if _, content, err := s.History.Get(snap.ID); err == nil {
+			return content, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,111 @@
+package configapp
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"pz-web-backend/internal/history"
+	"pz-web-backend/internal/textdiff"
+)
+
+// CurrentRevision 在 Diff 中表示磁盘上的当前文件。
+const CurrentRevision = "current"
+
+// ListHistory 按时间倒序返回 kind 的快照。
+func (s Service) ListHistory(kind SaveKind) ([]history.Snapshot, error) {
+	if s.History == nil {
+		return nil, fmt.Errorf("history not configured")
+	}
+	if s.configPath(kind) == "" {
+		return nil, fmt.Errorf("invalid config kind: %s", kind)
+	}
+	return s.History.List(string(kind))
+}
+
+// GetSnapshot 返回快照及其内容；快照不属于 kind 时视为不存在。
+func (s Service) GetSnapshot(kind SaveKind, id string) (history.Snapshot, []byte, error) {
+	if s.History == nil {
+		return history.Snapshot{}, nil, fmt.Errorf("history not configured")
+	}
+	snap, content, err := s.History.Get(id)
+	if err != nil {
+		return history.Snapshot{}, nil, err
+	}
+	if snap.Kind != string(kind) {
+		return history.Snapshot{}, nil, history.ErrNotFound
+	}
+	return snap, content, nil
+}
+
+// DiffSnapshots 返回 from 到 to 的 unified diff；任一方为 CurrentRevision 时取磁盘上的文件。
+// 结果中的敏感值已遮盖。
+func (s Service) DiffSnapshots(kind SaveKind, from string, to string) (string, error) {
+	fromContent, err := s.revisionContent(kind, from)
+	if err != nil {
+		return "", err
+	}
+	toContent, err := s.revisionContent(kind, to)
+	if err != nil {
+		return "", err
+	}
+	return MaskSecrets(kind, textdiff.Unified(from, to, string(fromContent), string(toContent), textdiff.DefaultContext)), nil
+}
+
+// Restore 将快照内容重新写回配置文件并返回新的 ETag。etag 为读取当前文件时的 ETag，
+// 与 Save 一样必须提供，文件已被他人修改时返回 *ConflictError。
+// 与 Save 走同一写盘路径（chown、历史、重启）。
+func (s Service) Restore(kind SaveKind, id string, etag string, author string, restart bool) (string, error) {
+	if etag == "" {
+		return "", ErrETagRequired
+	}
+	snap, content, err := s.GetSnapshot(kind, id)
+	if err != nil {
+		return "", err
+	}
+
+	newETag, err := s.locked(kind, func() (rendered, error) {
+		r := rendered{path: s.configPath(kind), content: string(content)}
+		original, err := s.FS.ReadFile(r.path)
+		switch {
+		case err == nil:
+			r.original, r.exists = original, true
+		case !errors.Is(err, os.ErrNotExist):
+			return r, fmt.Errorf("read file: %w", err)
+		}
+		mine := func() (string, error) { return r.content, nil }
+		return r, s.checkETag(kind, etag, r.original, mine)
+	}, author, "restore "+snap.ID)
+	if err != nil {
+		return newETag, err
+	}
+	return newETag, s.restartIf(restart)
+}
+
+func (s Service) revisionContent(kind SaveKind, rev string) ([]byte, error) {
+	if rev == CurrentRevision {
+		return s.FS.ReadFile(s.configPath(kind))
+	}
+	_, content, err := s.GetSnapshot(kind, rev)
+	return content, err
+}
+
+// snapshotBaseline 在某类配置第一次通过面板保存前，先保存原始文件，
+// 保证第一次保存也能回滚。
+func (s Service) snapshotBaseline(kind SaveKind, r rendered) error {
+	if s.History == nil || !r.exists {
+		return nil
+	}
+	existing, err := s.History.List(string(kind))
+	if err != nil {
+		return fmt.Errorf("list history: %w", err)
+	}
+	if len(existing) > 0 {
+		return nil
+	}
+	snap := history.Snapshot{Kind: string(kind), Author: "system", Note: "original file before first save"}
+	if _, err := s.History.Add(snap, r.original); err != nil {
+		return fmt.Errorf("record history: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package configapp
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"pz-web-backend/internal/config"
+	"pz-web-backend/internal/history"
+	"pz-web-backend/internal/infra/fs"
+)
+
+func TestService_SaveRecordsHistoryAndRestore(t *testing.T) {
+	base := t.TempDir()
+	osfs := fs.OSFS{}
+	iniPath := filepath.Join(base, "Server", "servertest.ini")
+	if err := osfs.MkdirAll(filepath.Dir(iniPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	original := "# loot\nPVP=false\n"
+	if err := osfs.WriteFile(iniPath, []byte(original), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	svc := Service{
+		BaseDataDir: base,
+		ServerName:  "servertest",
+		DevMode:     true,
+		FS:          osfs,
+		History:     &history.FileStore{Dir: filepath.Join(base, "history")},
+	}
+
+	saved, err := svc.Save(SaveInput{
+		Kind:   KindServer,
+		Items:  []config.Item{{Key: "PVP", Value: "true"}},
+		Author: "alice",
+		Note:   "enable pvp",
+		ETag:   ContentETag([]byte(original)),
+	})
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	snaps, err := svc.ListHistory(KindServer)
+	if err != nil {
+		t.Fatalf("ListHistory: %v", err)
+	}
+	if len(snaps) != 2 {
+		t.Fatalf("snaps=%+v", snaps)
+	}
+	latest, baseline := snaps[0], snaps[1]
+	if latest.Author != "alice" || latest.Note != "enable pvp" || baseline.Author != "system" {
+		t.Fatalf("snaps=%+v", snaps)
+	}
+
+	diff, err := svc.DiffSnapshots(KindServer, baseline.ID, latest.ID)
+	if err != nil {
+		t.Fatalf("DiffSnapshots: %v", err)
+	}
+	if !strings.Contains(diff, "-PVP=false\n+PVP=true\n") {
+		t.Fatalf("diff=%s", diff)
+	}
+
+	if _, err := svc.Restore(KindServer, baseline.ID, "", "bob", false); !errors.Is(err, ErrETagRequired) {
+		t.Fatalf("Restore without etag: %v", err)
+	}
+	var conflict *ConflictError
+	if _, err := svc.Restore(KindServer, baseline.ID, ContentETag([]byte(original)), "bob", false); !errors.As(err, &conflict) || conflict.CurrentETag != saved {
+		t.Fatalf("Restore with stale etag: %v", err)
+	}
+	if _, err := svc.Restore(KindServer, baseline.ID, saved, "bob", false); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	got, _ := osfs.ReadFile(iniPath)
+	if string(got) != original {
+		t.Fatalf("restored=%q", got)
+	}
+
+	snaps, _ = svc.ListHistory(KindServer)
+	if len(snaps) != 3 || snaps[0].Author != "bob" || !strings.HasPrefix(snaps[0].Note, "restore ") {
+		t.Fatalf("after restore snaps=%+v", snaps)
+	}
+
+	if _, _, err := svc.GetSnapshot(KindSandbox, baseline.ID); err != history.ErrNotFound {
+		t.Fatalf("expected kind mismatch to be not found, err=%v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package configapp
+
+import (
+	"path/filepath"
+
+	"pz-web-backend/internal/config"
+	"pz-web-backend/internal/textdiff"
+)
+
+// Change 预览中的单项变更。
+type Change struct {
+	Key     string `json:"key"`
+	Label   string `json:"label"`
+	Section string `json:"section"`
+	Old     string `json:"old"`
+	New     string `json:"new"`
+	// Added 表示文件中原本没有该 key。
+	Added bool `json:"added"`
+}
+
+// Preview 保存前的变更预览。
+type Preview struct {
+	Filename string   `json:"filename"`
+	Diff     string   `json:"diff"`
+	Changes  []Change `json:"changes"`
+}
+
+// Preview 按 Save 相同的规则生成新内容，但不写盘。
+// 校验失败时与 Save 一样返回 config.ValidationErrors。
+// 出生点类配置没有逐项的 Item，Changes 为空，只返回 Diff。
+func (s Service) Preview(in SaveInput, lang string) (Preview, error) {
+	r, err := s.render(in)
+	if err != nil {
+		return Preview{}, err
+	}
+
+	current, err := s.currentItems(in.Kind, r, lang)
+	if err != nil {
+		return Preview{}, err
+	}
+	byKey := make(map[string]config.Item, len(current))
+	for _, item := range current {
+		byKey[item.Key] = item
+	}
+
+	changes := []Change{}
+	for _, item := range in.Items {
+		old, exists := byKey[item.Key]
+		if exists && old.Value == item.Value {
+			continue
+		}
+		if config.IsSecretKey(item.Key) && in.Kind == KindServer {
+			if item.Value == config.SecretPlaceholder {
+				continue
+			}
+			old.Value, item.Value = maskValue(old.Value), maskValue(item.Value)
+		}
+		ch := Change{
+			Key:     item.Key,
+			Label:   item.Label,
+			Section: item.Section,
+			Old:     old.Value,
+			New:     item.Value,
+			Added:   !exists,
+		}
+		if exists {
+			ch.Label, ch.Section = old.Label, old.Section
+		}
+		changes = append(changes, ch)
+	}
+
+	name := filepath.Base(r.path)
+	return Preview{
+		Filename: name,
+		Diff:     MaskSecrets(in.Kind, textdiff.Unified(name, name+" (preview)", string(r.original), r.content, textdiff.DefaultContext)),
+		Changes:  changes,
+	}, nil
+}
+
+// currentItems 解析磁盘上的当前内容，文件不存在时返回空列表。
+func (s Service) currentItems(kind SaveKind, r rendered, lang string) ([]config.Item, error) {
+	if !r.exists {
+		return nil, nil
+	}
+	switch kind {
+	case KindServer:
+		return s.Config.ServerINIItems(config.ParseINIDocument(r.original), lang), nil
+	case KindSandbox:
+		chunk, err := config.ParseLua(r.original)
+		if err != nil {
+			return nil, err
+		}
+		return s.Config.SandboxLuaItems(chunk, lang)
+	}
+	return nil, nil
+}
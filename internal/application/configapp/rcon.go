@@ -0,0 +1,32 @@
+package configapp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"pz-web-backend/internal/infra/rcon"
+)
+
+// defaultRCONPort PZ 的默认 RCON 端口。
+const defaultRCONPort = 27015
+
+// RCONCredentials 从服务器 INI 读取 RCON 端口与密码；未设置密码时返回 rcon.ErrDisabled。
+func (s Service) RCONCredentials() (int, string, error) {
+	doc, err := s.serverDocument()
+	if err != nil {
+		return 0, "", err
+	}
+	password, _ := doc.Get("RCONPassword")
+	if password == "" {
+		return 0, "", rcon.ErrDisabled
+	}
+	port := defaultRCONPort
+	if v, ok := doc.Get("RCONPort"); ok && strings.TrimSpace(v) != "" {
+		port, err = strconv.Atoi(strings.TrimSpace(v))
+		if err != nil || port <= 0 || port > 65535 {
+			return 0, "", fmt.Errorf("invalid RCONPort %q", v)
+		}
+	}
+	return port, password, nil
+}
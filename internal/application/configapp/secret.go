@@ -0,0 +1,134 @@
+package configapp
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"pz-web-backend/internal/audit"
+	"pz-web-backend/internal/config"
+)
+
+// ErrUnknownSecret key 不是受保护的敏感项。
+var ErrUnknownSecret = errors.New("unknown secret key")
+
+// SecretStatus 敏感项是否已设置，不含值本身。
+type SecretStatus struct {
+	Key string `json:"key"`
+	Set bool   `json:"set"`
+}
+
+// MaskSecrets 遮盖 kind 对应文件内容（或其 diff）中的敏感值；只有服务器 INI 含敏感项。
+func MaskSecrets(kind SaveKind, text string) string {
+	if kind != KindServer {
+		return text
+	}
+	return config.MaskINISecrets(text)
+}
+
+// ListSecrets 返回各敏感项是否已设置。
+func (s Service) ListSecrets() ([]SecretStatus, error) {
+	doc, err := s.serverDocument()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]SecretStatus, 0, len(config.SecretKeys()))
+	for _, key := range config.SecretKeys() {
+		v, _ := doc.Get(key)
+		out = append(out, SecretStatus{Key: key, Set: v != ""})
+	}
+	return out, nil
+}
+
+// RevealSecret 返回敏感项的明文。审计记录写入失败时拒绝返回。
+func (s Service) RevealSecret(key string, actor audit.Entry) (string, error) {
+	if !config.IsSecretKey(key) {
+		return "", ErrUnknownSecret
+	}
+	doc, err := s.serverDocument()
+	if err != nil {
+		return "", err
+	}
+	if err := s.recordAudit(actor, "secret.reveal", key); err != nil {
+		return "", err
+	}
+	v, _ := doc.Get(key)
+	return v, nil
+}
+
+// RotateSecret 将敏感项改为 value（为空时随机生成），返回新值与文件的新 ETag。
+// 写盘与 Save 走同一路径，会记录历史快照。
+func (s Service) RotateSecret(key string, value string, actor audit.Entry, restart bool) (string, string, error) {
+	if !config.IsSecretKey(key) {
+		return "", "", ErrUnknownSecret
+	}
+	if value == "" {
+		var err error
+		if value, err = randomSecret(24); err != nil {
+			return "", "", err
+		}
+	}
+	if value == config.SecretPlaceholder {
+		return "", "", fmt.Errorf("%s cannot be set to the placeholder value", key)
+	}
+
+	in := SaveInput{Kind: KindServer, Items: []config.Item{{Key: key, Value: value}}}
+	etag, err := s.locked(KindServer, func() (rendered, error) { return s.render(in) }, actor.Actor, "rotate "+key)
+	if err != nil {
+		return "", "", err
+	}
+	if err := s.recordAudit(actor, "secret.rotate", key); err != nil {
+		return value, etag, err
+	}
+	return value, etag, s.restartIf(restart)
+}
+
+// RecentAudit 按时间倒序返回最近的审计记录。
+func (s Service) RecentAudit(limit int) ([]audit.Entry, error) {
+	if s.Audit == nil {
+		return nil, fmt.Errorf("audit log not configured")
+	}
+	return s.Audit.Recent(limit)
+}
+
+func (s Service) serverDocument() (*config.INIDocument, error) {
+	data, err := s.FS.ReadFile(s.configPath(KindServer))
+	if err != nil {
+		return nil, err
+	}
+	return config.ParseINIDocument(data), nil
+}
+
+func (s Service) recordAudit(e audit.Entry, action string, target string) error {
+	if s.Audit == nil {
+		return fmt.Errorf("audit log not configured")
+	}
+	e.Action, e.Target = action, target
+	if err := s.Audit.Record(e); err != nil {
+		return fmt.Errorf("record audit: %w", err)
+	}
+	return nil
+}
+
+func maskValue(v string) string {
+	if v == "" {
+		return ""
+	}
+	return config.SecretPlaceholder
+}
+
+const secretAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz23456789"
+
+func randomSecret(n int) (string, error) {
+	b := make([]byte, n)
+	max := big.NewInt(int64(len(secretAlphabet)))
+	for i := range b {
+		idx, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = secretAlphabet[idx.Int64()]
+	}
+	return string(b), nil
+}
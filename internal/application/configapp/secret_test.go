@@ -0,0 +1,116 @@
+package configapp
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"pz-web-backend/internal/audit"
+	"pz-web-backend/internal/config"
+	"pz-web-backend/internal/history"
+	"pz-web-backend/internal/infra/fs"
+)
+
+func newSecretTestService(t *testing.T, ini string) (Service, string, *audit.FileLogger) {
+	t.Helper()
+	base := t.TempDir()
+	iniPath := filepath.Join(base, "Server", "servertest.ini")
+	osfs := fs.OSFS{}
+	if err := osfs.MkdirAll(filepath.Dir(iniPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := osfs.WriteFile(iniPath, []byte(ini), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	logger := &audit.FileLogger{Path: filepath.Join(base, "audit.log")}
+	svc := Service{
+		BaseDataDir: base,
+		ServerName:  "servertest",
+		DevMode:     true,
+		FS:          osfs,
+		History:     &history.FileStore{Dir: filepath.Join(base, "history")},
+		Audit:       logger,
+	}
+	return svc, iniPath, logger
+}
+
+func TestService_SaveKeepsSecretWhenPlaceholderReturned(t *testing.T) {
+	svc, iniPath, _ := newSecretTestService(t, "PVP=false\nRCONPassword=hunter2\n")
+
+	_, err := svc.Save(SaveInput{Kind: KindServer, Items: []config.Item{
+		{Key: "PVP", Value: "true"},
+		{Key: "RCONPassword", Value: config.SecretPlaceholder},
+	}, ETag: ContentETag([]byte("PVP=false\nRCONPassword=hunter2\n"))})
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	got, _ := os.ReadFile(iniPath)
+	if string(got) != "PVP=true\nRCONPassword=hunter2\n" {
+		t.Fatalf("got=%q", got)
+	}
+
+	diff, err := svc.DiffSnapshots(KindServer, mustOldestSnapshot(t, svc).ID, CurrentRevision)
+	if err != nil {
+		t.Fatalf("DiffSnapshots: %v", err)
+	}
+	if strings.Contains(diff, "hunter2") {
+		t.Fatalf("diff leaks secret:\n%s", diff)
+	}
+}
+
+func TestService_RotateSecret_NumericPassword(t *testing.T) {
+	svc, iniPath, _ := newSecretTestService(t, "RCONPassword=12345\n")
+
+	rotated, _, err := svc.RotateSecret("RCONPassword", "", audit.Entry{Actor: "alice"}, false)
+	if err != nil {
+		t.Fatalf("RotateSecret: %v", err)
+	}
+	got, _ := os.ReadFile(iniPath)
+	if string(got) != "RCONPassword="+rotated+"\n" {
+		t.Fatalf("got=%q", got)
+	}
+}
+
+func TestService_RevealAndRotateSecretAreAudited(t *testing.T) {
+	svc, iniPath, logger := newSecretTestService(t, "RCONPassword=hunter2\n")
+	who := audit.Entry{Actor: "alice", IP: "192.0.2.1"}
+
+	v, err := svc.RevealSecret("RCONPassword", who)
+	if err != nil || v != "hunter2" {
+		t.Fatalf("v=%q err=%v", v, err)
+	}
+	if _, err := svc.RevealSecret("PVP", who); !errors.Is(err, ErrUnknownSecret) {
+		t.Fatalf("err=%v", err)
+	}
+
+	rotated, _, err := svc.RotateSecret("RCONPassword", "", who, false)
+	if err != nil {
+		t.Fatalf("RotateSecret: %v", err)
+	}
+	if len(rotated) != 24 || rotated == "hunter2" {
+		t.Fatalf("rotated=%q", rotated)
+	}
+	got, _ := os.ReadFile(iniPath)
+	if string(got) != "RCONPassword="+rotated+"\n" {
+		t.Fatalf("got=%q", got)
+	}
+
+	entries, err := logger.Recent(0)
+	if err != nil {
+		t.Fatalf("Recent: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Action != "secret.rotate" || entries[1].Action != "secret.reveal" || entries[1].Actor != "alice" {
+		t.Fatalf("entries=%+v", entries)
+	}
+}
+
+func mustOldestSnapshot(t *testing.T, svc Service) history.Snapshot {
+	t.Helper()
+	snaps, err := svc.ListHistory(KindServer)
+	if err != nil || len(snaps) == 0 {
+		t.Fatalf("snaps=%v err=%v", snaps, err)
+	}
+	return snaps[len(snaps)-1]
+}
@@ -1,13 +1,18 @@
 package configapp
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"path"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 
+	"pz-web-backend/internal/audit"
 	"pz-web-backend/internal/config"
+	"pz-web-backend/internal/history"
 	"pz-web-backend/internal/infra/executil"
 	"pz-web-backend/internal/infra/fs"
 	"pz-web-backend/internal/infra/supervisor"
@@ -23,61 +28,299 @@ type Service struct {
 	Runner executil.Runner
 
 	Restarter supervisor.Restarter
+	History   history.Store
+	Audit     audit.Logger
 }
 
-func (s Service) GetServerConfig(lang string) ([]config.Item, error) {
-	serverName := s.resolvedServerName()
-	path := filepath.Join(s.BaseDataDir, "Server", serverName+".ini")
-	return s.Config.ParseServerINI(path, lang)
+// GetServerConfig 返回服务器配置项及文件内容的 ETag，保存时需回传该 ETag。
+// 密码等敏感项的值以 config.SecretPlaceholder 返回。
+func (s Service) GetServerConfig(lang string) ([]config.Item, string, error) {
+	data, err := s.FS.ReadFile(s.configPath(KindServer))
+	if err != nil {
+		return nil, "", err
+	}
+	items := s.Config.ServerINIItems(config.ParseINIDocument(data), lang)
+	config.MaskSecrets(items)
+	return items, ContentETag(data), nil
 }
 
-func (s Service) GetSandboxConfig(lang string) ([]config.Item, error) {
-	serverName := s.resolvedServerName()
-	path := filepath.Join(s.BaseDataDir, "Server", serverName+"_SandboxVars.lua")
-	return s.Config.ParseSandboxLua(path, lang)
+// GetSandboxConfig 返回沙盒配置项及文件内容的 ETag。
+func (s Service) GetSandboxConfig(lang string) ([]config.Item, string, error) {
+	data, err := s.FS.ReadFile(s.configPath(KindSandbox))
+	if err != nil {
+		return nil, "", err
+	}
+	chunk, err := config.ParseLua(data)
+	if err != nil {
+		return nil, "", err
+	}
+	items, err := s.Config.SandboxLuaItems(chunk, lang)
+	if err != nil {
+		return nil, "", err
+	}
+	return items, ContentETag(data), nil
+}
+
+// GetSpawnRegions 返回出生区域（含被注释掉的条目）及文件的 ETag；文件不存在时返回空列表与 AbsentETag。
+func (s Service) GetSpawnRegions() ([]config.SpawnRegion, string, error) {
+	data, err := s.FS.ReadFile(s.configPath(KindSpawnRegions))
+	if errors.Is(err, os.ErrNotExist) {
+		return []config.SpawnRegion{}, AbsentETag, nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	chunk, err := config.ParseLua(data)
+	if err != nil {
+		return nil, "", err
+	}
+	regions, err := s.Config.SpawnRegions(chunk)
+	if err != nil {
+		return nil, "", err
+	}
+	return regions, ContentETag(data), nil
+}
+
+// GetSpawnPoints 返回按职业分组的出生点及文件的 ETag；文件不存在时返回空列表与 AbsentETag。
+func (s Service) GetSpawnPoints() ([]config.ProfessionSpawns, string, error) {
+	data, err := s.FS.ReadFile(s.configPath(KindSpawnPoints))
+	if errors.Is(err, os.ErrNotExist) {
+		return []config.ProfessionSpawns{}, AbsentETag, nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	chunk, err := config.ParseLua(data)
+	if err != nil {
+		return nil, "", err
+	}
+	points, err := s.Config.SpawnPoints(chunk)
+	if err != nil {
+		return nil, "", err
+	}
+	return points, ContentETag(data), nil
 }
 
 type SaveKind string
 
 const (
-	KindServer  SaveKind = "server"
-	KindSandbox SaveKind = "sandbox"
+	KindServer       SaveKind = "server"
+	KindSandbox      SaveKind = "sandbox"
+	KindSpawnRegions SaveKind = "spawnregions"
+	KindSpawnPoints  SaveKind = "spawnpoints"
 )
 
-func (s Service) Save(kind SaveKind, items []config.Item, restart bool) error {
-	var path string
-	var content string
+// Valid 判断是否为已知的配置类型。
+func (k SaveKind) Valid() bool {
+	switch k {
+	case KindServer, KindSandbox, KindSpawnRegions, KindSpawnPoints:
+		return true
+	}
+	return false
+}
 
-	serverName := s.resolvedServerName()
-	switch kind {
-	case KindServer:
-		path = filepath.Join(s.BaseDataDir, "Server", serverName+".ini")
-		content = s.Config.GenerateServerINI(items)
-	case KindSandbox:
-		path = filepath.Join(s.BaseDataDir, "Server", serverName+"_SandboxVars.lua")
-		content = s.Config.GenerateSandboxLua(items)
-	default:
-		return fmt.Errorf("invalid config kind: %s", kind)
+// SaveInput 一次保存请求。Author / Note 记录到历史快照。
+// Items 用于 server / sandbox，Regions / Professions 分别用于 spawnregions / spawnpoints。
+type SaveInput struct {
+	Kind        SaveKind
+	Items       []config.Item
+	Regions     []config.SpawnRegion
+	Professions []config.ProfessionSpawns
+	Restart     bool
+	Author      string
+	Note        string
+	// ETag 读取配置时拿到的 ETag；文件已被他人修改则返回 *ConflictError。
+	ETag string
+	// Force 不带 ETag 直接以当前文件为基础写入，可能覆盖他人同时的修改。
+	Force bool
+}
+
+// Save 写入配置并返回新文件的 ETag。ETag 为空且未设置 Force 时返回 ErrETagRequired。
+func (s Service) Save(in SaveInput) (string, error) {
+	if in.ETag == "" && !in.Force {
+		return "", ErrETagRequired
+	}
+	etag, err := s.locked(in.Kind, func() (rendered, error) { return s.render(in) }, in.Author, in.Note)
+	if err != nil {
+		return etag, err
+	}
+	return etag, s.restartIf(in.Restart)
+}
+
+// pathLocks 每个配置文件一把锁（路径 -> *sync.Mutex）。
+var pathLocks sync.Map
+
+func lockPath(p string) func() {
+	v, _ := pathLocks.LoadOrStore(p, &sync.Mutex{})
+	mu := v.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
+// locked 持有配置文件的锁完成读取、ETag 校验与写盘，两个基于同一 ETag 的保存不会互相覆盖。
+func (s Service) locked(kind SaveKind, render func() (rendered, error), author string, note string) (string, error) {
+	defer lockPath(s.configPath(kind))()
+	r, err := render()
+	if err != nil {
+		return "", err
+	}
+	return s.commit(kind, r, author, note)
+}
+
+// commit 原子写入新内容并记录快照，是保存与回滚共用的写盘路径；调用方需持有 lockPath。
+func (s Service) commit(kind SaveKind, r rendered, author string, note string) (string, error) {
+	if err := s.snapshotBaseline(kind, r); err != nil {
+		return "", err
 	}
 
-	if err := s.FS.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return fmt.Errorf("mkdir: %w", err)
+	if err := s.FS.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return "", fmt.Errorf("mkdir: %w", err)
 	}
-	if err := s.FS.WriteFile(path, []byte(content), 0o644); err != nil {
-		return fmt.Errorf("write file: %w", err)
+	if err := s.FS.WriteFileAtomic(r.path, []byte(r.content), 0o644); err != nil {
+		return "", fmt.Errorf("write file: %w", err)
 	}
+	etag := ContentETag([]byte(r.content))
 
 	if !s.DevMode && s.Runner != nil {
-		_, _ = s.Runner.CombinedOutput("chown", "steam:steam", path)
+		_, _ = s.Runner.CombinedOutput("chown", "steam:steam", r.path)
+	}
+
+	if s.History != nil {
+		snap := history.Snapshot{Kind: string(kind), Author: author, Note: note}
+		if _, err := s.History.Add(snap, []byte(r.content)); err != nil {
+			return etag, fmt.Errorf("record history: %w", err)
+		}
 	}
+	return etag, nil
+}
 
+// restartIf 写盘完成、释放锁之后再重启（重启会等待任务结束）。
+func (s Service) restartIf(restart bool) error {
 	if restart && s.Restarter != nil {
 		return s.Restarter.RestartPZServer()
 	}
-
 	return nil
 }
 
+// rendered 保存前计算出的目标文件与新内容。
+type rendered struct {
+	path     string
+	original []byte
+	exists   bool
+	content  string
+}
+
+// render 读取当前文件、校验输入并生成新内容，不写盘。
+// 校验失败时返回 config.ValidationErrors；in.ETag 与当前文件不符时返回 *ConflictError。
+func (s Service) render(in SaveInput) (rendered, error) {
+	kind, items := in.Kind, in.Items
+	r := rendered{path: s.configPath(kind)}
+	if r.path == "" {
+		return r, fmt.Errorf("invalid config kind: %s", kind)
+	}
+
+	original, err := s.FS.ReadFile(r.path)
+	switch {
+	case err == nil:
+		r.original, r.exists = original, true
+	case errors.Is(err, os.ErrNotExist):
+	default:
+		return r, fmt.Errorf("read file: %w", err)
+	}
+
+	mine := func() (string, error) {
+		in.ETag = ""
+		r, err := s.render(in)
+		return r.content, err
+	}
+	if err := s.checkETag(kind, in.ETag, r.original, mine); err != nil {
+		return r, err
+	}
+
+	switch kind {
+	case KindServer:
+		if !r.exists {
+			items = config.KeepSecrets(items, nil)
+			if errs := s.Config.ServerSchema(config.ParseINIDocument(nil)).Validate(items); len(errs) > 0 {
+				return r, errs
+			}
+			r.content = s.Config.GenerateServerINI(items)
+			return r, nil
+		}
+		doc := config.ParseINIDocument(r.original)
+		items = config.KeepSecrets(items, doc)
+		if errs := s.Config.ServerSchema(doc).Validate(items); len(errs) > 0 {
+			return r, errs
+		}
+		r.content = s.Config.UpdateServerINI(r.original, items)
+	case KindSandbox:
+		if !r.exists {
+			if errs := s.Config.DefaultSandboxSchema().Validate(items); len(errs) > 0 {
+				return r, errs
+			}
+			r.content = s.Config.GenerateSandboxLua(items)
+			return r, nil
+		}
+		chunk, err := config.ParseLua(r.original)
+		if err != nil {
+			return r, fmt.Errorf("parse sandbox: %w", err)
+		}
+		schema, err := s.Config.SandboxSchema(chunk)
+		if err != nil {
+			return r, fmt.Errorf("parse sandbox: %w", err)
+		}
+		if errs := schema.Validate(items); len(errs) > 0 {
+			return r, errs
+		}
+		r.content, err = s.Config.UpdateSandboxLua(r.original, items)
+		if err != nil {
+			return r, fmt.Errorf("update sandbox: %w", err)
+		}
+	case KindSpawnRegions:
+		if errs := config.ValidateSpawnRegions(in.Regions); len(errs) > 0 {
+			return r, errs
+		}
+		if !r.exists {
+			r.content = s.Config.GenerateSpawnRegions(in.Regions)
+			return r, nil
+		}
+		var err error
+		r.content, err = s.Config.UpdateSpawnRegions(r.original, in.Regions)
+		if err != nil {
+			return r, fmt.Errorf("update spawn regions: %w", err)
+		}
+	case KindSpawnPoints:
+		if errs := config.ValidateSpawnPoints(in.Professions); len(errs) > 0 {
+			return r, errs
+		}
+		if !r.exists {
+			r.content = s.Config.GenerateSpawnPoints(in.Professions)
+			return r, nil
+		}
+		var err error
+		r.content, err = s.Config.UpdateSpawnPoints(r.original, in.Professions)
+		if err != nil {
+			return r, fmt.Errorf("update spawn points: %w", err)
+		}
+	}
+	return r, nil
+}
+
+func (s Service) configPath(kind SaveKind) string {
+	serverName := s.resolvedServerName()
+	switch kind {
+	case KindServer:
+		return filepath.Join(s.BaseDataDir, "Server", serverName+".ini")
+	case KindSandbox:
+		return filepath.Join(s.BaseDataDir, "Server", serverName+"_SandboxVars.lua")
+	case KindSpawnRegions:
+		return filepath.Join(s.BaseDataDir, "Server", serverName+"_spawnregions.lua")
+	case KindSpawnPoints:
+		return filepath.Join(s.BaseDataDir, "Server", serverName+"_spawnpoints.lua")
+	}
+	return ""
+}
+
 func (s Service) resolvedServerName() string {
 	return ResolveServerName(s.FS, s.BaseDataDir, s.ServerName)
 }
@@ -1,9 +1,14 @@
 package configapp
 
 import (
+	"errors"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"pz-web-backend/internal/config"
 	"pz-web-backend/internal/infra/fs"
 )
 
@@ -51,3 +56,216 @@ func TestResolveServerName_SingleIni(t *testing.T) {
 		t.Fatalf("got=%q", got)
 	}
 }
+
+func TestService_Save_ServerPreservesComments(t *testing.T) {
+	base := t.TempDir()
+	serverDir := filepath.Join(base, "Server")
+	osfs := fs.OSFS{}
+	if err := osfs.MkdirAll(serverDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	iniPath := filepath.Join(serverDir, "servertest.ini")
+	original := "# Players can hurt each other\nPVP=false\n\n# Public server\nPublic=false\n"
+	if err := osfs.WriteFile(iniPath, []byte(original), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	svc := Service{BaseDataDir: base, ServerName: "servertest", DevMode: true, FS: osfs}
+	items := []config.Item{
+		{Key: "PVP", Value: "true"},
+		{Key: "Public", Value: "false"},
+	}
+	if _, err := svc.Save(SaveInput{Kind: KindServer, Items: items}); !errors.Is(err, ErrETagRequired) {
+		t.Fatalf("Save without etag: %v", err)
+	}
+	if _, err := svc.Save(SaveInput{Kind: KindServer, Items: items, Force: true}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	got, err := osfs.ReadFile(iniPath)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	want := "# Players can hurt each other\nPVP=true\n\n# Public server\nPublic=false\n"
+	if string(got) != want {
+		t.Fatalf("got=%q want %q", got, want)
+	}
+}
+
+func TestService_Save_ConflictWhenFileChanged(t *testing.T) {
+	base := t.TempDir()
+	osfs := fs.OSFS{}
+	iniPath := filepath.Join(base, "Server", "servertest.ini")
+	if err := osfs.MkdirAll(filepath.Dir(iniPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := osfs.WriteFile(iniPath, []byte("PVP=false\n"), 0o640); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	svc := Service{BaseDataDir: base, ServerName: "servertest", DevMode: true, FS: osfs}
+	loaded := ContentETag([]byte("PVP=false\n"))
+
+	// 另一位管理员先保存。
+	if err := osfs.WriteFile(iniPath, []byte("PVP=true\n"), 0o640); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	_, err := svc.Save(SaveInput{Kind: KindServer, Items: []config.Item{{Key: "PVP", Value: "false"}}, ETag: loaded})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("err=%v", err)
+	}
+	if conflict.CurrentETag != ContentETag([]byte("PVP=true\n")) || !strings.Contains(conflict.Diff, "+PVP=false") {
+		t.Fatalf("conflict=%+v", conflict)
+	}
+
+	etag, err := svc.Save(SaveInput{Kind: KindServer, Items: []config.Item{{Key: "PVP", Value: "false"}}, ETag: conflict.CurrentETag})
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if etag != ContentETag([]byte("PVP=false\n")) {
+		t.Fatalf("etag=%q", etag)
+	}
+	info, err := os.Stat(iniPath)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if info.Mode().Perm() != 0o640 {
+		t.Fatalf("mode=%v", info.Mode().Perm())
+	}
+}
+
+// slowReadFS 读文件后停顿，让并发保存在没有锁时一定都先读到旧文件。
+type slowReadFS struct{ fs.OSFS }
+
+func (f slowReadFS) ReadFile(name string) ([]byte, error) {
+	data, err := f.OSFS.ReadFile(name)
+	time.Sleep(20 * time.Millisecond)
+	return data, err
+}
+
+func TestService_Save_ConcurrentSameETag(t *testing.T) {
+	base := t.TempDir()
+	iniPath := filepath.Join(base, "Server", "servertest.ini")
+	if err := os.MkdirAll(filepath.Dir(iniPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(iniPath, []byte("PublicName=a\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	svc := Service{BaseDataDir: base, ServerName: "servertest", DevMode: true, FS: slowReadFS{}}
+	loaded := ContentETag([]byte("PublicName=a\n"))
+
+	errs := make(chan error, 2)
+	for _, name := range []string{"b", "c"} {
+		go func() {
+			_, err := svc.Save(SaveInput{Kind: KindServer, Items: []config.Item{{Key: "PublicName", Value: name}}, ETag: loaded})
+			errs <- err
+		}()
+	}
+	var conflicts int
+	for i := 0; i < 2; i++ {
+		err := <-errs
+		var conflict *ConflictError
+		switch {
+		case errors.As(err, &conflict):
+			conflicts++
+		case err != nil:
+			t.Fatalf("Save: %v", err)
+		}
+	}
+	if conflicts != 1 {
+		t.Fatalf("conflicts=%d", conflicts)
+	}
+}
+
+func TestService_WorkshopItems(t *testing.T) {
+	base := t.TempDir()
+	osfs := fs.OSFS{}
+	_ = osfs.MkdirAll(filepath.Join(base, "Server"), 0o755)
+	if err := osfs.WriteFile(filepath.Join(base, "Server", "servertest.ini"), []byte("WorkshopItems=111; 222;;111\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	svc := Service{BaseDataDir: base, ServerName: "servertest", FS: osfs}
+	ids, err := svc.WorkshopItems()
+	if err != nil || strings.Join(ids, ",") != "111,222" {
+		t.Fatalf("ids=%v err=%v", ids, err)
+	}
+}
+
+func TestService_SaveModLines(t *testing.T) {
+	base := t.TempDir()
+	osfs := fs.OSFS{}
+	_ = osfs.MkdirAll(filepath.Join(base, "Server"), 0o755)
+	iniPath := filepath.Join(base, "Server", "servertest.ini")
+	if err := osfs.WriteFile(iniPath, []byte("PVP=true\nMods=a;b;a\nWorkshopItems=1\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	svc := Service{BaseDataDir: base, ServerName: "servertest", DevMode: true, FS: osfs}
+	lines, err := svc.ModLines()
+	if err != nil || strings.Join(lines.Mods, ",") != "a,b" || strings.Join(lines.WorkshopItems, ",") != "1" || lines.ETag == "" {
+		t.Fatalf("lines=%+v err=%v", lines, err)
+	}
+	etag, err := svc.SaveModLines(ModLinesInput{Mods: []string{"b", "c"}, WorkshopItems: []string{"1", "2"}, ETag: lines.ETag})
+	if err != nil {
+		t.Fatalf("SaveModLines: %v", err)
+	}
+	got, _ := osfs.ReadFile(iniPath)
+	if string(got) != "PVP=true\nMods=b;c\nWorkshopItems=1;2\n" || etag != ContentETag(got) {
+		t.Fatalf("got=%q", got)
+	}
+	var conflict *ConflictError
+	if _, err := svc.SaveModLines(ModLinesInput{Mods: []string{"x"}, ETag: lines.ETag}); !errors.As(err, &conflict) {
+		t.Fatalf("err=%v", err)
+	}
+}
+
+func TestService_Save_SandboxFloatStaysFloatAfterIntegerSave(t *testing.T) {
+	base := t.TempDir()
+	osfs := fs.OSFS{}
+	luaPath := filepath.Join(base, "Server", "servertest_SandboxVars.lua")
+	if err := osfs.MkdirAll(filepath.Dir(luaPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	original := []byte("SandboxVars = {\n    VERSION = 6,\n    FoodLootNew = 0.6,\n}\n")
+	if err := osfs.WriteFile(luaPath, original, 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	svc := Service{BaseDataDir: base, ServerName: "servertest", DevMode: true, FS: osfs}
+	etag, err := svc.Save(SaveInput{Kind: KindSandbox, Items: []config.Item{{Key: "FoodLootNew", Value: "1"}}, ETag: ContentETag(original)})
+	if err != nil {
+		t.Fatalf("Save 1: %v", err)
+	}
+	if _, err := svc.Save(SaveInput{Kind: KindSandbox, Items: []config.Item{{Key: "FoodLootNew", Value: "1.5"}}, ETag: etag}); err != nil {
+		t.Fatalf("Save 1.5: %v", err)
+	}
+	got, _ := osfs.ReadFile(luaPath)
+	if !strings.Contains(string(got), "FoodLootNew = 1.5,") {
+		t.Fatalf("got=%q", got)
+	}
+}
+
+func TestService_Save_ValidatesWhenFileMissing(t *testing.T) {
+	base := t.TempDir()
+	osfs := fs.OSFS{}
+	svc := Service{BaseDataDir: base, ServerName: "servertest", DevMode: true, FS: osfs}
+
+	var invalid config.ValidationErrors
+	_, err := svc.Save(SaveInput{Kind: KindServer, Items: []config.Item{{Key: "MaxPlayers", Value: "500"}}, ETag: AbsentETag})
+	if !errors.As(err, &invalid) || invalid[0].Key != "MaxPlayers" {
+		t.Fatalf("server err=%v", err)
+	}
+	_, err = svc.Save(SaveInput{Kind: KindSandbox, Items: []config.Item{{Key: "StarterKit", Value: "maybe"}}, ETag: AbsentETag})
+	if !errors.As(err, &invalid) || invalid[0].Key != "StarterKit" {
+		t.Fatalf("sandbox err=%v", err)
+	}
+	for _, kind := range []SaveKind{KindServer, KindSandbox} {
+		if _, err := os.Stat(svc.configPath(kind)); !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("%s written: %v", kind, err)
+		}
+	}
+}
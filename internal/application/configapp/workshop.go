@@ -0,0 +1,75 @@
+package configapp
+
+import (
+	"strings"
+
+	"pz-web-backend/internal/config"
+)
+
+// ModLines 服务器 INI 中的 Mods= 与 WorkshopItems=（按分号拆分、去重并保持顺序）及文件的 ETag。
+type ModLines struct {
+	Mods          []string `json:"mods"`
+	WorkshopItems []string `json:"workshop_items"`
+	ETag          string   `json:"etag"`
+}
+
+// ModLinesInput 只改写 Mods= 与 WorkshopItems=，其余配置不变。
+type ModLinesInput struct {
+	Mods          []string
+	WorkshopItems []string
+	ETag          string
+	Author        string
+	Note          string
+	Restart       bool
+}
+
+// WorkshopItems 返回服务器 INI 中 WorkshopItems= 列出的创意工坊 ID（去重并保持顺序）。
+func (s Service) WorkshopItems() ([]string, error) {
+	doc, err := s.serverDocument()
+	if err != nil {
+		return nil, err
+	}
+	value, _ := doc.Get("WorkshopItems")
+	return splitList(value), nil
+}
+
+func (s Service) ModLines() (ModLines, error) {
+	data, err := s.FS.ReadFile(s.configPath(KindServer))
+	if err != nil {
+		return ModLines{}, err
+	}
+	doc := config.ParseINIDocument(data)
+	mods, _ := doc.Get("Mods")
+	items, _ := doc.Get("WorkshopItems")
+	return ModLines{Mods: splitList(mods), WorkshopItems: splitList(items), ETag: ContentETag(data)}, nil
+}
+
+// SaveModLines 与 Save 走同一写盘路径（ETag 校验、历史快照、可选重启），返回新 ETag。
+func (s Service) SaveModLines(in ModLinesInput) (string, error) {
+	save := SaveInput{
+		Kind: KindServer,
+		Items: []config.Item{
+			{Key: "Mods", Value: strings.Join(splitList(strings.Join(in.Mods, ";")), ";")},
+			{Key: "WorkshopItems", Value: strings.Join(splitList(strings.Join(in.WorkshopItems, ";")), ";")},
+		},
+		Restart: in.Restart,
+		Author:  in.Author,
+		Note:    in.Note,
+		ETag:    in.ETag,
+	}
+	return s.Save(save)
+}
+
+func splitList(value string) []string {
+	seen := make(map[string]bool)
+	out := []string{}
+	for _, id := range strings.Split(value, ";") {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		out = append(out, id)
+	}
+	return out
+}
@@ -14,9 +14,11 @@ type readDirErrFS struct {
 	err error
 }
 
-func (readDirErrFS) MkdirAll(string, os.FileMode) error          { return nil }
-func (readDirErrFS) WriteFile(string, []byte, os.FileMode) error { return nil }
-func (e readDirErrFS) ReadDir(string) ([]os.DirEntry, error)     { return nil, e.err }
+func (readDirErrFS) MkdirAll(string, os.FileMode) error                { return nil }
+func (readDirErrFS) ReadFile(string) ([]byte, error)                   { return nil, nil }
+func (readDirErrFS) WriteFile(string, []byte, os.FileMode) error       { return nil }
+func (readDirErrFS) WriteFileAtomic(string, []byte, os.FileMode) error { return nil }
+func (e readDirErrFS) ReadDir(string) ([]os.DirEntry, error)           { return nil, e.err }
 
 func TestService_Get_SortsLanguages_CN_EN_First(t *testing.T) {
 	base := t.TempDir()
@@ -0,0 +1,273 @@
+package logapp
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"pz-web-backend/internal/pzlog"
+)
+
+var ErrInvalidCursor = errors.New("invalid or expired log cursor")
+
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+// Query 检索条件，零值字段不参与过滤。
+// 没有行头的行（堆栈等）沿用上一条带行头的行的时间与级别。
+type Query struct {
+	From time.Time
+	To   time.Time
+	// Level 最低级别；Logs 目录下不带级别的文件（user.txt 等）在指定级别时不会命中。
+	Level   pzlog.Level
+	Pattern *regexp.Regexp
+	// Player 玩家名或 SteamID，不区分大小写的子串匹配。
+	Player string
+	// File 只检索该文件（File.ID）。
+	File   string
+	Cursor string
+	Limit  int
+}
+
+// Match 一行命中结果；Line 从 1 开始。
+type Match struct {
+	File      string      `json:"file"`
+	Line      int         `json:"line"`
+	Time      time.Time   `json:"time"`
+	Level     pzlog.Level `json:"level,omitempty"`
+	Subsystem string      `json:"subsystem,omitempty"`
+	Text      string      `json:"text"`
+}
+
+type Result struct {
+	Matches []Match `json:"matches"`
+	// NextCursor 为空表示没有更多结果。
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// position 游标：某文件中一行的起点，以及读到这里时继承的时间与级别。
+type position struct {
+	File   string      `json:"f"`
+	Offset int64       `json:"o"`
+	Line   int         `json:"l"`
+	Time   int64       `json:"t,omitempty"`
+	Level  pzlog.Level `json:"v,omitempty"`
+}
+
+func (p position) encode() string {
+	data, _ := json.Marshal(p)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string) (position, error) {
+	var p position
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || json.Unmarshal(data, &p) != nil || p.File == "" || p.Offset < 0 {
+		return position{}, ErrInvalidCursor
+	}
+	return p, nil
+}
+
+// Search 按文件顺序（见 Files）返回最多 Limit 条结果。
+// 游标记录文件 ID 与偏移，文件在两次请求之间被轮转改名时返回 ErrInvalidCursor。
+func (s Service) Search(q Query) (Result, error) {
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+	res := Result{Matches: []Match{}}
+	err := s.scan(q, func(m Match, at position) bool {
+		if len(res.Matches) == limit {
+			// 多读一条确认还有结果，游标指向这一条。
+			res.NextCursor = at.encode()
+			return false
+		}
+		res.Matches = append(res.Matches, m)
+		return true
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	return res, nil
+}
+
+// Export 把命中的行写入 w，不分页。只指定了文件时原样输出整个文件；
+// 跨多个文件时每个文件前写一行 "==> ID <=="。
+func (s Service) Export(w io.Writer, q Query) error {
+	if q.File != "" && q.From.IsZero() && q.To.IsZero() && q.Level == "" && q.Pattern == nil && q.Player == "" {
+		f, err := s.File(q.File)
+		if err != nil {
+			return err
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+		_, err = io.Copy(w, rc)
+		return err
+	}
+
+	q.Cursor = ""
+	bw := bufio.NewWriter(w)
+	current := ""
+	var werr error
+	err := s.scan(q, func(m Match, _ position) bool {
+		if q.File == "" && m.File != current {
+			current = m.File
+			if _, werr = fmt.Fprintf(bw, "==> %s <==\n", m.File); werr != nil {
+				return false
+			}
+		}
+		if _, werr = bw.WriteString(m.Text + "\n"); werr != nil {
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if werr != nil {
+		return werr
+	}
+	return bw.Flush()
+}
+
+// scan 依次读取文件，对每条命中调用 fn（at 为该行起点），fn 返回 false 时停止。
+func (s Service) scan(q Query, fn func(m Match, at position) bool) error {
+	files, err := s.Files()
+	if err != nil {
+		return err
+	}
+	if q.File != "" {
+		var only []File
+		for _, f := range files {
+			if f.ID == q.File {
+				only = append(only, f)
+			}
+		}
+		if only == nil {
+			return ErrFileNotFound
+		}
+		files = only
+	}
+
+	start := position{}
+	if q.Cursor != "" {
+		if start, err = decodeCursor(q.Cursor); err != nil {
+			return err
+		}
+		idx := -1
+		for i, f := range files {
+			if f.ID == start.File {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return ErrInvalidCursor
+		}
+		files = files[idx:]
+	}
+
+	player := strings.ToLower(q.Player)
+	for i, f := range files {
+		// 最后修改早于起始时间的文件不可能有命中。
+		if !q.From.IsZero() && f.ModTime.Before(q.From) {
+			continue
+		}
+		pos := position{File: f.ID}
+		if i == 0 && q.Cursor != "" {
+			pos = start
+		}
+		more, err := s.scanFile(f, pos, func(m Match, at position) bool {
+			if !q.From.IsZero() && (m.Time.IsZero() || m.Time.Before(q.From)) {
+				return true
+			}
+			if !q.To.IsZero() && (m.Time.IsZero() || m.Time.After(q.To)) {
+				return true
+			}
+			if q.Level != "" && !m.Level.AtLeast(q.Level) {
+				return true
+			}
+			if player != "" && !strings.Contains(strings.ToLower(m.Text), player) {
+				return true
+			}
+			if q.Pattern != nil && !q.Pattern.MatchString(m.Text) {
+				return true
+			}
+			return fn(m, at)
+		})
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+	}
+	return nil
+}
+
+// scanFile 从 pos 开始逐行读取；返回 false 表示 fn 要求停止。
+func (s Service) scanFile(f File, pos position, fn func(m Match, at position) bool) (bool, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return false, err
+	}
+	defer rc.Close()
+	if pos.Offset > 0 {
+		if seeker, ok := rc.(io.Seeker); ok {
+			_, err = seeker.Seek(pos.Offset, io.SeekStart)
+		} else {
+			_, err = io.CopyN(io.Discard, rc, pos.Offset)
+		}
+		if err != nil {
+			return false, ErrInvalidCursor
+		}
+	}
+
+	br := bufio.NewReaderSize(rc, 64*1024)
+	for {
+		raw, err := br.ReadString('\n')
+		if raw != "" {
+			at := pos
+			pos.Offset += int64(len(raw))
+			pos.Line++
+			text := strings.TrimRight(raw, "\r\n")
+			line := pzlog.ParseLine(text, s.Location)
+			if line.HasHeader() {
+				pos.Level = line.Level
+				if !line.Time.IsZero() {
+					pos.Time = line.Time.UnixMilli()
+				}
+			}
+			m := Match{File: f.ID, Line: pos.Line, Level: pos.Level, Text: text}
+			if pos.Time != 0 {
+				m.Time = time.UnixMilli(pos.Time)
+			}
+			if line.HasHeader() {
+				m.Subsystem = line.Subsystem
+			}
+			if !fn(m, at) {
+				return false, nil
+			}
+		}
+		if err == io.EOF {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package logapp
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"pz-web-backend/internal/pzlog"
+)
+
+func newSearchService(t *testing.T) Service {
+	dir := t.TempDir()
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	ms := func(h int) string { return strconv.FormatInt(day.Add(time.Duration(h)*time.Hour).UnixMilli(), 10) }
+	logPath := filepath.Join(dir, "pz-stdout.log")
+	writeLog(t, logPath+".1", strings.Join([]string{
+		"LOG  : General     , " + ms(1) + "> 1> Server started",
+		"ERROR: General     , " + ms(2) + "> 2> DebugLogStream.printException> Stack trace:",
+		"java.lang.NullPointerException",
+		"\tat zombie.Foo.bar(Foo.java:1)",
+		"LOG  : Network     , " + ms(3) + "> 3> ConnectionManager: [fully-connected] username=\"Griefer\"",
+	}, "\n")+"\n", day.Add(3*time.Hour))
+	writeLog(t, logPath, strings.Join([]string{
+		"LOG  : General     , " + ms(5) + "> 5> griefer destroyed a wall",
+		"WARN : Network     , " + ms(6) + "> 6> ConnectionManager: [disconnect] username=\"Griefer\"",
+	}, "\n")+"\n", day.Add(6*time.Hour))
+	writeLog(t, filepath.Join(dir, "Logs", "01-05-24_00-00_user.txt"),
+		"[01-05-24 04:00:00.000] 76561198000000001 \"Griefer\" fully connected (1,1,0).\n", day.Add(4*time.Hour))
+	return Service{LogPath: logPath, LogsDir: filepath.Join(dir, "Logs"), Location: time.UTC}
+}
+
+func texts(ms []Match) string {
+	var out []string
+	for _, m := range ms {
+		out = append(out, m.File+"#"+strconv.Itoa(m.Line))
+	}
+	return strings.Join(out, ",")
+}
+
+func TestService_SearchFilters(t *testing.T) {
+	s := newSearchService(t)
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	res, err := s.Search(Query{Player: "griefer"})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if got := texts(res.Matches); got != "console/pz-stdout.log.1#5,console/pz-stdout.log#1,console/pz-stdout.log#2,Logs/01-05-24_00-00_user.txt#1" {
+		t.Fatalf("player=%s", got)
+	}
+	if m := res.Matches[3]; !m.Time.Equal(day.Add(4 * time.Hour)) {
+		t.Fatalf("user log time=%v", m.Time)
+	}
+
+	// 堆栈行继承 ERROR 级别与时间。
+	res, _ = s.Search(Query{Level: pzlog.LevelWarn})
+	if got := texts(res.Matches); got != "console/pz-stdout.log.1#2,console/pz-stdout.log.1#3,console/pz-stdout.log.1#4,console/pz-stdout.log#2" {
+		t.Fatalf("level=%s", got)
+	}
+	if res.Matches[2].Level != pzlog.LevelError || !res.Matches[2].Time.Equal(day.Add(2*time.Hour)) {
+		t.Fatalf("stack=%+v", res.Matches[2])
+	}
+
+	res, _ = s.Search(Query{From: day.Add(150 * time.Minute), To: day.Add(5 * time.Hour), Pattern: regexp.MustCompile(`(?i)griefer`)})
+	if got := texts(res.Matches); got != "console/pz-stdout.log.1#5,console/pz-stdout.log#1,Logs/01-05-24_00-00_user.txt#1" {
+		t.Fatalf("window=%s", got)
+	}
+
+	res, _ = s.Search(Query{File: "console/pz-stdout.log", Pattern: regexp.MustCompile(`disconnect`)})
+	if got := texts(res.Matches); got != "console/pz-stdout.log#2" {
+		t.Fatalf("file=%s", got)
+	}
+	if _, err := s.Search(Query{File: "nope"}); !errors.Is(err, ErrFileNotFound) {
+		t.Fatalf("err=%v", err)
+	}
+}
+
+func TestService_SearchPaginatesWithCursor(t *testing.T) {
+	s := newSearchService(t)
+	var all []string
+	cursor := ""
+	pages := 0
+	for {
+		res, err := s.Search(Query{Level: pzlog.LevelLog, Cursor: cursor, Limit: 3})
+		if err != nil {
+			t.Fatalf("Search: %v", err)
+		}
+		pages++
+		all = append(all, texts(res.Matches))
+		if res.NextCursor == "" {
+			break
+		}
+		cursor = res.NextCursor
+	}
+	if pages != 3 || strings.Join(all, "|") != "console/pz-stdout.log.1#1,console/pz-stdout.log.1#2,console/pz-stdout.log.1#3|"+
+		"console/pz-stdout.log.1#4,console/pz-stdout.log.1#5,console/pz-stdout.log#1|console/pz-stdout.log#2" {
+		t.Fatalf("pages=%d all=%v", pages, all)
+	}
+	if _, err := s.Search(Query{Cursor: "garbage!"}); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("err=%v", err)
+	}
+}
+
+func TestService_SearchCursorSurvivesAppends(t *testing.T) {
+	s := newSearchService(t)
+	full, err := s.Search(Query{Limit: MaxLimit})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	first, err := s.Search(Query{Limit: 4})
+	if err != nil || first.NextCursor == "" {
+		t.Fatalf("first=%+v err=%v", first, err)
+	}
+
+	// 翻页期间旧文件被写入，修改时间变成最新。
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(s.LogPath+".1", later, later); err != nil {
+		t.Fatal(err)
+	}
+	rest, err := s.Search(Query{Limit: MaxLimit, Cursor: first.NextCursor})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if got := texts(first.Matches) + "," + texts(rest.Matches); got != texts(full.Matches) {
+		t.Fatalf("got=%s want=%s", got, texts(full.Matches))
+	}
+}
+
+func TestService_Export(t *testing.T) {
+	s := newSearchService(t)
+	var b strings.Builder
+	if err := s.Export(&b, Query{Player: "griefer"}); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	out := b.String()
+	if strings.Count(out, "==> ") != 3 || !strings.Contains(out, "==> Logs/01-05-24_00-00_user.txt <==\n[01-05-24 04:00:00.000]") {
+		t.Fatalf("out=%q", out)
+	}
+
+	b.Reset()
+	if err := s.Export(&b, Query{File: "console/pz-stdout.log"}); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if strings.Count(b.String(), "\n") != 2 || strings.Contains(b.String(), "==>") {
+		t.Fatalf("out=%q", b.String())
+	}
+}
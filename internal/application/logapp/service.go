@@ -0,0 +1,200 @@
+// Package logapp 在当前与历史日志中检索、导出。
+package logapp
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrFileNotFound = errors.New("log file not found")
+
+const (
+	// KindConsole 服务器控制台输出（supervisord / direct 模式的 stdout 日志）。
+	KindConsole = "console"
+	// KindRotated 控制台日志轮转出的旧文件（.1、.2.gz 等）。
+	KindRotated = "rotated"
+	// KindSession Zomboid/Logs 下按会话生成的文件（DebugLog-server、user、chat 等）。
+	KindSession = "session"
+)
+
+// Service LogPath 为控制台日志，LogsDir 为 Zomboid/Logs，任一为空时跳过对应来源。
+type Service struct {
+	LogPath string
+	LogsDir string
+	// Location Logs 目录下方括号时间的时区，nil 为面板所在时区。
+	Location *time.Location
+}
+
+// File 一个可检索的日志文件；ID 在列表内唯一，用于指定文件与分页游标。
+type File struct {
+	ID      string    `json:"id"`
+	Kind    string    `json:"kind"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+
+	path string
+	// rotation 轮转文件的序号，越大越旧；不是数字时为 -1。
+	rotation int
+	// named Logs 目录下文件名开头的时间，没有时为零值。
+	named time.Time
+}
+
+// Files 检索也按这个顺序进行：控制台轮转文件从旧到新（.2 在 .1 之前）、当前控制台日志，
+// 再是 Logs 目录下的文件按文件名中的时间从旧到新。顺序不依赖修改时间，分页期间文件被追加写入时游标仍然有效。
+func (s Service) Files() ([]File, error) {
+	var files []File
+	if s.LogPath != "" {
+		if info, err := os.Stat(s.LogPath); err == nil && info.Mode().IsRegular() {
+			files = append(files, newFile("console/"+filepath.Base(s.LogPath), KindConsole, s.LogPath, info))
+		}
+		rotated, err := filepath.Glob(globEscape(s.LogPath) + ".*")
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range rotated {
+			if info, err := os.Stat(p); err == nil && info.Mode().IsRegular() {
+				files = append(files, newFile("console/"+filepath.Base(p), KindRotated, p, info))
+			}
+		}
+	}
+	if s.LogsDir != "" {
+		err := filepath.WalkDir(s.LogsDir, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if p == s.LogsDir && errors.Is(err, fs.ErrNotExist) {
+					return fs.SkipAll
+				}
+				return err
+			}
+			if d.IsDir() || !isTextLog(d.Name()) {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			rel, _ := filepath.Rel(s.LogsDir, p)
+			files = append(files, newFile("Logs/"+filepath.ToSlash(rel), KindSession, p, info))
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.SliceStable(files, func(i, j int) bool { return files[i].before(files[j]) })
+	if files == nil {
+		files = []File{}
+	}
+	return files, nil
+}
+
+// File 按 ID 查找；只能取到 Files 列出的文件。
+func (s Service) File(id string) (File, error) {
+	files, err := s.Files()
+	if err != nil {
+		return File{}, err
+	}
+	for _, f := range files {
+		if f.ID == id {
+			return f, nil
+		}
+	}
+	return File{}, ErrFileNotFound
+}
+
+// Open 返回解压后的内容；.gz 文件透明解压。
+func (f File) Open() (io.ReadCloser, error) {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(f.path, ".gz") {
+		return file, nil
+	}
+	zr, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return gzipFile{Reader: zr, file: file}, nil
+}
+
+// Compressed 文件本身是否为 gzip。
+func (f File) Compressed() bool {
+	return strings.HasSuffix(f.path, ".gz")
+}
+
+type gzipFile struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (g gzipFile) Close() error {
+	g.Reader.Close()
+	return g.file.Close()
+}
+
+func newFile(id, kind, path string, info os.FileInfo) File {
+	f := File{ID: id, Kind: kind, Size: info.Size(), ModTime: info.ModTime(), path: path, rotation: -1}
+	switch kind {
+	case KindRotated:
+		// pz-stdout.log.2.gz → 2
+		suffix := strings.TrimPrefix(filepath.Ext(strings.TrimSuffix(filepath.Base(path), ".gz")), ".")
+		if n, err := strconv.Atoi(suffix); err == nil {
+			f.rotation = n
+		}
+	case KindSession:
+		f.named = sessionTime(filepath.Base(path))
+	}
+	return f
+}
+
+var kindOrder = map[string]int{KindRotated: 0, KindConsole: 1, KindSession: 2}
+
+// before 排序规则见 Files；无法排出先后时按 ID。
+func (f File) before(o File) bool {
+	if f.Kind != o.Kind {
+		return kindOrder[f.Kind] < kindOrder[o.Kind]
+	}
+	switch {
+	case f.rotation != o.rotation && f.rotation >= 0 && o.rotation >= 0:
+		return f.rotation > o.rotation
+	case !f.named.Equal(o.named) && !f.named.IsZero() && !o.named.IsZero():
+		return f.named.Before(o.named)
+	case f.named.IsZero() != o.named.IsZero():
+		// 文件名中没有时间的排在最后。
+		return o.named.IsZero()
+	}
+	return f.ID < o.ID
+}
+
+// sessionTime 解析 PZ 会话日志文件名开头的时间，例如 01-05-24_04-00_chat.txt、01-05-24_04-00-30_DebugLog-server.txt。
+func sessionTime(name string) time.Time {
+	for _, layout := range []string{"02-01-06_15-04-05_", "02-01-06_15-04_"} {
+		if len(name) < len(layout) {
+			continue
+		}
+		if t, err := time.Parse(layout, name[:len(layout)]); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func isTextLog(name string) bool {
+	name = strings.TrimSuffix(name, ".gz")
+	return strings.HasSuffix(name, ".txt") || strings.HasSuffix(name, ".log")
+}
+
+// globEscape 转义路径中的通配符，日志路径本身不应被当作模式。
+func globEscape(p string) string {
+	r := strings.NewReplacer(`*`, `\*`, `?`, `\?`, `[`, `\[`)
+	return r.Replace(p)
+}
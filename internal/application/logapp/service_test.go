@@ -0,0 +1,84 @@
+package logapp
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeLog(t *testing.T, path, content string, mod time.Time) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if strings.HasSuffix(path, ".gz") {
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		zw := gzip.NewWriter(f)
+		io.WriteString(zw, content)
+		zw.Close()
+		f.Close()
+	} else if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, mod, mod); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestService_FilesListsAllSourcesInStableOrder(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	logPath := filepath.Join(dir, "pz-stdout.log")
+	writeLog(t, logPath, "now\n", base.Add(3*time.Hour))
+	writeLog(t, logPath+".1", "older\n", base.Add(2*time.Hour))
+	writeLog(t, logPath+".2.gz", "oldest\n", base.Add(time.Hour))
+	logsDir := filepath.Join(dir, "Logs")
+	writeLog(t, filepath.Join(logsDir, "logs_01-05-24", "01-05-24_00-00_user.txt"), "archived\n", base)
+	writeLog(t, filepath.Join(logsDir, "01-05-24_04-00_chat.txt"), "chat\n", base.Add(4*time.Hour))
+	writeLog(t, filepath.Join(logsDir, "coop-console.zip"), "skip", base)
+
+	s := Service{LogPath: logPath, LogsDir: logsDir}
+	files, err := s.Files()
+	if err != nil {
+		t.Fatalf("Files: %v", err)
+	}
+	var ids []string
+	for _, f := range files {
+		ids = append(ids, f.ID+":"+f.Kind)
+	}
+	want := "console/pz-stdout.log.2.gz:rotated,console/pz-stdout.log.1:rotated,console/pz-stdout.log:console," +
+		"Logs/logs_01-05-24/01-05-24_00-00_user.txt:session,Logs/01-05-24_04-00_chat.txt:session"
+	if strings.Join(ids, ",") != want {
+		t.Fatalf("ids=%v", ids)
+	}
+
+	f, err := s.File("console/pz-stdout.log.2.gz")
+	if err != nil {
+		t.Fatalf("File: %v", err)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	data, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(data) != "oldest\n" {
+		t.Fatalf("data=%q", data)
+	}
+	if _, err := s.File("../etc/passwd"); err != ErrFileNotFound {
+		t.Fatalf("err=%v", err)
+	}
+
+	// 目录不存在时视为没有文件。
+	empty, err := Service{LogsDir: filepath.Join(dir, "missing")}.Files()
+	if err != nil || len(empty) != 0 {
+		t.Fatalf("files=%v err=%v", empty, err)
+	}
+}
@@ -0,0 +1,82 @@
+package modsapp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CollectionItem 合集中的一个创意工坊条目；Error 非空时没有查到其中的模组。
+type CollectionItem struct {
+	WorkshopID string   `json:"workshop_id"`
+	Name       string   `json:"name"`
+	Mods       []string `json:"mods"`
+	Source     string   `json:"source"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// CollectionImport 展开合集得到的条目与模组，WorkshopItems / Mods 可直接追加到服务器配置。
+type CollectionImport struct {
+	CollectionID string           `json:"collection_id"`
+	Collections  []string         `json:"collections"`
+	Items        []CollectionItem `json:"items"`
+	// Unavailable 查询失败的子合集。
+	Unavailable       []string `json:"unavailable"`
+	WorkshopItems     []string `json:"workshop_items"`
+	Mods              []string `json:"mods"`
+	WorkshopItemsLine string   `json:"workshop_items_line"`
+	ModsLine          string   `json:"mods_line"`
+	// Unresolved 没能确定 ModID 的条目（查询失败，或 Steam 描述中没有 "Mod ID:"），需要手动补充。
+	Unresolved []string `json:"unresolved"`
+}
+
+// ImportCollection 展开 Steam 合集（含子合集），再批量查询每个条目提供的模组。
+func (s Service) ImportCollection(collectionID string) (CollectionImport, error) {
+	if s.Workshop == nil {
+		return CollectionImport{}, fmt.Errorf("workshop fetcher not configured")
+	}
+	contents, err := s.Workshop.FetchCollection(collectionID)
+	if err != nil {
+		return CollectionImport{}, err
+	}
+	results, err := s.Lookup(contents.Items)
+	if err != nil {
+		return CollectionImport{}, err
+	}
+
+	out := CollectionImport{
+		CollectionID:  contents.ID,
+		Collections:   contents.Collections,
+		Items:         make([]CollectionItem, 0, len(results)),
+		Unavailable:   contents.Unavailable,
+		WorkshopItems: append([]string{}, contents.Items...),
+		Mods:          []string{},
+		Unresolved:    []string{},
+	}
+	modSet := newOrderedSet(nil)
+	for _, r := range results {
+		item := CollectionItem{WorkshopID: r.WorkshopID, Mods: []string{}, Source: r.Source}
+		if r.Err != nil {
+			item.Error = r.Err.Error()
+		}
+		for _, m := range r.Mods {
+			if item.Name == "" {
+				item.Name = m.Name
+			}
+			// Steam 描述中的多个 "Mod ID:" 以逗号连接，无法识别时为 "?"。
+			for _, id := range strings.Split(m.ModID, ",") {
+				if id = strings.TrimLeft(strings.TrimSpace(id), `\`); id != "" && id != "?" {
+					item.Mods = append(item.Mods, id)
+					modSet.add(id)
+				}
+			}
+		}
+		if len(item.Mods) == 0 {
+			out.Unresolved = append(out.Unresolved, r.WorkshopID)
+		}
+		out.Items = append(out.Items, item)
+	}
+	out.Mods = append(out.Mods, modSet.items...)
+	out.WorkshopItemsLine = strings.Join(out.WorkshopItems, ";")
+	out.ModsLine = strings.Join(out.Mods, ";")
+	return out, nil
+}
@@ -0,0 +1,48 @@
+package modsapp
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"pz-web-backend/internal/mods"
+)
+
+func TestService_ImportCollection(t *testing.T) {
+	root := t.TempDir()
+	base := filepath.Join(root, "steamapps/workshop/content/108600/1/mods/A")
+	if err := os.MkdirAll(base, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(base, "mod.info"), []byte("id=a\nname=A\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	svc := Service{
+		InstallDir: root,
+		Workshop: stubWorkshop{
+			m: map[string]mods.ModInfo{
+				"2": {WorkshopID: "2", ModID: `\b,c`, Name: "B"},
+				"3": {WorkshopID: "3", ModID: "?", Name: "Maps"},
+			},
+			collections: map[string]mods.CollectionContents{
+				"100": {ID: "100", Items: []string{"1", "2", "3", "4"}, Collections: []string{"200"}, Unavailable: []string{}},
+			},
+		},
+	}
+	res, err := svc.ImportCollection("100")
+	if err != nil {
+		t.Fatalf("ImportCollection: %v", err)
+	}
+	if res.WorkshopItemsLine != "1;2;3;4" || res.ModsLine != "a;b;c" || strings.Join(res.Unresolved, ",") != "3,4" {
+		t.Fatalf("res=%+v", res)
+	}
+	if len(res.Items) != 4 || res.Items[0].Source != "local" || res.Items[1].Name != "B" || res.Items[3].Error == "" {
+		t.Fatalf("items=%+v", res.Items)
+	}
+
+	if _, err := svc.ImportCollection("9"); err != mods.ErrCollectionNotFound {
+		t.Fatalf("err=%v", err)
+	}
+}
@@ -0,0 +1,343 @@
+package modsapp
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"pz-web-backend/internal/application/configapp"
+	"pz-web-backend/internal/mods"
+)
+
+// ErrETagRequired 应用修复时必须传回检查报告的 ETag。
+var ErrETagRequired = errors.New("etag required: reload the health report and try again")
+
+// ModsConfig 读写服务器 INI 中的 Mods= 与 WorkshopItems=，通常是 configapp.Service。
+type ModsConfig interface {
+	ModLines() (configapp.ModLines, error)
+	SaveModLines(in configapp.ModLinesInput) (string, error)
+}
+
+type IssueKind string
+
+const (
+	// IssueModNotSubscribed Mods= 中的模组由本地某个条目提供，但该条目不在 WorkshopItems= 中。
+	IssueModNotSubscribed IssueKind = "mod_not_subscribed"
+	// IssueModNotFound Mods= 中的模组在本地所有 mod.info 中都找不到。
+	IssueModNotFound IssueKind = "mod_not_found"
+	// IssueItemUnused WorkshopItems= 中的条目已下载，但它提供的模组一个都没有启用。
+	IssueItemUnused IssueKind = "workshop_item_unused"
+	// IssueItemNotDownloaded WorkshopItems= 中的条目在本地没有下载内容。
+	IssueItemNotDownloaded IssueKind = "workshop_item_not_downloaded"
+	IssueMissingDependency IssueKind = "missing_dependency"
+	IssueIncompatible      IssueKind = "incompatible_mods"
+	// IssueModUnsupported 当前游戏版本不会加载该模组的任何变体（需配置游戏版本）。
+	IssueModUnsupported IssueKind = "mod_unsupported"
+)
+
+type FixAction string
+
+const (
+	FixAddMod             FixAction = "add_mod"
+	FixRemoveMod          FixAction = "remove_mod"
+	FixAddWorkshopItem    FixAction = "add_workshop_item"
+	FixRemoveWorkshopItem FixAction = "remove_workshop_item"
+)
+
+// Fix 可自动应用的修改。
+type Fix struct {
+	Action     FixAction `json:"action"`
+	ModID      string    `json:"mod_id,omitempty"`
+	WorkshopID string    `json:"workshop_id,omitempty"`
+}
+
+// HealthIssue 一个问题。ID 由类型与对象组成，在 INI 不变时保持稳定，用于选择要修复的问题；
+// Fixes 为空表示只能手动处理（见 Suggestion）。
+type HealthIssue struct {
+	ID         string    `json:"id"`
+	Kind       IssueKind `json:"kind"`
+	ModID      string    `json:"mod_id,omitempty"`
+	WorkshopID string    `json:"workshop_id,omitempty"`
+	Message    string    `json:"message"`
+	Suggestion string    `json:"suggestion"`
+	Fixes      []Fix     `json:"fixes,omitempty"`
+}
+
+// HealthReport ETag 为检查时服务器 INI 的 ETag，应用修复时传回以防覆盖他人的修改。
+type HealthReport struct {
+	Mods          []string      `json:"mods"`
+	WorkshopItems []string      `json:"workshop_items"`
+	ETag          string        `json:"etag"`
+	Issues        []HealthIssue `json:"issues"`
+	Fixable       int           `json:"fixable"`
+}
+
+// FixInput IssueIDs 为空时应用全部可自动修复的问题。
+type FixInput struct {
+	IssueIDs []string
+	ETag     string
+	Author   string
+	Restart  bool
+}
+
+// FixResult Applied 为实际应用的问题；没有可应用的修复时不写盘，ETag 不变。
+type FixResult struct {
+	ETag    string        `json:"etag"`
+	Applied []HealthIssue `json:"applied"`
+	Report  HealthReport  `json:"report"`
+}
+
+// Health 对照本地已下载内容检查 Mods= 与 WorkshopItems=。
+func (s Service) Health() (HealthReport, error) {
+	if s.Config == nil {
+		return HealthReport{}, fmt.Errorf("mods config not configured")
+	}
+	lines, err := s.Config.ModLines()
+	if err != nil {
+		return HealthReport{}, err
+	}
+	return s.health(lines)
+}
+
+// FixHealth 把选中问题的修复合并为一次保存。
+func (s Service) FixHealth(in FixInput) (FixResult, error) {
+	if s.Config == nil {
+		return FixResult{}, fmt.Errorf("mods config not configured")
+	}
+	lines, err := s.Config.ModLines()
+	if err != nil {
+		return FixResult{}, err
+	}
+	report, err := s.health(lines)
+	if err != nil {
+		return FixResult{}, err
+	}
+	if in.ETag == "" {
+		return FixResult{}, ErrETagRequired
+	}
+	etag := in.ETag
+
+	wanted := make(map[string]bool, len(in.IssueIDs))
+	for _, id := range in.IssueIDs {
+		wanted[id] = true
+	}
+	modList, itemList := newOrderedSet(lines.Mods), newOrderedSet(lines.WorkshopItems)
+	applied := []HealthIssue{}
+	for _, issue := range report.Issues {
+		if len(issue.Fixes) == 0 || (len(wanted) > 0 && !wanted[issue.ID]) {
+			continue
+		}
+		for _, f := range issue.Fixes {
+			switch f.Action {
+			case FixAddMod:
+				modList.add(f.ModID)
+			case FixRemoveMod:
+				modList.remove(f.ModID)
+			case FixAddWorkshopItem:
+				itemList.add(f.WorkshopID)
+			case FixRemoveWorkshopItem:
+				itemList.remove(f.WorkshopID)
+			}
+		}
+		applied = append(applied, issue)
+	}
+	if len(applied) == 0 {
+		return FixResult{ETag: lines.ETag, Applied: applied, Report: report}, nil
+	}
+
+	newETag, err := s.Config.SaveModLines(configapp.ModLinesInput{
+		Mods:          modList.items,
+		WorkshopItems: itemList.items,
+		ETag:          etag,
+		Author:        in.Author,
+		Note:          fmt.Sprintf("fix %d mod issue(s)", len(applied)),
+		Restart:       in.Restart,
+	})
+	if err != nil {
+		return FixResult{}, err
+	}
+	after, err := s.Health()
+	if err != nil {
+		return FixResult{}, err
+	}
+	return FixResult{ETag: newETag, Applied: applied, Report: after}, nil
+}
+
+func (s Service) health(lines configapp.ModLines) (HealthReport, error) {
+	var local []mods.ModInfo
+	// 还没下载过任何条目时创意工坊目录不存在，按没有本地模组处理。
+	if _, err := os.Stat(s.workshopDir()); err == nil || s.InstallDir == "" {
+		if local, err = s.ListLocalMods(); err != nil {
+			return HealthReport{}, err
+		}
+	}
+	report := HealthReport{Mods: lines.Mods, WorkshopItems: lines.WorkshopItems, ETag: lines.ETag, Issues: []HealthIssue{}}
+
+	providers := make(map[string][]string)
+	itemMods := make(map[string][]string)
+	byID := make(map[string]mods.ModInfo)
+	for _, m := range local {
+		providers[m.ModID] = append(providers[m.ModID], m.WorkshopID)
+		itemMods[m.WorkshopID] = append(itemMods[m.WorkshopID], m.ModID)
+		if _, ok := byID[m.ModID]; !ok {
+			byID[m.ModID] = m
+		}
+	}
+	// B42 的 Mods= 可能带前导反斜杠。
+	enabled := make(map[string]bool, len(lines.Mods))
+	for _, id := range lines.Mods {
+		enabled[strings.TrimLeft(id, `\`)] = true
+	}
+	listed := toSet(lines.WorkshopItems)
+
+	var notDownloaded []string
+	for _, ws := range lines.WorkshopItems {
+		if !s.itemDownloaded(ws) {
+			notDownloaded = append(notDownloaded, ws)
+		}
+	}
+
+	add := func(issue HealthIssue) {
+		report.Issues = append(report.Issues, issue)
+		if len(issue.Fixes) > 0 {
+			report.Fixable++
+		}
+	}
+
+	for _, id := range lines.Mods {
+		key := strings.TrimLeft(id, `\`)
+		ws := providers[key]
+		switch {
+		case len(ws) == 0 && len(notDownloaded) > 0:
+			add(HealthIssue{
+				ID: string(IssueModNotFound) + ":" + id, Kind: IssueModNotFound, ModID: id,
+				Message:    fmt.Sprintf("mod %q is not provided by any downloaded workshop item", id),
+				Suggestion: fmt.Sprintf("it may come from a workshop item that is not downloaded yet (%s); run a Steam update, or remove it from Mods=", strings.Join(notDownloaded, ", ")),
+			})
+		case len(ws) == 0:
+			add(HealthIssue{
+				ID: string(IssueModNotFound) + ":" + id, Kind: IssueModNotFound, ModID: id,
+				Message:    fmt.Sprintf("mod %q is not provided by any downloaded workshop item", id),
+				Suggestion: "remove it from Mods=, or add the workshop item that provides it",
+				Fixes:      []Fix{{Action: FixRemoveMod, ModID: id}},
+			})
+		case !anyIn(ws, listed):
+			add(HealthIssue{
+				ID: string(IssueModNotSubscribed) + ":" + id, Kind: IssueModNotSubscribed, ModID: id, WorkshopID: ws[0],
+				Message:    fmt.Sprintf("mod %q comes from workshop item %s, which is not in WorkshopItems=", id, ws[0]),
+				Suggestion: fmt.Sprintf("add %s to WorkshopItems=", ws[0]),
+				Fixes:      []Fix{{Action: FixAddWorkshopItem, WorkshopID: ws[0]}},
+			})
+		}
+		if m, ok := byID[key]; ok && m.Unsupported {
+			add(HealthIssue{
+				ID: string(IssueModUnsupported) + ":" + id, Kind: IssueModUnsupported, ModID: id, WorkshopID: m.WorkshopID,
+				Message:    fmt.Sprintf("mod %q has no variant that game version %s will load", id, s.GameVersion),
+				Suggestion: "update the workshop item or disable the mod",
+			})
+		}
+	}
+
+	for _, ws := range lines.WorkshopItems {
+		if !s.itemDownloaded(ws) {
+			add(HealthIssue{
+				ID: string(IssueItemNotDownloaded) + ":" + ws, Kind: IssueItemNotDownloaded, WorkshopID: ws,
+				Message:    fmt.Sprintf("workshop item %s is not downloaded", ws),
+				Suggestion: "the server downloads it on the next start; run a Steam update now, or remove it from WorkshopItems= if it was deleted",
+			})
+			continue
+		}
+		provided := itemMods[ws]
+		if len(provided) == 0 || anyIn(provided, enabled) {
+			continue
+		}
+		add(HealthIssue{
+			ID: string(IssueItemUnused) + ":" + ws, Kind: IssueItemUnused, WorkshopID: ws,
+			Message:    fmt.Sprintf("workshop item %s is subscribed but none of its mods (%s) are enabled", ws, strings.Join(provided, ", ")),
+			Suggestion: "enable one of its mods in Mods=, or remove it from WorkshopItems=",
+			Fixes:      []Fix{{Action: FixRemoveWorkshopItem, WorkshopID: ws}},
+		})
+	}
+
+	res := mods.Resolve(lines.Mods, local, mods.ResolveOptions{})
+	for _, md := range res.Missing {
+		issue := HealthIssue{
+			ID: string(IssueMissingDependency) + ":" + md.ModID, Kind: IssueMissingDependency, ModID: md.ModID, WorkshopID: md.WorkshopID,
+			Message: fmt.Sprintf("mod %q is required by %s but not enabled", md.ModID, strings.Join(md.RequiredBy, ", ")),
+		}
+		if md.WorkshopID == "" {
+			issue.Suggestion = "subscribe to the workshop item that provides it and add it to Mods="
+		} else {
+			issue.Suggestion = fmt.Sprintf("add %s to Mods= (provided by workshop item %s)", md.ModID, md.WorkshopID)
+			issue.Fixes = []Fix{{Action: FixAddMod, ModID: md.ModID}}
+			if !listed[md.WorkshopID] {
+				issue.Fixes = append(issue.Fixes, Fix{Action: FixAddWorkshopItem, WorkshopID: md.WorkshopID})
+			}
+		}
+		add(issue)
+	}
+	for _, c := range res.Incompatible {
+		add(HealthIssue{
+			ID: string(IssueIncompatible) + ":" + c.ModID + ":" + c.With, Kind: IssueIncompatible, ModID: c.ModID,
+			Message:    fmt.Sprintf("mod %q is incompatible with %q", c.ModID, c.With),
+			Suggestion: "disable one of them",
+		})
+	}
+	return report, nil
+}
+
+// itemDownloaded 条目目录存在即视为已下载。
+func (s Service) itemDownloaded(workshopID string) bool {
+	info, err := os.Stat(filepath.Join(s.workshopDir(), workshopID))
+	return err == nil && info.IsDir()
+}
+
+func (s Service) workshopDir() string {
+	return filepath.Join(s.InstallDir, "steamapps", "workshop", "content", "108600")
+}
+
+type orderedSet struct {
+	items []string
+}
+
+func newOrderedSet(items []string) *orderedSet {
+	return &orderedSet{items: append([]string(nil), items...)}
+}
+
+func (o *orderedSet) add(v string) {
+	for _, it := range o.items {
+		if it == v {
+			return
+		}
+	}
+	o.items = append(o.items, v)
+}
+
+func (o *orderedSet) remove(v string) {
+	out := o.items[:0]
+	for _, it := range o.items {
+		if it != v {
+			out = append(out, it)
+		}
+	}
+	o.items = out
+}
+
+func toSet(items []string) map[string]bool {
+	m := make(map[string]bool, len(items))
+	for _, it := range items {
+		m[it] = true
+	}
+	return m
+}
+
+func anyIn(items []string, set map[string]bool) bool {
+	for _, it := range items {
+		if set[it] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package modsapp
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"pz-web-backend/internal/application/configapp"
+	"pz-web-backend/internal/infra/fs"
+)
+
+func newHealthService(t *testing.T, ini string) (Service, string) {
+	root := t.TempDir()
+	write := func(ws, dir, content string) {
+		base := filepath.Join(root, "steamapps/workshop/content/108600", ws, "mods", dir)
+		if err := os.MkdirAll(base, 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(base, "mod.info"), []byte(content), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	write("1", "Lib", "id=lib\n")
+	write("2", "Main", "id=main\nrequire=\\lib\nincompatible=\\rival\n")
+	write("3", "Rival", "id=rival\n")
+	write("4", "Unused", "id=unused\n")
+
+	iniPath := filepath.Join(root, "Server", "servertest.ini")
+	if err := os.MkdirAll(filepath.Dir(iniPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(iniPath, []byte(ini), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	cfg := configapp.Service{BaseDataDir: root, ServerName: "servertest", DevMode: true, FS: fs.OSFS{}}
+	return Service{InstallDir: root, Config: cfg}, iniPath
+}
+
+func issueIDs(issues []HealthIssue) string {
+	var ids []string
+	for _, i := range issues {
+		ids = append(ids, i.ID)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestService_Health(t *testing.T) {
+	svc, _ := newHealthService(t, "Mods=main;rival;ghost\nWorkshopItems=2;4\n")
+	report, err := svc.Health()
+	if err != nil {
+		t.Fatalf("Health: %v", err)
+	}
+	want := "mod_not_subscribed:rival,mod_not_found:ghost,workshop_item_unused:4,missing_dependency:lib,incompatible_mods:main:rival"
+	if got := issueIDs(report.Issues); got != want {
+		t.Fatalf("issues=%s", got)
+	}
+	if report.Fixable != 4 || report.ETag == "" {
+		t.Fatalf("report=%+v", report)
+	}
+	dep := report.Issues[3]
+	if dep.WorkshopID != "1" || len(dep.Fixes) != 2 || dep.Fixes[0].Action != FixAddMod || dep.Fixes[1].Action != FixAddWorkshopItem {
+		t.Fatalf("dep=%+v", dep)
+	}
+
+	// 有未下载的条目时，找不到的模组可能来自它，不提供自动移除。
+	svc, _ = newHealthService(t, "Mods=ghost\nWorkshopItems=999\n")
+	report, _ = svc.Health()
+	if got := issueIDs(report.Issues); got != "mod_not_found:ghost,workshop_item_not_downloaded:999" || report.Fixable != 0 {
+		t.Fatalf("issues=%s fixable=%d", got, report.Fixable)
+	}
+}
+
+func TestService_FixHealth(t *testing.T) {
+	svc, iniPath := newHealthService(t, "PVP=true\nMods=main;ghost\nWorkshopItems=2;4\n")
+
+	if _, err := svc.FixHealth(FixInput{}); !errors.Is(err, ErrETagRequired) {
+		t.Fatalf("err=%v", err)
+	}
+	report, err := svc.Health()
+	if err != nil {
+		t.Fatalf("Health: %v", err)
+	}
+
+	// 只修复选中的问题。
+	res, err := svc.FixHealth(FixInput{IssueIDs: []string{"mod_not_found:ghost"}, ETag: report.ETag})
+	if err != nil {
+		t.Fatalf("FixHealth: %v", err)
+	}
+	if len(res.Applied) != 1 || issueIDs(res.Report.Issues) != "workshop_item_unused:4,missing_dependency:lib" {
+		t.Fatalf("res=%+v", res)
+	}
+
+	res, err = svc.FixHealth(FixInput{ETag: res.ETag})
+	if err != nil {
+		t.Fatalf("FixHealth: %v", err)
+	}
+	got, _ := os.ReadFile(iniPath)
+	if string(got) != "PVP=true\nMods=main;lib\nWorkshopItems=2;1\n" || len(res.Report.Issues) != 0 {
+		t.Fatalf("ini=%q report=%+v", got, res.Report)
+	}
+
+	// 没有可修复的问题时不写盘。
+	again, err := svc.FixHealth(FixInput{ETag: res.ETag})
+	if err != nil || len(again.Applied) != 0 || again.ETag != res.ETag {
+		t.Fatalf("again=%+v err=%v", again, err)
+	}
+}
@@ -6,13 +6,29 @@ import (
 	"pz-web-backend/internal/mods"
 )
 
+// WorkshopFetcher 查询 Steam 创意工坊，通常是 *mods.WorkshopClient。
 type WorkshopFetcher interface {
-	FetchWorkshopInfo(workshopID string) (mods.ModInfo, error)
+	// FetchWorkshopInfos 每个 ID 要么出现在 infos 中，要么出现在 errs 中。
+	FetchWorkshopInfos(workshopIDs []string) (infos map[string]mods.ModInfo, errs map[string]error)
+	// RefreshWorkshopInfos 同上，但不使用缓存。
+	RefreshWorkshopInfos(workshopIDs []string) (infos map[string]mods.ModInfo, errs map[string]error)
+	FetchCollection(collectionID string) (mods.CollectionContents, error)
+}
+
+// WorkshopItemSource 提供服务器配置中订阅的创意工坊 ID。
+type WorkshopItemSource interface {
+	WorkshopItems() ([]string, error)
 }
 
 type Service struct {
 	InstallDir string
 	Workshop   WorkshopFetcher
+	// Items 为 CheckUpdates 提供 WorkshopItems=，通常是 configapp.Service。
+	Items WorkshopItemSource
+	// Config 为 Health / FixHealth 读写 Mods= 与 WorkshopItems=。
+	Config ModsConfig
+	// GameVersion 服务器的游戏版本，决定每个模组使用哪个变体目录；零值表示未知。
+	GameVersion mods.GameVersion
 }
 
 type LookupResult struct {
@@ -26,58 +42,63 @@ func (s Service) ListLocalMods() ([]mods.ModInfo, error) {
 	if s.InstallDir == "" {
 		return nil, fmt.Errorf("install dir is empty")
 	}
-	return mods.ScanLocalMods(s.InstallDir)
+	return mods.ScanLocalModsFor(s.InstallDir, s.GameVersion)
 }
 
+// Lookup 优先使用本地已下载的 mod.info，其余条目一次性批量向 Steam 查询；结果与 workshopIDs 顺序一致。
 func (s Service) Lookup(workshopIDs []string) ([]LookupResult, error) {
 	localMods, err := s.ListLocalMods()
 	if err != nil {
 		// 本地扫描失败不影响 Steam 查询（仍返回结果），错误由每项携带。
 		localMods = nil
 	}
+	byWorkshop := make(map[string][]mods.ModInfo)
+	for _, lm := range localMods {
+		byWorkshop[lm.WorkshopID] = append(byWorkshop[lm.WorkshopID], lm)
+	}
 
-	results := make([]LookupResult, 0, len(workshopIDs))
+	var remote []string
 	for _, wid := range workshopIDs {
-		var matched []mods.ModInfo
-		for _, lm := range localMods {
-			if lm.WorkshopID == wid {
-				matched = append(matched, lm)
-			}
-		}
-		if len(matched) > 0 {
-			results = append(results, LookupResult{
-				WorkshopID: wid,
-				Mods:       matched,
-				Source:     "local",
-			})
-			continue
+		if len(byWorkshop[wid]) == 0 {
+			remote = append(remote, wid)
 		}
+	}
+	var (
+		infos map[string]mods.ModInfo
+		errs  map[string]error
+	)
+	if len(remote) > 0 && s.Workshop != nil {
+		infos, errs = s.Workshop.FetchWorkshopInfos(remote)
+	}
 
-		if s.Workshop == nil {
-			results = append(results, LookupResult{
-				WorkshopID: wid,
-				Source:     "steam",
-				Err:        fmt.Errorf("workshop fetcher not configured"),
-			})
+	results := make([]LookupResult, 0, len(workshopIDs))
+	for _, wid := range workshopIDs {
+		if matched := byWorkshop[wid]; len(matched) > 0 {
+			results = append(results, LookupResult{WorkshopID: wid, Mods: matched, Source: "local"})
 			continue
 		}
-
-		info, fetchErr := s.Workshop.FetchWorkshopInfo(wid)
-		if fetchErr != nil {
-			results = append(results, LookupResult{
-				WorkshopID: wid,
-				Source:     "steam",
-				Err:        fetchErr,
-			})
-			continue
+		r := LookupResult{WorkshopID: wid, Source: "steam"}
+		switch info, ok := infos[wid]; {
+		case s.Workshop == nil:
+			r.Err = fmt.Errorf("workshop fetcher not configured")
+		case ok:
+			r.Mods = []mods.ModInfo{info}
+		case errs[wid] != nil:
+			r.Err = errs[wid]
+		default:
+			r.Err = mods.ErrWorkshopItemNotFound
 		}
-
-		results = append(results, LookupResult{
-			WorkshopID: wid,
-			Mods:       []mods.ModInfo{info},
-			Source:     "steam",
-		})
+		results = append(results, r)
 	}
 
 	return results, nil
 }
+
+// Resolve 按本地 mod.info 解析选中模组的依赖、冲突与加载顺序，见 mods.Resolve。
+func (s Service) Resolve(selected []string, addMissing bool) (mods.Resolution, error) {
+	local, err := s.ListLocalMods()
+	if err != nil {
+		return mods.Resolution{}, err
+	}
+	return mods.Resolve(selected, local, mods.ResolveOptions{AddMissing: addMissing}), nil
+}
@@ -9,18 +9,34 @@ import (
 )
 
 type stubWorkshop struct {
-	m   map[string]mods.ModInfo
-	err error
+	m           map[string]mods.ModInfo
+	err         error
+	collections map[string]mods.CollectionContents
 }
 
-func (s stubWorkshop) FetchWorkshopInfo(workshopID string) (mods.ModInfo, error) {
-	if s.err != nil {
-		return mods.ModInfo{}, s.err
+func (s stubWorkshop) FetchWorkshopInfos(workshopIDs []string) (map[string]mods.ModInfo, map[string]error) {
+	infos, errs := make(map[string]mods.ModInfo), make(map[string]error)
+	for _, id := range workshopIDs {
+		if s.err != nil {
+			errs[id] = s.err
+		} else if v, ok := s.m[id]; ok {
+			infos[id] = v
+		} else {
+			errs[id] = os.ErrNotExist
+		}
 	}
-	if v, ok := s.m[workshopID]; ok {
-		return v, nil
+	return infos, errs
+}
+
+func (s stubWorkshop) RefreshWorkshopInfos(workshopIDs []string) (map[string]mods.ModInfo, map[string]error) {
+	return s.FetchWorkshopInfos(workshopIDs)
+}
+
+func (s stubWorkshop) FetchCollection(collectionID string) (mods.CollectionContents, error) {
+	if c, ok := s.collections[collectionID]; ok {
+		return c, nil
 	}
-	return mods.ModInfo{}, os.ErrNotExist
+	return mods.CollectionContents{}, mods.ErrCollectionNotFound
 }
 
 func TestService_ListLocalMods(t *testing.T) {
@@ -81,3 +97,35 @@ func TestService_Lookup_FallsBackToSteam(t *testing.T) {
 		t.Fatalf("res=%+v", res)
 	}
 }
+
+type stubItems []string
+
+func (s stubItems) WorkshopItems() ([]string, error) { return s, nil }
+
+func TestService_Resolve(t *testing.T) {
+	root := t.TempDir()
+	write := func(ws, dir, content string) {
+		base := filepath.Join(root, "steamapps/workshop/content/108600", ws, "mods", dir)
+		if err := os.MkdirAll(base, 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(base, "mod.info"), []byte(content), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	write("1", "Lib", "id=lib\n")
+	write("2", "Main", "id=main\nrequire=\\lib\n")
+
+	svc := Service{InstallDir: root}
+	res, err := svc.Resolve([]string{"main"}, false)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if len(res.Missing) != 1 || res.Missing[0].ModID != "lib" || res.Missing[0].WorkshopID != "1" {
+		t.Fatalf("missing=%+v", res.Missing)
+	}
+	res, _ = svc.Resolve([]string{"main"}, true)
+	if res.ModsLine != "lib;main" || res.WorkshopItemsLine != "1;2" {
+		t.Fatalf("res=%+v", res)
+	}
+}
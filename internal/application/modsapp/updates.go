@@ -0,0 +1,155 @@
+package modsapp
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"time"
+
+	"pz-web-backend/internal/mods"
+)
+
+type UpdateStatus string
+
+const (
+	UpdateCurrent  UpdateStatus = "up_to_date"
+	UpdateOutdated UpdateStatus = "outdated"
+	// UpdateMissing 本地没有下载，服务器下次启动时才会下载。
+	UpdateMissing UpdateStatus = "missing"
+	// UpdateRemoved Steam 上已删除、不公开或被封禁，服务器启动时会下载失败。
+	UpdateRemoved UpdateStatus = "removed"
+	// UpdateUnknown Steam 查询失败（网络问题等），无法判断。
+	UpdateUnknown UpdateStatus = "unknown"
+)
+
+// 本地更新时间的来源。
+const (
+	LocalSourceManifest = "manifest"
+	LocalSourceMtime    = "mtime"
+)
+
+// ItemUpdate 单个条目本地与 Steam 上的版本对比。
+type ItemUpdate struct {
+	WorkshopID string       `json:"workshop_id"`
+	Title      string       `json:"title,omitempty"`
+	Status     UpdateStatus `json:"status"`
+	// LocalUpdated 优先取 appworkshop_108600.acf 中的 timeupdated，没有记录时取目录内最新文件的修改时间。
+	LocalUpdated  *time.Time `json:"local_updated,omitempty"`
+	LocalSource   string     `json:"local_source,omitempty"`
+	LocalSize     int64      `json:"local_size,omitempty"`
+	RemoteUpdated *time.Time `json:"remote_updated,omitempty"`
+	RemoteSize    int64      `json:"remote_size,omitempty"`
+	Error         string     `json:"error,omitempty"`
+}
+
+// UpdateReport WorkshopItems= 中条目的更新检查结果，Outdated / Missing / Removed 为对应状态的 ID。
+type UpdateReport struct {
+	CheckedAt time.Time    `json:"checked_at"`
+	Items     []ItemUpdate `json:"items"`
+	Outdated  []string     `json:"outdated"`
+	Missing   []string     `json:"missing"`
+	Removed   []string     `json:"removed"`
+}
+
+// NeedsRestart 是否有重启后服务器会下载的更新。只看过期的条目：
+// 缺失的条目可能始终下载不下来（私有、下载失败），按它重启会在每次检查时都重启一次。
+func (r UpdateReport) NeedsRestart() bool {
+	return len(r.Outdated) > 0
+}
+
+// CheckUpdates 向 Steam 查询 WorkshopItems= 中条目的最新 time_updated（不使用缓存），与本地下载的版本对比。
+func (s Service) CheckUpdates() (UpdateReport, error) {
+	if s.Items == nil {
+		return UpdateReport{}, fmt.Errorf("workshop item source not configured")
+	}
+	if s.Workshop == nil {
+		return UpdateReport{}, fmt.Errorf("workshop fetcher not configured")
+	}
+	ids, err := s.Items.WorkshopItems()
+	if err != nil {
+		return UpdateReport{}, err
+	}
+	// 清单损坏时退回到按文件修改时间判断。
+	manifest, err := mods.ReadWorkshopManifest(s.InstallDir)
+	if err != nil {
+		manifest = mods.WorkshopManifest{}
+	}
+	var infos map[string]mods.ModInfo
+	var errs map[string]error
+	if len(ids) > 0 {
+		infos, errs = s.Workshop.RefreshWorkshopInfos(ids)
+	}
+
+	report := UpdateReport{
+		CheckedAt: time.Now(),
+		Items:     make([]ItemUpdate, 0, len(ids)),
+		Outdated:  []string{},
+		Missing:   []string{},
+		Removed:   []string{},
+	}
+	for _, id := range ids {
+		item := ItemUpdate{WorkshopID: id}
+		downloaded := s.itemDownloaded(id)
+		if downloaded {
+			s.localVersion(&item, manifest)
+		}
+		info, found := infos[id]
+		if found {
+			item.Title = info.Name
+			item.RemoteSize = info.FileSize
+			if info.TimeUpdated > 0 {
+				t := time.Unix(info.TimeUpdated, 0)
+				item.RemoteUpdated = &t
+			}
+		} else if err := errs[id]; err != nil {
+			item.Error = err.Error()
+		}
+
+		switch {
+		case (!found && errors.Is(errs[id], mods.ErrWorkshopItemNotFound)) || info.Banned:
+			item.Status = UpdateRemoved
+			report.Removed = append(report.Removed, id)
+		case !downloaded:
+			item.Status = UpdateMissing
+			report.Missing = append(report.Missing, id)
+		case !found:
+			item.Status = UpdateUnknown
+		case item.RemoteUpdated != nil && item.LocalUpdated != nil && item.RemoteUpdated.After(*item.LocalUpdated):
+			item.Status = UpdateOutdated
+			report.Outdated = append(report.Outdated, id)
+		default:
+			item.Status = UpdateCurrent
+		}
+		report.Items = append(report.Items, item)
+	}
+	return report, nil
+}
+
+// localVersion 填入本地已下载版本的时间与大小。
+func (s Service) localVersion(item *ItemUpdate, manifest mods.WorkshopManifest) {
+	if m, ok := manifest[item.WorkshopID]; ok && m.TimeUpdated > 0 {
+		t := time.Unix(m.TimeUpdated, 0)
+		item.LocalUpdated, item.LocalSource, item.LocalSize = &t, LocalSourceManifest, m.Size
+		return
+	}
+	var latest time.Time
+	var size int64
+	_ = filepath.WalkDir(filepath.Join(s.workshopDir(), item.WorkshopID), func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		size += info.Size()
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+		return nil
+	})
+	if !latest.IsZero() {
+		item.LocalUpdated, item.LocalSource, item.LocalSize = &latest, LocalSourceMtime, size
+	}
+}
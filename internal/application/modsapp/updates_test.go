@@ -0,0 +1,84 @@
+package modsapp
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"pz-web-backend/internal/mods"
+)
+
+func TestService_CheckUpdates(t *testing.T) {
+	root := t.TempDir()
+	for _, ws := range []string{"1", "2", "3", "5"} {
+		dir := filepath.Join(root, "steamapps/workshop/content/108600", ws, "mods/A")
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "mod.info"), []byte("id=a"+ws+"\n"), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	// 3 没有清单记录，按文件修改时间判断。
+	old := time.Unix(1600000000, 0)
+	if err := os.Chtimes(filepath.Join(root, "steamapps/workshop/content/108600/3/mods/A/mod.info"), old, old); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	acf := `"AppWorkshop" { "WorkshopItemsInstalled" {
+		"1" { "size" "100" "timeupdated" "1700000000" }
+		"2" { "size" "100" "timeupdated" "1700000000" }
+	} }`
+	if err := os.WriteFile(mods.WorkshopManifestPath(root), []byte(acf), 0o644); err != nil {
+		t.Fatalf("write acf: %v", err)
+	}
+
+	svc := Service{
+		InstallDir: root,
+		Items:      stubItems{"1", "2", "3", "4", "5", "6"},
+		Workshop: stubWorkshop{m: map[string]mods.ModInfo{
+			"1": {WorkshopID: "1", Name: "One", TimeUpdated: 1700000000, FileSize: 100},
+			"2": {WorkshopID: "2", TimeUpdated: 1710000000},
+			"3": {WorkshopID: "3", TimeUpdated: 1650000000},
+			"4": {WorkshopID: "4", TimeUpdated: 1650000000},
+			"6": {WorkshopID: "6", Banned: true},
+		}},
+	}
+	report, err := svc.CheckUpdates()
+	if err != nil {
+		t.Fatalf("CheckUpdates: %v", err)
+	}
+	var statuses []string
+	for _, it := range report.Items {
+		statuses = append(statuses, it.WorkshopID+"="+string(it.Status))
+	}
+	// stubWorkshop 对未知 ID 返回 os.ErrNotExist（视为查询失败而不是已删除）。
+	want := "1=up_to_date,2=outdated,3=outdated,4=missing,5=unknown,6=removed"
+	if got := strings.Join(statuses, ","); got != want {
+		t.Fatalf("statuses=%s", got)
+	}
+	if strings.Join(report.Outdated, ",") != "2,3" || strings.Join(report.Missing, ",") != "4" ||
+		strings.Join(report.Removed, ",") != "6" || !report.NeedsRestart() {
+		t.Fatalf("report=%+v", report)
+	}
+	if it := report.Items[0]; it.Title != "One" || it.LocalSource != LocalSourceManifest || it.LocalSize != 100 {
+		t.Fatalf("item=%+v", it)
+	}
+	if it := report.Items[2]; it.LocalSource != LocalSourceMtime || !it.LocalUpdated.Equal(old) {
+		t.Fatalf("item=%+v", it)
+	}
+
+	// 只有缺失的条目时不重启。
+	svc.Items = stubItems{"4"}
+	if report, _ = svc.CheckUpdates(); len(report.Missing) != 1 || report.NeedsRestart() {
+		t.Fatalf("report=%+v", report)
+	}
+
+	svc.Workshop = stubWorkshop{err: mods.ErrWorkshopItemNotFound}
+	svc.Items = stubItems{"1"}
+	report, _ = svc.CheckUpdates()
+	if strings.Join(report.Removed, ",") != "1" || report.NeedsRestart() {
+		t.Fatalf("report=%+v", report)
+	}
+}
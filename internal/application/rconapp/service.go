@@ -0,0 +1,192 @@
+package rconapp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"pz-web-backend/internal/infra/rcon"
+)
+
+// ErrInvalidArgument 命令参数不合法（为空或包含引号、换行）。
+var ErrInvalidArgument = errors.New("invalid argument")
+
+// Executor 执行一条 RCON 命令。
+type Executor interface {
+	Exec(command string) (string, error)
+}
+
+// CredentialSource 提供 RCON 端口与密码，通常来自解析后的服务器 INI。
+type CredentialSource interface {
+	RCONCredentials() (port int, password string, err error)
+}
+
+type Service struct {
+	// Host RCON 监听地址，PZ 服务器与面板部署在同一台机器上。
+	Host        string
+	Credentials CredentialSource
+	// Dial 根据地址与密码创建 Executor；为空时使用 rcon.NewClient。
+	Dial func(addr string, password string) Executor
+
+	clients *clientCache
+}
+
+func NewService(host string, creds CredentialSource) Service {
+	return Service{Host: host, Credentials: creds, clients: &clientCache{}}
+}
+
+// Exec 原样执行一条命令。
+func (s Service) Exec(command string) (string, error) {
+	command = strings.TrimSpace(command)
+	if command == "" || strings.ContainsAny(command, "\r\n") {
+		return "", fmt.Errorf("%w: command must be a single non-empty line", ErrInvalidArgument)
+	}
+	exec, err := s.executor()
+	if err != nil {
+		return "", err
+	}
+	return exec.Exec(command)
+}
+
+// Players 返回在线玩家名。
+func (s Service) Players() ([]string, error) {
+	out, err := s.Exec("players")
+	if err != nil {
+		return nil, err
+	}
+	return ParsePlayers(out), nil
+}
+
+// ServerMessage 向所有玩家广播消息。
+func (s Service) ServerMessage(message string) (string, error) {
+	arg, err := quoteArg(message)
+	if err != nil {
+		return "", err
+	}
+	return s.Exec("servermsg " + arg)
+}
+
+func (s Service) Save() (string, error) {
+	return s.Exec("save")
+}
+
+// KickUser 踢出玩家；reason 可为空。
+func (s Service) KickUser(user string, reason string) (string, error) {
+	cmd, err := userCommand("kickuser", user, reason)
+	if err != nil {
+		return "", err
+	}
+	return s.Exec(cmd)
+}
+
+// BanUser 封禁玩家；banIP 为 true 时同时封禁其 IP。
+func (s Service) BanUser(user string, reason string, banIP bool) (string, error) {
+	cmd, err := userCommand("banuser", user, reason)
+	if err != nil {
+		return "", err
+	}
+	if banIP {
+		cmd += " -ip"
+	}
+	return s.Exec(cmd)
+}
+
+// Quit 保存并关闭服务器。
+func (s Service) Quit() (string, error) {
+	return s.Exec("quit")
+}
+
+// ParsePlayers 解析 players 命令的输出：
+//
+//	Players connected (2):
+//	-alice
+//	-bob
+func ParsePlayers(out string) []string {
+	players := []string{}
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "-") {
+			if name := strings.TrimSpace(line[1:]); name != "" {
+				players = append(players, name)
+			}
+		}
+	}
+	return players
+}
+
+func userCommand(name string, user string, reason string) (string, error) {
+	u, err := quoteArg(user)
+	if err != nil {
+		return "", err
+	}
+	cmd := name + " " + u
+	if strings.TrimSpace(reason) != "" {
+		r, err := quoteArg(reason)
+		if err != nil {
+			return "", err
+		}
+		cmd += " -r " + r
+	}
+	return cmd, nil
+}
+
+// quoteArg PZ 的命令解析不支持转义，参数中不能出现双引号。
+func quoteArg(v string) (string, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return "", fmt.Errorf("%w: value is required", ErrInvalidArgument)
+	}
+	if strings.ContainsAny(v, "\"\r\n") {
+		return "", fmt.Errorf("%w: quotes and line breaks are not allowed", ErrInvalidArgument)
+	}
+	return `"` + v + `"`, nil
+}
+
+func (s Service) executor() (Executor, error) {
+	if s.Credentials == nil {
+		return nil, fmt.Errorf("rcon credentials not configured")
+	}
+	port, password, err := s.Credentials.RCONCredentials()
+	if err != nil {
+		return nil, err
+	}
+	host := s.Host
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+
+	dial := s.Dial
+	if dial == nil {
+		dial = func(addr string, password string) Executor { return rcon.NewClient(addr, password) }
+	}
+	if s.clients == nil {
+		return dial(addr, password), nil
+	}
+	return s.clients.get(addr, password, dial), nil
+}
+
+// clientCache 复用已认证的连接；配置中的端口或密码变化时重新创建。
+type clientCache struct {
+	mu       sync.Mutex
+	addr     string
+	password string
+	exec     Executor
+}
+
+func (c *clientCache) get(addr string, password string, dial func(string, string) Executor) Executor {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.exec != nil && c.addr == addr && c.password == password {
+		return c.exec
+	}
+	if closer, ok := c.exec.(io.Closer); ok {
+		_ = closer.Close()
+	}
+	c.addr, c.password, c.exec = addr, password, dial(addr, password)
+	return c.exec
+}
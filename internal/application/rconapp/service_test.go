@@ -0,0 +1,66 @@
+package rconapp
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type fakeCreds struct {
+	port     int
+	password string
+}
+
+func (f *fakeCreds) RCONCredentials() (int, string, error) { return f.port, f.password, nil }
+
+type recordingExec struct {
+	addr     string
+	commands []string
+	out      string
+}
+
+func (r *recordingExec) Exec(command string) (string, error) {
+	r.commands = append(r.commands, command)
+	return r.out, nil
+}
+
+func TestService_CommandsAndClientReuse(t *testing.T) {
+	creds := &fakeCreds{port: 27015, password: "a"}
+	var dialed []*recordingExec
+	svc := NewService("127.0.0.1", creds)
+	svc.Dial = func(addr string, password string) Executor {
+		e := &recordingExec{addr: addr, out: "Players connected (2):\n-alice\n-bob the builder\n"}
+		dialed = append(dialed, e)
+		return e
+	}
+
+	players, err := svc.Players()
+	if err != nil {
+		t.Fatalf("Players: %v", err)
+	}
+	if !reflect.DeepEqual(players, []string{"alice", "bob the builder"}) {
+		t.Fatalf("players=%v", players)
+	}
+	if _, err := svc.BanUser("bob", "griefing", true); err != nil {
+		t.Fatalf("BanUser: %v", err)
+	}
+	if _, err := svc.KickUser(`bo"b`, ""); !errors.Is(err, ErrInvalidArgument) {
+		t.Fatalf("err=%v", err)
+	}
+
+	if len(dialed) != 1 || dialed[0].addr != "127.0.0.1:27015" {
+		t.Fatalf("dialed=%+v", dialed)
+	}
+	want := []string{"players", `banuser "bob" -r "griefing" -ip`}
+	if !reflect.DeepEqual(dialed[0].commands, want) {
+		t.Fatalf("commands=%q", dialed[0].commands)
+	}
+
+	creds.password = "b"
+	if _, err := svc.ServerMessage("restart in 5 minutes"); err != nil {
+		t.Fatalf("ServerMessage: %v", err)
+	}
+	if len(dialed) != 2 || dialed[1].commands[0] != `servermsg "restart in 5 minutes"` {
+		t.Fatalf("expected redial after password change, dialed=%+v", dialed)
+	}
+}
@@ -0,0 +1,73 @@
+package restartapp
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"regexp"
+
+	"pz-web-backend/internal/infra/logtail"
+	"pz-web-backend/internal/pzlog"
+)
+
+// DefaultSavePattern 服务器日志中表示世界保存完成的行。
+var DefaultSavePattern = pzlog.WorldSavedPattern
+
+// SaveWatcher 在发出 save 命令之前开始监听，返回的 wait 阻塞到保存完成或 ctx 结束。
+type SaveWatcher interface {
+	WatchSave(ctx context.Context) (wait func() error, err error)
+}
+
+// LogSaveWatcher 通过 tail 服务器日志等待保存完成的日志行。
+type LogSaveWatcher struct {
+	Tailer  logtail.Tailer
+	Path    string
+	Pattern *regexp.Regexp
+}
+
+func (w LogSaveWatcher) WatchSave(ctx context.Context) (func() error, error) {
+	if w.Tailer == nil {
+		return nil, fmt.Errorf("log tailer not configured")
+	}
+	pattern := w.Pattern
+	if pattern == nil {
+		pattern = DefaultSavePattern
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	rc, err := w.Tailer.Tail(ctx, w.Path, logtail.FromEnd)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		sc := bufio.NewScanner(rc)
+		sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for sc.Scan() {
+			if pattern.MatchString(sc.Text()) {
+				done <- nil
+				return
+			}
+		}
+		if err := sc.Err(); err != nil {
+			done <- err
+			return
+		}
+		done <- fmt.Errorf("log stream ended before save finished")
+	}()
+
+	return func() error {
+		defer func() {
+			cancel()
+			_ = rc.Close()
+		}()
+		select {
+		case err := <-done:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}, nil
+}
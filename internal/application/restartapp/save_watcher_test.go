@@ -0,0 +1,44 @@
+package restartapp
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"pz-web-backend/internal/infra/logtail"
+)
+
+type pipeTailer struct {
+	r     *io.PipeReader
+	lines int
+}
+
+func (p *pipeTailer) Tail(ctx context.Context, path string, lines int) (io.ReadCloser, error) {
+	p.lines = lines
+	return p.r, nil
+}
+
+func TestLogSaveWatcher_WaitsForSaveLine(t *testing.T) {
+	r, w := io.Pipe()
+	tailer := &pipeTailer{r: r}
+	watcher := LogSaveWatcher{Tailer: tailer, Path: "server.log"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	wait, err := watcher.WatchSave(ctx)
+	if err != nil {
+		t.Fatalf("WatchSave: %v", err)
+	}
+	if tailer.lines != logtail.FromEnd {
+		t.Fatalf("lines=%d", tailer.lines)
+	}
+
+	go func() {
+		_, _ = io.WriteString(w, "LOG  : General > player connected\n")
+		_, _ = io.WriteString(w, "LOG  : General > World saved\n")
+	}()
+	if err := wait(); err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+}
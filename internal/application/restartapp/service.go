@@ -0,0 +1,428 @@
+package restartapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"pz-web-backend/internal/infra/supervisor"
+	"pz-web-backend/internal/jobs"
+)
+
+var (
+	// ErrInProgress 已有一次重启在进行中。
+	ErrInProgress = errors.New("a restart is already in progress")
+	// ErrNotCancellable 没有可取消的重启，或已进入保存 / 重启阶段。
+	ErrNotCancellable = errors.New("no cancellable restart")
+)
+
+// DefaultWarnings 默认的倒计时提醒点。
+var DefaultWarnings = []time.Duration{10 * time.Minute, 5 * time.Minute, time.Minute, 10 * time.Second}
+
+// JobKind 计划重启在 jobs.Manager 中的类型。
+const JobKind = "server.graceful_restart"
+
+// DefaultMessage 倒计时广播内容，%s 为剩余时间。
+const DefaultMessage = "Server will restart in %s."
+
+type Phase string
+
+const (
+	PhaseIdle       Phase = "idle"
+	PhasePending    Phase = "pending"
+	PhaseCountdown  Phase = "countdown"
+	PhaseSaving     Phase = "saving"
+	PhaseRestarting Phase = "restarting"
+	PhaseUp         Phase = "up"
+	PhaseCancelled  Phase = "cancelled"
+	PhaseFailed     Phase = "failed"
+)
+
+// RCON 倒计时用到的 RCON 命令。
+type RCON interface {
+	ServerMessage(message string) (string, error)
+	Save() (string, error)
+	Players() ([]string, error)
+}
+
+// Options 一次重启的参数。
+type Options struct {
+	// Warnings 距重启的提醒点；为空时立即保存并重启。
+	Warnings []time.Duration
+	// Message 广播模板，%s 为剩余时间；为空时使用 DefaultMessage。
+	Message     string
+	Reason      string
+	RequestedBy string
+}
+
+// Status 当前或最近一次重启的状态。
+type Status struct {
+	Phase       Phase     `json:"phase"`
+	Reason      string    `json:"reason,omitempty"`
+	RequestedBy string    `json:"requested_by,omitempty"`
+	StartedAt   time.Time `json:"started_at"`
+	RestartAt   time.Time `json:"restart_at"`
+	// NextWarning 下一次广播的时间点（剩余时长），仅 countdown 阶段有值。
+	NextWarning string `json:"next_warning,omitempty"`
+	// Notes 不影响继续重启的问题，例如 RCON 广播失败、保存超时。
+	Notes []string `json:"notes,omitempty"`
+	Error string   `json:"error,omitempty"`
+	// JobID 对应的后台任务，未配置 Jobs 时为空。
+	JobID string `json:"job_id,omitempty"`
+}
+
+// Orchestrator 按“倒计时广播 → save → 等待保存完成 → 重启 → 等待恢复”的流程重启服务器。
+// 同一时间只允许一次重启。
+type Orchestrator struct {
+	RCON      RCON
+	Restarter supervisor.Restarter
+	Saves     SaveWatcher
+	// Jobs 非空时重启作为独占任务运行，与更新等操作互斥；
+	// 此时 Restarter 不能再经过同一个 Manager，否则会与自身冲突。
+	Jobs *jobs.Manager
+
+	// SaveTimeout 等待保存完成的最长时间，超时后仍继续重启。
+	SaveTimeout time.Duration
+	// UpTimeout 重启后等待 RCON 恢复的最长时间。
+	UpTimeout    time.Duration
+	PollInterval time.Duration
+
+	Now func() time.Time
+
+	mu       sync.Mutex
+	status   Status
+	cancel   context.CancelFunc
+	finished chan struct{}
+	reporter *jobs.Reporter
+}
+
+// Status 返回状态快照。
+func (o *Orchestrator) Status() Status {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	st := o.status
+	if st.Phase == "" {
+		st.Phase = PhaseIdle
+	}
+	st.Notes = append([]string(nil), st.Notes...)
+	return st
+}
+
+// Start 在后台开始一次重启并立即返回初始状态。
+func (o *Orchestrator) Start(opts Options) (Status, error) {
+	o.mu.Lock()
+	if o.activeLocked() {
+		o.mu.Unlock()
+		return Status{}, ErrInProgress
+	}
+
+	warnings := append([]time.Duration(nil), opts.Warnings...)
+	sort.Slice(warnings, func(i, j int) bool { return warnings[i] > warnings[j] })
+	var lead time.Duration
+	if len(warnings) > 0 {
+		lead = warnings[0]
+	}
+
+	now := o.now()
+	restartAt := now.Add(lead)
+	ctx, cancel := context.WithCancel(context.Background())
+	finished := make(chan struct{})
+	run := func() {
+		defer close(finished)
+		o.run(ctx, restartAt, warnings, opts.Message)
+	}
+
+	// run 首先需要 o.mu，因此在锁内启动任务也能保证先看到下面设置的状态。
+	var jobID string
+	if o.Jobs != nil {
+		job, err := o.Jobs.Start(jobs.Spec{Kind: JobKind, Exclusive: true}, func(jobCtx context.Context, r *jobs.Reporter) error {
+			o.mu.Lock()
+			o.reporter = r
+			o.mu.Unlock()
+			stop := context.AfterFunc(jobCtx, func() { _, _ = o.Cancel() })
+			defer stop()
+			run()
+			return o.Status().err()
+		})
+		if err != nil {
+			o.mu.Unlock()
+			cancel()
+			return Status{}, err
+		}
+		jobID = job.ID
+	}
+
+	o.cancel = cancel
+	o.finished = finished
+	o.reporter = nil
+	o.status = Status{
+		Phase:       PhasePending,
+		Reason:      opts.Reason,
+		RequestedBy: opts.RequestedBy,
+		StartedAt:   now,
+		RestartAt:   restartAt,
+		JobID:       jobID,
+	}
+	st := o.status
+	o.mu.Unlock()
+
+	if o.Jobs == nil {
+		go run()
+	}
+	return st, nil
+}
+
+// err 把结束状态转换为任务结果。
+func (st Status) err() error {
+	switch st.Phase {
+	case PhaseFailed:
+		return errors.New(st.Error)
+	case PhaseCancelled:
+		return context.Canceled
+	}
+	return nil
+}
+
+// Cancel 取消尚未进入保存阶段的重启，并向玩家广播取消消息。
+func (o *Orchestrator) Cancel() (Status, error) {
+	o.mu.Lock()
+	if o.status.Phase != PhasePending && o.status.Phase != PhaseCountdown {
+		o.mu.Unlock()
+		return Status{}, ErrNotCancellable
+	}
+	o.cancel()
+	o.status.Phase = PhaseCancelled
+	o.status.NextWarning = ""
+	o.mu.Unlock()
+
+	if o.RCON != nil {
+		if _, err := o.RCON.ServerMessage("Scheduled restart cancelled."); err != nil {
+			o.note("broadcast cancel: %v", err)
+		}
+	}
+	return o.Status(), nil
+}
+
+// Wait 阻塞到当前重启流程结束（测试与关闭时使用）。
+func (o *Orchestrator) Wait() {
+	o.mu.Lock()
+	finished := o.finished
+	o.mu.Unlock()
+	if finished != nil {
+		<-finished
+	}
+}
+
+func (o *Orchestrator) run(ctx context.Context, restartAt time.Time, warnings []time.Duration, message string) {
+	if message == "" {
+		message = DefaultMessage
+	}
+
+	for _, w := range warnings {
+		ok := o.advance(ctx, func(st *Status) {
+			st.Phase = PhaseCountdown
+			st.NextWarning = FormatDuration(w)
+		})
+		if !ok || !o.sleepUntil(ctx, restartAt.Add(-w)) {
+			return
+		}
+		if o.RCON != nil {
+			if _, err := o.RCON.ServerMessage(fmt.Sprintf(message, FormatDuration(w))); err != nil {
+				o.note("broadcast %s warning: %v", FormatDuration(w), err)
+			}
+		}
+	}
+	if !o.sleepUntil(ctx, restartAt) {
+		return
+	}
+
+	// 从这里开始不可取消。
+	ok := o.advance(ctx, func(st *Status) {
+		st.Phase = PhaseSaving
+		st.NextWarning = ""
+	})
+	if !ok {
+		return
+	}
+	o.save()
+
+	o.update(func(st *Status) { st.Phase = PhaseRestarting })
+	if o.Restarter == nil {
+		o.fail(fmt.Errorf("restarter not configured"))
+		return
+	}
+	if err := o.Restarter.RestartPZServer(); err != nil {
+		o.fail(fmt.Errorf("restart: %w", err))
+		return
+	}
+
+	if err := o.waitUp(); err != nil {
+		o.fail(err)
+		return
+	}
+	o.update(func(st *Status) { st.Phase = PhaseUp })
+}
+
+// save 发出 save 并等待日志中的完成标记；失败或超时只记录，不阻止重启。
+func (o *Orchestrator) save() {
+	if o.RCON == nil {
+		o.note("save skipped: rcon not configured")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), o.saveTimeout())
+	defer cancel()
+
+	var wait func() error
+	if o.Saves != nil {
+		var err error
+		if wait, err = o.Saves.WatchSave(ctx); err != nil {
+			o.note("watch save: %v", err)
+		}
+	}
+	if _, err := o.RCON.Save(); err != nil {
+		o.note("save: %v", err)
+		return
+	}
+	if wait == nil {
+		return
+	}
+	if err := wait(); err != nil {
+		o.note("wait for save: %v", err)
+	}
+}
+
+// waitUp 轮询 RCON，直到服务器重新响应。
+func (o *Orchestrator) waitUp() error {
+	if o.RCON == nil {
+		return nil
+	}
+	deadline := o.now().Add(o.upTimeout())
+	for {
+		if _, err := o.RCON.Players(); err == nil {
+			return nil
+		}
+		if o.now().After(deadline) {
+			return fmt.Errorf("server did not come back within %s", o.upTimeout())
+		}
+		time.Sleep(o.pollInterval())
+	}
+}
+
+func (o *Orchestrator) sleepUntil(ctx context.Context, t time.Time) bool {
+	d := t.Sub(o.now())
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return ctx.Err() == nil
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (o *Orchestrator) activeLocked() bool {
+	switch o.status.Phase {
+	case PhasePending, PhaseCountdown, PhaseSaving, PhaseRestarting:
+		return true
+	}
+	return false
+}
+
+// advance 在未被取消时更新状态；与 Cancel 在同一把锁下判断，避免覆盖 cancelled。
+func (o *Orchestrator) advance(ctx context.Context, fn func(st *Status)) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if ctx.Err() != nil {
+		return false
+	}
+	o.applyLocked(fn)
+	return true
+}
+
+func (o *Orchestrator) update(fn func(st *Status)) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.applyLocked(fn)
+}
+
+// applyLocked 修改状态，并把阶段变化同步到后台任务。
+func (o *Orchestrator) applyLocked(fn func(st *Status)) {
+	prev := o.status.Phase
+	fn(&o.status)
+	if o.reporter != nil && o.status.Phase != prev {
+		o.reporter.Stage(string(o.status.Phase))
+	}
+}
+
+func (o *Orchestrator) note(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.status.Notes = append(o.status.Notes, msg)
+	if o.reporter != nil {
+		o.reporter.Log(msg)
+	}
+}
+
+func (o *Orchestrator) fail(err error) {
+	o.update(func(st *Status) {
+		st.Phase = PhaseFailed
+		st.Error = err.Error()
+	})
+}
+
+func (o *Orchestrator) now() time.Time {
+	if o.Now != nil {
+		return o.Now()
+	}
+	return time.Now()
+}
+
+func (o *Orchestrator) saveTimeout() time.Duration {
+	if o.SaveTimeout > 0 {
+		return o.SaveTimeout
+	}
+	return 2 * time.Minute
+}
+
+func (o *Orchestrator) upTimeout() time.Duration {
+	if o.UpTimeout > 0 {
+		return o.UpTimeout
+	}
+	return 5 * time.Minute
+}
+
+func (o *Orchestrator) pollInterval() time.Duration {
+	if o.PollInterval > 0 {
+		return o.PollInterval
+	}
+	return 5 * time.Second
+}
+
+// FormatDuration 以玩家可读的形式输出时长，例如 "10 minutes"、"1 minute"、"30 seconds"。
+func FormatDuration(d time.Duration) string {
+	var parts []string
+	if m := int(d / time.Minute); m > 0 {
+		parts = append(parts, plural(m, "minute"))
+		d -= time.Duration(m) * time.Minute
+	}
+	if s := int(d / time.Second); s > 0 || len(parts) == 0 {
+		parts = append(parts, plural(s, "second"))
+	}
+	return strings.Join(parts, " ")
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
@@ -0,0 +1,185 @@
+package restartapp
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"pz-web-backend/internal/jobs"
+)
+
+type fakeRCON struct {
+	mu       sync.Mutex
+	events   []string
+	down     bool
+	failSave bool
+}
+
+func (f *fakeRCON) record(e string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, e)
+}
+
+func (f *fakeRCON) ServerMessage(message string) (string, error) {
+	f.record("msg:" + message)
+	return "", nil
+}
+
+func (f *fakeRCON) Save() (string, error) {
+	f.record("save")
+	if f.failSave {
+		return "", errors.New("connection refused")
+	}
+	return "", nil
+}
+
+func (f *fakeRCON) Players() ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.down {
+		f.down = false
+		return nil, errors.New("connection refused")
+	}
+	return nil, nil
+}
+
+func (f *fakeRCON) snapshot() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.events...)
+}
+
+type fakeRestarter struct{ rcon *fakeRCON }
+
+func (r fakeRestarter) RestartPZServer() error {
+	r.rcon.record("restart")
+	r.rcon.mu.Lock()
+	r.rcon.down = true
+	r.rcon.mu.Unlock()
+	return nil
+}
+
+type fakeSaves struct{ rcon *fakeRCON }
+
+func (s fakeSaves) WatchSave(ctx context.Context) (func() error, error) {
+	return func() error {
+		s.rcon.record("saved")
+		return nil
+	}, nil
+}
+
+func TestOrchestrator_CountdownSaveRestart(t *testing.T) {
+	rcon := &fakeRCON{}
+	o := &Orchestrator{RCON: rcon, Restarter: fakeRestarter{rcon}, Saves: fakeSaves{rcon}, PollInterval: time.Millisecond}
+
+	st, err := o.Start(Options{Warnings: []time.Duration{10 * time.Millisecond, 40 * time.Millisecond}, Message: "restart in %s"})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if st.Phase != PhasePending {
+		t.Fatalf("phase=%s", st.Phase)
+	}
+	if _, err := o.Start(Options{}); !errors.Is(err, ErrInProgress) {
+		t.Fatalf("err=%v", err)
+	}
+	o.Wait()
+
+	want := []string{"msg:restart in 0 seconds", "msg:restart in 0 seconds", "save", "saved", "restart"}
+	if got := rcon.snapshot(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("events=%q", got)
+	}
+	if got := o.Status(); got.Phase != PhaseUp || len(got.Notes) != 0 {
+		t.Fatalf("status=%+v", got)
+	}
+}
+
+func TestOrchestrator_CancelDuringCountdown(t *testing.T) {
+	rcon := &fakeRCON{}
+	o := &Orchestrator{RCON: rcon, Restarter: fakeRestarter{rcon}}
+
+	if _, err := o.Start(Options{Warnings: []time.Duration{time.Hour}}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	st, err := o.Cancel()
+	if err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	o.Wait()
+	if st.Phase != PhaseCancelled || o.Status().Phase != PhaseCancelled {
+		t.Fatalf("status=%+v", o.Status())
+	}
+	for _, e := range rcon.snapshot() {
+		if e == "restart" || e == "save" {
+			t.Fatalf("events=%q", rcon.snapshot())
+		}
+	}
+	if _, err := o.Cancel(); !errors.Is(err, ErrNotCancellable) {
+		t.Fatalf("err=%v", err)
+	}
+}
+
+func TestOrchestrator_SaveFailureStillRestarts(t *testing.T) {
+	rcon := &fakeRCON{failSave: true}
+	o := &Orchestrator{RCON: rcon, Restarter: fakeRestarter{rcon}, PollInterval: time.Millisecond}
+
+	if _, err := o.Start(Options{}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	o.Wait()
+	st := o.Status()
+	if st.Phase != PhaseUp || len(st.Notes) != 1 {
+		t.Fatalf("status=%+v", st)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	cases := map[time.Duration]string{
+		10 * time.Minute:       "10 minutes",
+		time.Minute:            "1 minute",
+		90 * time.Second:       "1 minute 30 seconds",
+		10 * time.Second:       "10 seconds",
+		500 * time.Millisecond: "0 seconds",
+	}
+	for d, want := range cases {
+		if got := FormatDuration(d); got != want {
+			t.Fatalf("%s: got %q want %q", d, got, want)
+		}
+	}
+}
+
+func TestOrchestrator_RunsAsExclusiveJob(t *testing.T) {
+	rcon := &fakeRCON{}
+	m := &jobs.Manager{}
+	o := &Orchestrator{RCON: rcon, Restarter: fakeRestarter{rcon}, Jobs: m, PollInterval: time.Millisecond}
+
+	busy, _ := m.Start(jobs.Spec{Kind: "server.update", Exclusive: true}, func(ctx context.Context, r *jobs.Reporter) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if _, err := o.Start(Options{}); !errors.Is(err, jobs.ErrBusy) {
+		t.Fatalf("err=%v", err)
+	}
+	if o.Status().Phase != PhaseIdle {
+		t.Fatalf("status=%+v", o.Status())
+	}
+	_, _ = m.Cancel(busy.ID)
+	_, _ = m.Wait(busy.ID)
+
+	st, err := o.Start(Options{Warnings: []time.Duration{time.Hour}})
+	if err != nil || st.JobID == "" {
+		t.Fatalf("st=%+v err=%v", st, err)
+	}
+	// 取消任务等同于取消倒计时。
+	if _, err := m.Cancel(st.JobID); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	job, _ := m.Wait(st.JobID)
+	o.Wait()
+	if job.State != jobs.StateCancelled || job.Stage != string(PhaseCountdown) || o.Status().Phase != PhaseCancelled {
+		t.Fatalf("job=%+v status=%+v", job, o.Status())
+	}
+}
@@ -0,0 +1,114 @@
+package scheduleapp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// 执行结果。
+const (
+	RunSucceeded = "succeeded"
+	RunFailed    = "failed"
+	// RunSkipped 到点时上一次执行尚未结束。
+	RunSkipped = "skipped"
+)
+
+// 触发方式。
+const (
+	TriggerSchedule = "schedule"
+	TriggerManual   = "manual"
+)
+
+// Run 一次执行记录。
+type Run struct {
+	ID       string `json:"id"`
+	TaskID   string `json:"task_id"`
+	TaskName string `json:"task_name"`
+	Action   Action `json:"action"`
+	Trigger  string `json:"trigger"`
+	// ScheduledAt 计划触发时间，手动执行时与 StartedAt 相同。
+	ScheduledAt time.Time `json:"scheduled_at"`
+	StartedAt   time.Time `json:"started_at"`
+	EndedAt     time.Time `json:"ended_at"`
+	Status      string    `json:"status"`
+	Output      string    `json:"output,omitempty"`
+	Error       string    `json:"error,omitempty"`
+}
+
+type RunStore interface {
+	Add(r Run) error
+	// Recent 按时间倒序返回最近 limit 条；taskID 非空时只返回该任务的记录，limit <= 0 返回全部。
+	Recent(taskID string, limit int) ([]Run, error)
+}
+
+// FileRunStore 每行一个 JSON 对象，只追加不改写。
+type FileRunStore struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+func (s *FileRunStore) Add(r Run) error {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (s *FileRunStore) Recent(taskID string, limit int) ([]Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := os.Open(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Run{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var all []Run
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		var r Run
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			continue
+		}
+		if taskID != "" && r.TaskID != taskID {
+			continue
+		}
+		all = append(all, r)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	out := make([]Run, 0, len(all))
+	for i := len(all) - 1; i >= 0; i-- {
+		if limit > 0 && len(out) == limit {
+			break
+		}
+		out = append(out, all[i])
+	}
+	return out, nil
+}
@@ -0,0 +1,482 @@
+// Package scheduleapp 按 cron 表达式定时执行面板操作（计划重启、RCON 广播、备份、SteamCMD 更新、创意工坊检查），
+// 任务与执行记录保存在磁盘上。
+package scheduleapp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"pz-web-backend/internal/application/backupapp"
+	"pz-web-backend/internal/application/modsapp"
+	"pz-web-backend/internal/application/restartapp"
+	"pz-web-backend/internal/jobs"
+)
+
+// PreviewCount 任务详情中预览的触发次数。
+const PreviewCount = 5
+
+type Restarter interface {
+	Start(opts restartapp.Options) (restartapp.Status, error)
+}
+
+type Messenger interface {
+	ServerMessage(message string) (string, error)
+}
+
+type Backuper interface {
+	Create(ctx context.Context) (backupapp.Backup, error)
+}
+
+type SteamUpdater interface {
+	Start() (jobs.Job, error)
+}
+
+type WorkshopChecker interface {
+	CheckUpdates() (modsapp.UpdateReport, error)
+}
+
+// TaskStatus 任务及其下次触发时间、最近一次执行。
+type TaskStatus struct {
+	Task
+	NextRuns []time.Time `json:"next_runs"`
+	Running  bool        `json:"running"`
+	LastRun  *Run        `json:"last_run,omitempty"`
+}
+
+// State 调度循环最近一次检查的结果；Error 非空时（例如任务文件无法读取）所有任务都不会触发。
+type State struct {
+	LastTick time.Time `json:"last_tick"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// Scheduler 定期检查到点的任务并在后台执行；面板停机期间错过的触发不会补跑。
+type Scheduler struct {
+	Tasks TaskStore
+	Runs  RunStore
+
+	Restart     Restarter
+	RCON        Messenger
+	Backup      Backuper
+	SteamUpdate SteamUpdater
+	Workshop    WorkshopChecker
+
+	// Interval 检查间隔，默认 10s。
+	Interval time.Duration
+	Now      func() time.Time
+
+	mu      sync.Mutex
+	loaded  bool
+	tasks   []Task
+	next    map[string]time.Time
+	running map[string]bool
+	state   State
+	wg      sync.WaitGroup
+}
+
+// Start 在后台运行调度循环，ctx 结束时退出。
+func (s *Scheduler) Start(ctx context.Context) {
+	go func() {
+		interval := s.Interval
+		if interval <= 0 {
+			interval = 10 * time.Second
+		}
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick.C:
+				s.Tick(ctx)
+			}
+		}
+	}()
+}
+
+// Tick 执行所有已到点的任务。
+func (s *Scheduler) Tick(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.state = State{LastTick: now}
+	if err := s.loadLocked(); err != nil {
+		s.state.Error = fmt.Sprintf("load tasks: %v", err)
+		return
+	}
+	for _, t := range s.tasks {
+		if !t.Enabled {
+			continue
+		}
+		due, ok := s.next[t.ID]
+		if !ok || due.After(now) {
+			continue
+		}
+		s.scheduleLocked(t, now)
+		s.fireLocked(ctx, t, TriggerSchedule, due)
+	}
+}
+
+// State 返回调度循环的状态。
+func (s *Scheduler) State() State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
+// Wait 等待所有正在执行的任务结束。
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+// List 按名称返回全部任务。
+func (s *Scheduler) List() ([]TaskStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.loadLocked(); err != nil {
+		return nil, err
+	}
+	out := make([]TaskStatus, 0, len(s.tasks))
+	for _, t := range s.tasks {
+		out = append(out, s.statusLocked(t))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+func (s *Scheduler) Get(id string) (TaskStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.loadLocked(); err != nil {
+		return TaskStatus{}, err
+	}
+	i := s.indexLocked(id)
+	if i < 0 {
+		return TaskStatus{}, ErrNotFound
+	}
+	return s.statusLocked(s.tasks[i]), nil
+}
+
+// Create 校验并保存新任务，ID 与时间戳由 Scheduler 生成。
+func (s *Scheduler) Create(t Task) (TaskStatus, error) {
+	if err := t.Validate(); err != nil {
+		return TaskStatus{}, err
+	}
+	id, err := newID()
+	if err != nil {
+		return TaskStatus{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.loadLocked(); err != nil {
+		return TaskStatus{}, err
+	}
+	now := s.now()
+	t.ID = id
+	t.CreatedAt = now
+	t.UpdatedAt = now
+	tasks := append(append([]Task{}, s.tasks...), t)
+	if err := s.Tasks.Save(tasks); err != nil {
+		return TaskStatus{}, err
+	}
+	s.tasks = tasks
+	s.scheduleLocked(t, now)
+	return s.statusLocked(t), nil
+}
+
+// Update 整体替换任务内容，保留 ID 与创建时间。
+func (s *Scheduler) Update(id string, t Task) (TaskStatus, error) {
+	if err := t.Validate(); err != nil {
+		return TaskStatus{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.loadLocked(); err != nil {
+		return TaskStatus{}, err
+	}
+	i := s.indexLocked(id)
+	if i < 0 {
+		return TaskStatus{}, ErrNotFound
+	}
+	now := s.now()
+	t.ID = id
+	t.CreatedAt = s.tasks[i].CreatedAt
+	t.UpdatedAt = now
+	tasks := append([]Task{}, s.tasks...)
+	tasks[i] = t
+	if err := s.Tasks.Save(tasks); err != nil {
+		return TaskStatus{}, err
+	}
+	s.tasks = tasks
+	s.scheduleLocked(t, now)
+	return s.statusLocked(t), nil
+}
+
+// Delete 删除任务；正在执行的那一次不受影响，执行记录保留。
+func (s *Scheduler) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.loadLocked(); err != nil {
+		return err
+	}
+	i := s.indexLocked(id)
+	if i < 0 {
+		return ErrNotFound
+	}
+	tasks := append(append([]Task{}, s.tasks[:i]...), s.tasks[i+1:]...)
+	if err := s.Tasks.Save(tasks); err != nil {
+		return err
+	}
+	s.tasks = tasks
+	delete(s.next, id)
+	return nil
+}
+
+// RunNow 立即在后台执行一次（不影响下次计划触发），结果写入执行记录。
+func (s *Scheduler) RunNow(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.loadLocked(); err != nil {
+		return err
+	}
+	i := s.indexLocked(id)
+	if i < 0 {
+		return ErrNotFound
+	}
+	if s.running[id] {
+		return ErrRunning
+	}
+	s.fireLocked(ctx, s.tasks[i], TriggerManual, s.now())
+	return nil
+}
+
+// History 返回执行记录，taskID 为空时返回全部任务的记录。
+func (s *Scheduler) History(taskID string, limit int) ([]Run, error) {
+	return s.Runs.Recent(taskID, limit)
+}
+
+// Preview 计算任意表达式之后 n 次的触发时间，用于编辑任务时预览。
+func (s *Scheduler) Preview(expr string, timezone string, n int) ([]time.Time, error) {
+	t := Task{Cron: expr, Timezone: timezone}
+	sched, loc, err := t.schedule()
+	if err != nil {
+		return nil, err
+	}
+	return sched.NextN(s.now().In(loc), n), nil
+}
+
+func (s *Scheduler) loadLocked() error {
+	if s.loaded {
+		return nil
+	}
+	tasks, err := s.Tasks.Load()
+	if err != nil {
+		return err
+	}
+	s.tasks = tasks
+	s.next = make(map[string]time.Time)
+	s.running = make(map[string]bool)
+	s.loaded = true
+	now := s.now()
+	for _, t := range tasks {
+		s.scheduleLocked(t, now)
+	}
+	return nil
+}
+
+// scheduleLocked 计算 now 之后的下一次触发；表达式无效或永不触发时移除。
+func (s *Scheduler) scheduleLocked(t Task, now time.Time) {
+	delete(s.next, t.ID)
+	sched, loc, err := t.schedule()
+	if err != nil {
+		return
+	}
+	if next := sched.Next(now.In(loc)); !next.IsZero() {
+		s.next[t.ID] = next
+	}
+}
+
+func (s *Scheduler) statusLocked(t Task) TaskStatus {
+	st := TaskStatus{Task: t, NextRuns: []time.Time{}, Running: s.running[t.ID]}
+	if t.Enabled {
+		if sched, loc, err := t.schedule(); err == nil {
+			st.NextRuns = sched.NextN(s.now().In(loc), PreviewCount)
+		}
+	}
+	// 读取失败不影响返回任务本身。
+	if runs, err := s.Runs.Recent(t.ID, 1); err == nil && len(runs) > 0 {
+		st.LastRun = &runs[0]
+	}
+	return st
+}
+
+func (s *Scheduler) indexLocked(id string) int {
+	for i, t := range s.tasks {
+		if t.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// fireLocked 在后台执行任务；上一次尚未结束时只记录一条 skipped。
+func (s *Scheduler) fireLocked(ctx context.Context, t Task, trigger string, scheduledAt time.Time) {
+	run := Run{
+		TaskID:      t.ID,
+		TaskName:    t.Name,
+		Action:      t.Action,
+		Trigger:     trigger,
+		ScheduledAt: scheduledAt,
+		StartedAt:   s.now(),
+	}
+	if s.running[t.ID] {
+		run.EndedAt = run.StartedAt
+		run.Status = RunSkipped
+		run.Error = ErrRunning.Error()
+		s.record(run)
+		return
+	}
+
+	s.running[t.ID] = true
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		output, err := s.execute(ctx, t)
+		run.EndedAt = s.now()
+		run.Output = output
+		run.Status = RunSucceeded
+		if err != nil {
+			run.Status = RunFailed
+			run.Error = err.Error()
+		}
+		s.record(run)
+
+		s.mu.Lock()
+		delete(s.running, t.ID)
+		s.mu.Unlock()
+	}()
+}
+
+func (s *Scheduler) execute(ctx context.Context, t Task) (string, error) {
+	switch t.Action {
+	case ActionRestart:
+		return s.restart(t, "scheduled: "+t.Name)
+
+	case ActionRCONMessage:
+		if s.RCON == nil {
+			return "", errNotConfigured(t.Action)
+		}
+		return s.RCON.ServerMessage(t.Params.Message)
+
+	case ActionBackup:
+		if s.Backup == nil {
+			return "", errNotConfigured(t.Action)
+		}
+		b, err := s.Backup.Create(ctx)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("created %s (%d bytes)", b.Name, b.Size), nil
+
+	case ActionSteamUpdate:
+		if s.SteamUpdate == nil {
+			return "", errNotConfigured(t.Action)
+		}
+		job, err := s.SteamUpdate.Start()
+		if err != nil {
+			return "", err
+		}
+		return "started job " + job.ID, nil
+
+	case ActionWorkshopCheck:
+		if s.Workshop == nil {
+			return "", errNotConfigured(t.Action)
+		}
+		report, err := s.Workshop.CheckUpdates()
+		if err != nil {
+			return "", err
+		}
+		out := fmt.Sprintf("%d workshop items", len(report.Items))
+		if len(report.Outdated) > 0 {
+			out += "; outdated: " + strings.Join(report.Outdated, ",")
+		}
+		if len(report.Missing) > 0 {
+			out += "; not installed: " + strings.Join(report.Missing, ",")
+		}
+		if len(report.Removed) > 0 {
+			out += "; removed from workshop: " + strings.Join(report.Removed, ",")
+		}
+		if !t.Params.RestartOnUpdate || !report.NeedsRestart() {
+			return out, nil
+		}
+		msg, err := s.restart(t, fmt.Sprintf("scheduled: %s (workshop updates: %s)", t.Name, strings.Join(report.Outdated, ",")))
+		if errors.Is(err, restartapp.ErrInProgress) {
+			return out + "; restart already in progress", nil
+		}
+		if err != nil {
+			return out, err
+		}
+		return out + "; " + msg, nil
+	}
+	return "", fmt.Errorf("%w: unknown action %q", ErrInvalidTask, t.Action)
+}
+
+// restart 按任务的 Message / Warnings 发起带倒计时的重启。
+func (s *Scheduler) restart(t Task, reason string) (string, error) {
+	if s.Restart == nil {
+		return "", errNotConfigured(ActionRestart)
+	}
+	opts := restartapp.Options{
+		Warnings:    restartapp.DefaultWarnings,
+		Message:     t.Params.Message,
+		Reason:      reason,
+		RequestedBy: "scheduler",
+	}
+	warnings, err := t.Params.warnings()
+	if err != nil {
+		return "", err
+	}
+	if warnings != nil {
+		opts.Warnings = warnings
+	}
+	st, err := s.Restart.Start(opts)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("restart at %s (job %s)", st.RestartAt.Format(time.RFC3339), st.JobID), nil
+}
+
+// record 写入执行记录；写入失败只能丢弃，不影响调度。
+func (s *Scheduler) record(r Run) {
+	if r.ID == "" {
+		r.ID, _ = newID()
+	}
+	_ = s.Runs.Add(r)
+}
+
+func (s *Scheduler) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
+
+func errNotConfigured(a Action) error {
+	return errors.New(string(a) + " is not configured")
+}
+
+func newID() (string, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b[:]), nil
+}
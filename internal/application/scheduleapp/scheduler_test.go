@@ -0,0 +1,265 @@
+package scheduleapp
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"pz-web-backend/internal/application/backupapp"
+	"pz-web-backend/internal/application/modsapp"
+	"pz-web-backend/internal/application/restartapp"
+)
+
+type fakeRCON struct{ messages []string }
+
+func (f *fakeRCON) ServerMessage(message string) (string, error) {
+	f.messages = append(f.messages, message)
+	return "Message sent.", nil
+}
+
+type fakeRestart struct{ opts []restartapp.Options }
+
+func (f *fakeRestart) Start(opts restartapp.Options) (restartapp.Status, error) {
+	f.opts = append(f.opts, opts)
+	return restartapp.Status{JobID: "j1"}, nil
+}
+
+type blockingBackup struct{ release chan struct{} }
+
+func (b blockingBackup) Create(ctx context.Context) (backupapp.Backup, error) {
+	<-b.release
+	return backupapp.Backup{}, errors.New("disk full")
+}
+
+type fakeWorkshop struct{ report modsapp.UpdateReport }
+
+func (f fakeWorkshop) CheckUpdates() (modsapp.UpdateReport, error) { return f.report, nil }
+
+func newScheduler(t *testing.T, now *time.Time) *Scheduler {
+	dir := t.TempDir()
+	return &Scheduler{
+		Tasks: &FileTaskStore{Path: filepath.Join(dir, "schedules.json")},
+		Runs:  &FileRunStore{Path: filepath.Join(dir, "schedule-runs.log")},
+		Now:   func() time.Time { return *now },
+	}
+}
+
+func TestScheduler_FiresDueTasksInTheirTimezone(t *testing.T) {
+	now := time.Date(2024, 1, 1, 1, 59, 0, 0, time.UTC)
+	s := newScheduler(t, &now)
+	rcon := &fakeRCON{}
+	restart := &fakeRestart{}
+	s.RCON = rcon
+	s.Restart = restart
+
+	msg, err := s.Create(Task{Name: "hello", Cron: "0 * * * *", Action: ActionRCONMessage, Params: Params{Message: "hi"}, Enabled: true})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	// 上海 10:00 = UTC 02:00。
+	_, err = s.Create(Task{Name: "restart", Cron: "0 10 * * *", Timezone: "Asia/Shanghai", Action: ActionRestart,
+		Params: Params{Warnings: []string{"5m"}}, Enabled: true})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := s.Create(Task{Name: "off", Cron: "* * * * *", Action: ActionRCONMessage, Params: Params{Message: "x"}}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if len(msg.NextRuns) != PreviewCount || !msg.NextRuns[0].Equal(time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC)) {
+		t.Fatalf("next=%v", msg.NextRuns)
+	}
+
+	s.Tick(context.Background())
+	s.Wait()
+	if len(rcon.messages) != 0 {
+		t.Fatalf("fired too early")
+	}
+
+	now = now.Add(90 * time.Second)
+	s.Tick(context.Background())
+	s.Wait()
+	// 同一分钟内再次检查不会重复触发。
+	s.Tick(context.Background())
+	s.Wait()
+	if len(rcon.messages) != 1 || len(restart.opts) != 1 || restart.opts[0].Warnings[0] != 5*time.Minute {
+		t.Fatalf("messages=%v restart=%+v", rcon.messages, restart.opts)
+	}
+
+	runs, err := s.History("", 0)
+	if err != nil || len(runs) != 2 {
+		t.Fatalf("runs=%+v err=%v", runs, err)
+	}
+	st, _ := s.Get(msg.ID)
+	if st.LastRun == nil || st.LastRun.Status != RunSucceeded || st.LastRun.Output != "Message sent." ||
+		!st.LastRun.ScheduledAt.Equal(time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC)) {
+		t.Fatalf("last=%+v", st.LastRun)
+	}
+	if !st.NextRuns[0].Equal(time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC)) {
+		t.Fatalf("next=%v", st.NextRuns)
+	}
+}
+
+func TestScheduler_PersistsTasksAndSkipsOverlappingRuns(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := newScheduler(t, &now)
+	backup := blockingBackup{release: make(chan struct{})}
+	s.Backup = backup
+
+	task, err := s.Create(Task{Name: "backup", Cron: "@hourly", Action: ActionBackup, Enabled: true})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := s.RunNow(context.Background(), task.ID); err != nil {
+		t.Fatalf("RunNow: %v", err)
+	}
+	if err := s.RunNow(context.Background(), task.ID); !errors.Is(err, ErrRunning) {
+		t.Fatalf("err=%v", err)
+	}
+	now = now.Add(time.Hour)
+	s.Tick(context.Background())
+	close(backup.release)
+	s.Wait()
+
+	runs, _ := s.History(task.ID, 0)
+	if len(runs) != 2 || runs[0].Status != RunFailed || runs[0].Error != "disk full" || runs[0].Trigger != TriggerManual ||
+		runs[1].Status != RunSkipped || runs[1].Trigger != TriggerSchedule {
+		t.Fatalf("runs=%+v", runs)
+	}
+
+	// 新实例从磁盘读取任务。
+	reloaded := &Scheduler{Tasks: s.Tasks, Runs: s.Runs, Now: s.Now}
+	list, err := reloaded.List()
+	if err != nil || len(list) != 1 || list[0].ID != task.ID || list[0].LastRun == nil {
+		t.Fatalf("list=%+v err=%v", list, err)
+	}
+
+	task.Cron = "0 0 * * *"
+	if _, err := reloaded.Update(task.ID, task.Task); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if err := reloaded.Delete(task.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := reloaded.Get(task.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err=%v", err)
+	}
+}
+
+func TestScheduler_WorkshopCheckRestartsOnUpdate(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := newScheduler(t, &now)
+	restart := &fakeRestart{}
+	s.Restart = restart
+	s.Workshop = fakeWorkshop{report: modsapp.UpdateReport{
+		Items:    make([]modsapp.ItemUpdate, 3),
+		Outdated: []string{"1"},
+		Missing:  []string{},
+		Removed:  []string{"3"},
+	}}
+
+	check, err := s.Create(Task{Name: "check", Cron: "@hourly", Action: ActionWorkshopCheck})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	policy, err := s.Create(Task{Name: "mods", Cron: "@hourly", Action: ActionWorkshopCheck,
+		Params: Params{RestartOnUpdate: true, Warnings: []string{"5m"}}})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	for _, id := range []string{check.ID, policy.ID} {
+		if err := s.RunNow(context.Background(), id); err != nil {
+			t.Fatalf("RunNow: %v", err)
+		}
+		s.Wait()
+	}
+
+	runs, _ := s.History(check.ID, 1)
+	if len(runs) != 1 || runs[0].Output != "3 workshop items; outdated: 1; removed from workshop: 3" {
+		t.Fatalf("runs=%+v", runs)
+	}
+	if len(restart.opts) != 1 || restart.opts[0].Reason != "scheduled: mods (workshop updates: 1)" ||
+		len(restart.opts[0].Warnings) != 1 {
+		t.Fatalf("opts=%+v", restart.opts)
+	}
+	runs, _ = s.History(policy.ID, 1)
+	if len(runs) != 1 || runs[0].Status != RunSucceeded || !strings.Contains(runs[0].Output, "restart at") {
+		t.Fatalf("runs=%+v", runs)
+	}
+
+	// 下载不下来的条目一直是 missing，不能每次检查都重启。
+	s.Workshop = fakeWorkshop{report: modsapp.UpdateReport{
+		Items:    make([]modsapp.ItemUpdate, 1),
+		Outdated: []string{},
+		Missing:  []string{"2"},
+	}}
+	if err := s.RunNow(context.Background(), policy.ID); err != nil {
+		t.Fatalf("RunNow: %v", err)
+	}
+	s.Wait()
+	if len(restart.opts) != 1 {
+		t.Fatalf("opts=%+v", restart.opts)
+	}
+}
+
+func TestScheduler_TickRecordsLoadError(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := newScheduler(t, &now)
+	path := s.Tasks.(*FileTaskStore).Path
+	if err := os.WriteFile(path, []byte("{broken"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s.Tick(context.Background())
+	if st := s.State(); !st.LastTick.Equal(now) || !strings.HasPrefix(st.Error, "load tasks: ") {
+		t.Fatalf("state=%+v", st)
+	}
+
+	if err := os.WriteFile(path, []byte("[]"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Minute)
+	s.Tick(context.Background())
+	if st := s.State(); !st.LastTick.Equal(now) || st.Error != "" {
+		t.Fatalf("state=%+v", st)
+	}
+}
+
+func TestTask_Validate(t *testing.T) {
+	cases := []Task{
+		{Name: "", Cron: "* * * * *", Action: ActionBackup},
+		{Name: "a", Cron: "* * *", Action: ActionBackup},
+		{Name: "a", Cron: "* * * * *", Timezone: "Mars/Olympus", Action: ActionBackup},
+		{Name: "a", Cron: "* * * * *", Action: "reboot"},
+		{Name: "a", Cron: "* * * * *", Action: ActionRCONMessage},
+		{Name: "a", Cron: "* * * * *", Action: ActionRestart, Params: Params{Warnings: []string{"soon"}}},
+		{Name: "a", Cron: "* * * * *", Action: ActionRestart, Params: Params{Message: "no placeholder"}},
+		{Name: "a", Cron: "* * * * *", Action: ActionWorkshopCheck, Params: Params{RestartOnUpdate: true, Warnings: []string{"soon"}}},
+	}
+	for _, c := range cases {
+		if err := c.Validate(); !errors.Is(err, ErrInvalidTask) {
+			t.Fatalf("task=%+v err=%v", c, err)
+		}
+	}
+	if err := (Task{Name: "a", Cron: "@daily", Timezone: "Europe/Berlin", Action: ActionWorkshopCheck}).Validate(); err != nil {
+		t.Fatalf("err=%v", err)
+	}
+}
+
+func TestScheduler_Preview(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	s := newScheduler(t, &now)
+	got, err := s.Preview("0 */6 * * *", "UTC", 3)
+	if err != nil {
+		t.Fatalf("Preview: %v", err)
+	}
+	var s2 []string
+	for _, g := range got {
+		s2 = append(s2, g.Format("15:04"))
+	}
+	if strings.Join(s2, ",") != "18:00,00:00,06:00" {
+		t.Fatalf("got=%v", s2)
+	}
+}
@@ -0,0 +1,177 @@
+package scheduleapp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"pz-web-backend/internal/cron"
+	"pz-web-backend/internal/infra/fs"
+)
+
+var (
+	ErrNotFound = errors.New("scheduled task not found")
+	// ErrInvalidTask 表达式、时区、动作或参数不合法。
+	ErrInvalidTask = errors.New("invalid scheduled task")
+	// ErrRunning 该任务上一次执行尚未结束。
+	ErrRunning = errors.New("scheduled task is already running")
+)
+
+type Action string
+
+const (
+	// ActionRestart 带倒计时的计划重启。
+	ActionRestart       Action = "restart"
+	ActionRCONMessage   Action = "rcon_message"
+	ActionBackup        Action = "backup"
+	ActionSteamUpdate   Action = "steam_update"
+	ActionWorkshopCheck Action = "workshop_check"
+)
+
+// Params 动作参数，未用到的字段忽略。
+type Params struct {
+	// Message rcon_message 的广播内容；restart 的倒计时模板（恰好一个 %s）。
+	Message string `json:"message,omitempty"`
+	// Warnings restart 的倒计时提醒点（如 "10m"）；省略时使用默认值，空数组表示立即重启。
+	Warnings []string `json:"warnings"`
+	// RestartOnUpdate workshop_check 发现过期的条目时发起重启（使用 Message / Warnings 倒计时），
+	// 服务器启动时会下载更新。
+	RestartOnUpdate bool `json:"restart_on_update,omitempty"`
+}
+
+// Task 一条定时任务。
+type Task struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Cron string `json:"cron"`
+	// Timezone IANA 时区名（如 "Asia/Shanghai"），为空时使用面板所在时区。
+	Timezone  string    `json:"timezone,omitempty"`
+	Action    Action    `json:"action"`
+	Params    Params    `json:"params"`
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate 检查表达式、时区与动作参数。
+func (t Task) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidTask)
+	}
+	if _, _, err := t.schedule(); err != nil {
+		return err
+	}
+	switch t.Action {
+	case ActionRCONMessage:
+		if strings.TrimSpace(t.Params.Message) == "" {
+			return fmt.Errorf("%w: message is required", ErrInvalidTask)
+		}
+	case ActionRestart:
+		return t.Params.validateRestart()
+	case ActionWorkshopCheck:
+		if t.Params.RestartOnUpdate {
+			return t.Params.validateRestart()
+		}
+	case ActionBackup, ActionSteamUpdate:
+	default:
+		return fmt.Errorf("%w: unknown action %q", ErrInvalidTask, t.Action)
+	}
+	return nil
+}
+
+// schedule 解析表达式与时区。
+func (t Task) schedule() (cron.Schedule, *time.Location, error) {
+	sched, err := cron.Parse(t.Cron)
+	if err != nil {
+		return cron.Schedule{}, nil, fmt.Errorf("%w: %v", ErrInvalidTask, err)
+	}
+	loc, err := LoadLocation(t.Timezone)
+	if err != nil {
+		return cron.Schedule{}, nil, err
+	}
+	return sched, loc, nil
+}
+
+// LoadLocation 空字符串表示面板所在时区。
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown timezone %q", ErrInvalidTask, name)
+	}
+	return loc, nil
+}
+
+// validateRestart 检查重启的倒计时模板与提醒点。
+func (p Params) validateRestart() error {
+	if p.Message != "" && strings.Count(p.Message, "%s") != 1 {
+		return fmt.Errorf("%w: message must contain exactly one %%s placeholder", ErrInvalidTask)
+	}
+	_, err := p.warnings()
+	return err
+}
+
+// warnings 为 nil 表示使用默认倒计时。
+func (p Params) warnings() ([]time.Duration, error) {
+	if p.Warnings == nil {
+		return nil, nil
+	}
+	out := make([]time.Duration, 0, len(p.Warnings))
+	for _, w := range p.Warnings {
+		d, err := time.ParseDuration(w)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("%w: invalid warning duration %q", ErrInvalidTask, w)
+		}
+		out = append(out, d)
+	}
+	return out, nil
+}
+
+type TaskStore interface {
+	Load() ([]Task, error)
+	Save(tasks []Task) error
+}
+
+// FileTaskStore 把全部任务保存为一个 JSON 数组；文件不存在时视为没有任务。
+type FileTaskStore struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+func (s *FileTaskStore) Load() ([]Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Task{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var tasks []Task
+	if err := json.Unmarshal(data, &tasks); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", s.Path, err)
+	}
+	return tasks, nil
+}
+
+func (s *FileTaskStore) Save(tasks []Task) error {
+	data, err := json.MarshalIndent(tasks, "", "  ")
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o755); err != nil {
+		return err
+	}
+	return fs.WriteFileAtomic(s.Path, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,83 @@
+package steamupdateapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"pz-web-backend/internal/infra/executil"
+	"pz-web-backend/internal/infra/steamcmd"
+	"pz-web-backend/internal/infra/supervisor"
+	"pz-web-backend/internal/jobs"
+)
+
+// JobKind 更新任务在 jobs.Manager 中的类型。
+const JobKind = "server.update"
+
+// 任务阶段，见 jobs.Job.Stage。
+const (
+	StageStopping = "stopping"
+	StageUpdating = "updating"
+	StageStarting = "starting"
+)
+
+// Service 以独占任务的形式执行“停服 → SteamCMD 更新 → 启动”。
+type Service struct {
+	Runner   executil.StreamRunner
+	Server   supervisor.Controller
+	SteamCMD steamcmd.Options
+	Jobs     *jobs.Manager
+}
+
+// Start 在后台启动更新任务并返回初始快照；已有独占任务时返回 jobs.ErrBusy。
+func (s Service) Start() (jobs.Job, error) {
+	if s.Jobs == nil {
+		return jobs.Job{}, fmt.Errorf("job manager not configured")
+	}
+	return s.Jobs.Start(jobs.Spec{Kind: JobKind, Exclusive: true}, s.run)
+}
+
+func (s Service) run(ctx context.Context, r *jobs.Reporter) error {
+	if s.Server == nil || s.Runner == nil {
+		return fmt.Errorf("update job not configured")
+	}
+	r.Stage(StageStopping)
+	if err := s.Server.StopPZServer(); err != nil {
+		return fmt.Errorf("stop server: %w", err)
+	}
+
+	// 更新失败或被取消也要把已停止的服务器启动回来。
+	err := s.update(ctx, r)
+	r.Stage(StageStarting)
+	if startErr := s.Server.StartPZServer(); startErr != nil {
+		err = errors.Join(err, fmt.Errorf("start server: %w", startErr))
+	}
+	return err
+}
+
+// update 执行 SteamCMD 并把输出转成任务日志与进度。
+func (s Service) update(ctx context.Context, r *jobs.Reporter) error {
+	r.Stage(StageUpdating)
+	name, args := s.SteamCMD.Command()
+	result := steamcmd.ResultNone
+	err := s.Runner.Stream(ctx, func(line string) {
+		if res := steamcmd.ParseResult(line); res != steamcmd.ResultNone {
+			result = res
+		}
+		r.Log(line)
+		if p, ok := steamcmd.ParseProgress(line); ok {
+			r.Progress(p)
+		}
+	}, name, args...)
+	switch {
+	case ctx.Err() != nil:
+		return ctx.Err()
+	case err != nil:
+		return fmt.Errorf("steamcmd: %w", err)
+	case result == steamcmd.ResultError:
+		return fmt.Errorf("steamcmd reported an error, see output")
+	case result == steamcmd.ResultNone:
+		return fmt.Errorf("steamcmd exited without reporting success")
+	}
+	return nil
+}
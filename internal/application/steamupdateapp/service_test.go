@@ -0,0 +1,128 @@
+package steamupdateapp
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"pz-web-backend/internal/infra/steamcmd"
+	"pz-web-backend/internal/jobs"
+)
+
+type fakeServer struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (f *fakeServer) record(c string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, c)
+	return nil
+}
+
+func (f *fakeServer) RestartPZServer() error { return f.record("restart") }
+func (f *fakeServer) StopPZServer() error    { return f.record("stop") }
+func (f *fakeServer) StartPZServer() error   { return f.record("start") }
+
+// scriptRunner 按 lines 输出；release 非空时先阻塞，便于测试并发启动与取消。
+type scriptRunner struct {
+	lines   []string
+	err     error
+	release chan struct{}
+	args    []string
+}
+
+func (r *scriptRunner) Stream(ctx context.Context, onLine func(string), name string, args ...string) error {
+	r.args = append([]string{name}, args...)
+	if r.release != nil {
+		select {
+		case <-r.release:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	for _, l := range r.lines {
+		onLine(l)
+	}
+	return r.err
+}
+
+func TestService_UpdateReportsProgressAndRestarts(t *testing.T) {
+	server := &fakeServer{}
+	runner := &scriptRunner{
+		release: make(chan struct{}),
+		lines: []string{
+			"Loading Steam API...OK",
+			" Update state (0x61) downloading, progress: 50.00 (5 / 10)",
+			"Success! App '380870' fully installed.",
+		},
+	}
+	svc := Service{Runner: runner, Server: server, Jobs: &jobs.Manager{}}
+
+	job, err := svc.Start()
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if _, err := svc.Start(); !errors.Is(err, jobs.ErrBusy) {
+		t.Fatalf("err=%v", err)
+	}
+	close(runner.release)
+
+	done, err := svc.Jobs.Wait(job.ID)
+	if err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	p, _ := done.Progress.(steamcmd.Progress)
+	if done.State != jobs.StateSucceeded || done.Kind != JobKind || p.Percent != 50 || len(done.Output) != 3 {
+		t.Fatalf("job=%+v", done)
+	}
+	if !reflect.DeepEqual(server.calls, []string{"stop", "start"}) {
+		t.Fatalf("calls=%v", server.calls)
+	}
+	if runner.args[len(runner.args)-1] != "+quit" {
+		t.Fatalf("args=%v", runner.args)
+	}
+}
+
+func TestService_FailedUpdateStillStartsServer(t *testing.T) {
+	server := &fakeServer{}
+	runner := &scriptRunner{lines: []string{"Error! App '380870' state is 0x202 after update job."}}
+	svc := Service{Runner: runner, Server: server, Jobs: &jobs.Manager{}}
+
+	job, err := svc.Start()
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	done, _ := svc.Jobs.Wait(job.ID)
+	if done.State != jobs.StateFailed || !strings.Contains(done.Error, "steamcmd") {
+		t.Fatalf("job=%+v", done)
+	}
+	if !reflect.DeepEqual(server.calls, []string{"stop", "start"}) {
+		t.Fatalf("calls=%v", server.calls)
+	}
+}
+
+func TestService_CancelStillStartsServer(t *testing.T) {
+	server := &fakeServer{}
+	runner := &scriptRunner{release: make(chan struct{})}
+	svc := Service{Runner: runner, Server: server, Jobs: &jobs.Manager{}}
+
+	job, err := svc.Start()
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if _, err := svc.Jobs.Cancel(job.ID); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	done, _ := svc.Jobs.Wait(job.ID)
+	if done.State != jobs.StateCancelled {
+		t.Fatalf("job=%+v", done)
+	}
+	if !reflect.DeepEqual(server.calls, []string{"stop", "start"}) {
+		t.Fatalf("calls=%v", server.calls)
+	}
+}
@@ -1,6 +1,7 @@
 package updateapp
 
 import (
+	"context"
 	"io"
 	"net/http"
 	"time"
@@ -37,9 +38,13 @@ func (s Service) CheckUpdate() (string, string, error) {
 	return checker.CheckUpdate()
 }
 
-func (s Service) PerformUpdate(downloadURL string) error {
-	client := s.httpClient()
-	resp, err := client.Get(downloadURL)
+// PerformUpdate 下载新版本并替换当前二进制，成功后退出进程由 supervisor 拉起。
+func (s Service) PerformUpdate(ctx context.Context, downloadURL string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, downloadURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.httpClient().Do(req)
 	if err != nil {
 		return err
 	}
@@ -2,6 +2,7 @@ package updateapp
 
 import (
 	"bytes"
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -80,7 +81,7 @@ func TestService_PerformUpdate_DevMode_NoReplace(t *testing.T) {
 		},
 	}
 
-	if err := svc.PerformUpdate(srv.URL); err != nil {
+	if err := svc.PerformUpdate(context.Background(), srv.URL); err != nil {
 		t.Fatalf("err=%v", err)
 	}
 	if _, err := os.Stat(tmpPath); err != nil {
@@ -111,7 +112,7 @@ func TestService_PerformUpdate_ReplacesBinaryAndExit(t *testing.T) {
 		exit:       func(code int) { close(done) },
 	}
 
-	if err := svc.PerformUpdate(srv.URL); err != nil {
+	if err := svc.PerformUpdate(context.Background(), srv.URL); err != nil {
 		t.Fatalf("err=%v", err)
 	}
 
@@ -0,0 +1,117 @@
+package watchdogapp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"pz-web-backend/internal/infra/procmgr"
+)
+
+// 事故类型。
+const (
+	KindCrash = "crash"
+	KindHang  = "hang"
+	// KindExitLoop 一小时内自动重启次数达到上限，或进程管理器报告启动失败（BACKOFF/FATAL 等）。
+	KindExitLoop = "exit_loop"
+	// KindUnexpectedRestart 看门狗两次检查之间 PID 变了，通常是进程管理器自己拉起的。
+	KindUnexpectedRestart = "unexpected_restart"
+)
+
+// 看门狗对事故采取的动作。
+const (
+	ActionNone      = "none"
+	ActionRestarted = "restarted"
+	ActionFailed    = "failed"
+	// ActionSkipped 策略关闭了自动重启。
+	ActionSkipped = "skipped"
+	// ActionGaveUp 达到每小时重启上限，不再尝试。
+	ActionGaveUp = "gave_up"
+)
+
+// Incident 一次事故记录，附带当时的进程状态与日志末尾。
+type Incident struct {
+	ID          string         `json:"id"`
+	Time        time.Time      `json:"time"`
+	Kind        string         `json:"kind"`
+	Detail      string         `json:"detail"`
+	Status      procmgr.Status `json:"status"`
+	Action      string         `json:"action"`
+	ActionError string         `json:"action_error,omitempty"`
+	Log         []string       `json:"log,omitempty"`
+}
+
+type IncidentStore interface {
+	Add(inc Incident) error
+	// Recent 按时间倒序返回最近 limit 条；limit <= 0 返回全部。
+	Recent(limit int) ([]Incident, error)
+}
+
+// FileIncidentStore 每行一个 JSON 对象，只追加不改写。
+type FileIncidentStore struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+func (s *FileIncidentStore) Add(inc Incident) error {
+	line, err := json.Marshal(inc)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (s *FileIncidentStore) Recent(limit int) ([]Incident, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := os.Open(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Incident{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var all []Incident
+	sc := bufio.NewScanner(f)
+	// 每条记录带 200 行日志，超过默认的 64KB 行长。
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		var inc Incident
+		if err := json.Unmarshal(sc.Bytes(), &inc); err != nil {
+			continue
+		}
+		all = append(all, inc)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	out := make([]Incident, 0, len(all))
+	for i := len(all) - 1; i >= 0; i-- {
+		if limit > 0 && len(out) == limit {
+			break
+		}
+		out = append(out, all[i])
+	}
+	return out, nil
+}
@@ -0,0 +1,132 @@
+package watchdogapp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"pz-web-backend/internal/infra/fs"
+)
+
+// ErrInvalidPolicy 策略参数不合法。
+var ErrInvalidPolicy = errors.New("invalid watchdog policy")
+
+// Policy 看门狗的检测与自动重启策略，时长字段为 0 表示关闭对应检测。
+type Policy struct {
+	Enabled bool `json:"enabled"`
+	// RestartOnCrash 为 false 时只记录事故，不自动拉起。
+	RestartOnCrash bool `json:"restart_on_crash"`
+	// LogSilenceMinutes 日志连续无输出多久视为卡死。
+	LogSilenceMinutes int `json:"log_silence_minutes"`
+	// RCONFailMinutes RCON 连续探测失败多久视为卡死。
+	RCONFailMinutes int `json:"rcon_fail_minutes"`
+	// StartupGraceMinutes 启动后多久内不做卡死检测（加载地图、模组较慢）。
+	StartupGraceMinutes int `json:"startup_grace_minutes"`
+	// BackoffSeconds 第一次自动重启前的等待，之后每次翻倍，不超过 MaxBackoffSeconds。
+	BackoffSeconds    int `json:"backoff_seconds"`
+	MaxBackoffSeconds int `json:"max_backoff_seconds"`
+	// MaxRestartsPerHour 一小时内自动重启的上限，达到后放弃并记录 exit_loop。
+	MaxRestartsPerHour int `json:"max_restarts_per_hour"`
+}
+
+// DefaultPolicy 默认开启崩溃拉起与 RCON 探测；日志静默检测默认关闭，
+// 因为空服时 PZ 可能很长时间不输出日志。
+func DefaultPolicy() Policy {
+	return Policy{
+		Enabled:             true,
+		RestartOnCrash:      true,
+		RCONFailMinutes:     5,
+		StartupGraceMinutes: 10,
+		BackoffSeconds:      10,
+		MaxBackoffSeconds:   300,
+		MaxRestartsPerHour:  3,
+	}
+}
+
+func (p Policy) Validate() error {
+	fields := []struct {
+		name  string
+		value int
+	}{
+		{"log_silence_minutes", p.LogSilenceMinutes},
+		{"rcon_fail_minutes", p.RCONFailMinutes},
+		{"startup_grace_minutes", p.StartupGraceMinutes},
+		{"backoff_seconds", p.BackoffSeconds},
+		{"max_backoff_seconds", p.MaxBackoffSeconds},
+		{"max_restarts_per_hour", p.MaxRestartsPerHour},
+	}
+	for _, f := range fields {
+		if f.value < 0 {
+			return fmt.Errorf("%w: %s must not be negative", ErrInvalidPolicy, f.name)
+		}
+	}
+	if p.MaxBackoffSeconds > 0 && p.MaxBackoffSeconds < p.BackoffSeconds {
+		return fmt.Errorf("%w: max_backoff_seconds must be >= backoff_seconds", ErrInvalidPolicy)
+	}
+	return nil
+}
+
+// backoff 第 n 次（从 0 开始）连续重启前的等待时间。
+func (p Policy) backoff(n int) time.Duration {
+	d := time.Duration(p.BackoffSeconds) * time.Second
+	max := time.Duration(p.MaxBackoffSeconds) * time.Second
+	for i := 0; i < n && d > 0; i++ {
+		d *= 2
+		if max > 0 && d >= max {
+			return max
+		}
+	}
+	if max > 0 && d > max {
+		return max
+	}
+	return d
+}
+
+type PolicyStore interface {
+	Load() (Policy, error)
+	Save(p Policy) error
+}
+
+// FilePolicyStore 把策略保存为 JSON 文件；文件不存在时返回 DefaultPolicy。
+type FilePolicyStore struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+func (s *FilePolicyStore) Load() (Policy, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return DefaultPolicy(), nil
+	}
+	if err != nil {
+		return Policy{}, err
+	}
+	p := DefaultPolicy()
+	if err := json.Unmarshal(data, &p); err != nil {
+		return Policy{}, fmt.Errorf("parse %s: %w", s.Path, err)
+	}
+	return p, nil
+}
+
+func (s *FilePolicyStore) Save(p Policy) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o755); err != nil {
+		return err
+	}
+	return fs.WriteFileAtomic(s.Path, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,388 @@
+// Package watchdogapp 跟踪服务器进程状态，发现崩溃、卡死与反复退出时按策略自动重启并记录事故。
+package watchdogapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"pz-web-backend/internal/infra/logtail"
+	"pz-web-backend/internal/infra/procmgr"
+	"pz-web-backend/internal/infra/rcon"
+	"pz-web-backend/internal/jobs"
+)
+
+// RestartJobKind 看门狗自动重启在 jobs.Manager 中的类型。
+const RestartJobKind = "watchdog.restart"
+
+// IncidentLogLines 每条事故附带的日志行数。
+const IncidentLogLines = 200
+
+// Pinger 用一条轻量 RCON 命令探测服务器是否还在响应。
+type Pinger interface {
+	Players() ([]string, error)
+}
+
+// LogSource 服务器日志：事故附带的末尾几行与最后一次写入时间。
+type LogSource interface {
+	LastLines(n int) ([]string, error)
+	LastActivity() (time.Time, error)
+}
+
+// FileLog 以文件修改时间作为最后一次输出时间。
+type FileLog struct {
+	Path string
+}
+
+func (l FileLog) LastLines(n int) ([]string, error) {
+	return logtail.LastLines(l.Path, n)
+}
+
+func (l FileLog) LastActivity() (time.Time, error) {
+	info, err := os.Stat(l.Path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
+
+// State 看门狗的当前观察结果。
+type State struct {
+	LastCheck time.Time      `json:"last_check"`
+	Process   procmgr.Status `json:"process"`
+	// RCONFailingSince RCON 连续探测失败的开始时间。
+	RCONFailingSince *time.Time `json:"rcon_failing_since,omitempty"`
+	RestartsLastHour int        `json:"restarts_last_hour"`
+	// GaveUp 达到重启上限后停止自动重启，直到服务器重新运行或策略被修改。
+	GaveUp       bool      `json:"gave_up"`
+	LastIncident *Incident `json:"last_incident,omitempty"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// Watchdog 定期检查进程状态。
+// 人为的停服、重启、更新都以独占任务运行，期间及之后的第一次检查只刷新基线，不视为事故。
+type Watchdog struct {
+	Process   procmgr.ProcessManager
+	RCON      Pinger
+	Logs      LogSource
+	Jobs      *jobs.Manager
+	Incidents IncidentStore
+	Policies  PolicyStore
+	// Interval 检查间隔，默认 30s。
+	Interval time.Duration
+	Now      func() time.Time
+
+	mu    sync.Mutex
+	state State
+
+	// 以下字段只在检查循环中访问。
+	checked      bool
+	lastCheck    time.Time
+	prev         procmgr.Status
+	wantRunning  bool
+	runningSince time.Time
+	rconFailing  time.Time
+	restarts     []time.Time
+	consecutive  int
+}
+
+// Start 在后台运行检查循环，ctx 结束时退出。
+func (w *Watchdog) Start(ctx context.Context) {
+	go func() {
+		interval := w.Interval
+		if interval <= 0 {
+			interval = 30 * time.Second
+		}
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		for {
+			w.Check(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick.C:
+			}
+		}
+	}()
+}
+
+// State 返回状态快照。
+func (w *Watchdog) State() State {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	st := w.state
+	if st.LastIncident != nil {
+		inc := *st.LastIncident
+		inc.Log = nil
+		st.LastIncident = &inc
+	}
+	return st
+}
+
+// SetPolicy 保存策略并解除“已放弃”状态。
+func (w *Watchdog) SetPolicy(p Policy) error {
+	if err := w.Policies.Save(p); err != nil {
+		return err
+	}
+	w.mu.Lock()
+	w.state.GaveUp = false
+	w.mu.Unlock()
+	return nil
+}
+
+// Check 执行一次检查；由 Start 的循环调用，不能并发调用。
+func (w *Watchdog) Check(ctx context.Context) {
+	now := w.now()
+	policy, err := w.Policies.Load()
+	if err != nil {
+		w.setError(now, err)
+		return
+	}
+	status, err := w.Process.Status()
+	if err != nil {
+		w.setError(now, err)
+		return
+	}
+
+	since := w.lastCheck
+	w.lastCheck = now
+	maintenance := w.Jobs != nil && w.Jobs.ExclusiveSince(since, RestartJobKind)
+	if !policy.Enabled || !w.checked || maintenance {
+		w.resetBaseline(status, now)
+		w.update(now, status, nil)
+		return
+	}
+	prev := w.prev
+	w.prev = status
+
+	if status.Running() {
+		if !prev.Running() || w.runningSince.IsZero() {
+			w.runningSince = now
+		}
+		if w.gaveUp() {
+			w.setGaveUp(false)
+		}
+		w.wantRunning = true
+		// 稳定运行一小时后重新计算退避。
+		if len(w.restarts) > 0 && now.Sub(w.restarts[len(w.restarts)-1]) >= time.Hour {
+			w.consecutive = 0
+		}
+		if prev.Running() && prev.PID != 0 && status.PID != 0 && prev.PID != status.PID {
+			err = w.record(now, Incident{
+				Kind:   KindUnexpectedRestart,
+				Detail: fmt.Sprintf("pid changed from %d to %d", prev.PID, status.PID),
+				Status: status,
+				Action: ActionNone,
+			})
+			w.runningSince = now
+			w.rconFailing = time.Time{}
+		}
+		if detail := w.hang(policy, status, now); detail != "" {
+			err = errors.Join(err, w.recover(ctx, policy, now, KindHang, detail, status, w.Process.RestartPZServer))
+		}
+		w.update(now, status, err)
+		return
+	}
+	w.rconFailing = time.Time{}
+
+	// 失败状态只在刚进入时处理一次，避免每次检查都重复记录。
+	failed := status.State == procmgr.StateFailed
+	down := (w.wantRunning && (failed || status.State == procmgr.StateStopped)) || (failed && prev.State != procmgr.StateFailed)
+	if !down || w.gaveUp() {
+		w.update(now, status, nil)
+		return
+	}
+	kind := KindCrash
+	if status.State == procmgr.StateFailed && !prev.Running() {
+		kind = KindExitLoop
+	}
+	detail := fmt.Sprintf("process %s (exit code %d)", status.State, status.ExitCode)
+	if status.Detail != "" {
+		detail += ": " + status.Detail
+	}
+	w.update(now, status, w.recover(ctx, policy, now, kind, detail, status, w.Process.StartPZServer))
+}
+
+// hang 检查日志静默与 RCON 探测，返回卡死原因；启动宽限期内不检查。
+func (w *Watchdog) hang(p Policy, status procmgr.Status, now time.Time) string {
+	started := status.StartedAt
+	if started.IsZero() || started.Before(w.runningSince) {
+		started = w.runningSince
+	}
+	if now.Sub(started) < time.Duration(p.StartupGraceMinutes)*time.Minute {
+		w.rconFailing = time.Time{}
+		return ""
+	}
+
+	if p.LogSilenceMinutes > 0 && w.Logs != nil {
+		limit := time.Duration(p.LogSilenceMinutes) * time.Minute
+		if last, err := w.Logs.LastActivity(); err == nil && now.Sub(last) >= limit && now.Sub(started) >= limit {
+			return fmt.Sprintf("no log output since %s", last.UTC().Format(time.RFC3339))
+		}
+	}
+
+	if p.RCONFailMinutes > 0 && w.RCON != nil {
+		_, err := w.RCON.Players()
+		// 未开启 RCON 或密码错误不代表服务器卡死。
+		if err == nil || errors.Is(err, rcon.ErrDisabled) || errors.Is(err, rcon.ErrAuthFailed) {
+			w.rconFailing = time.Time{}
+			return ""
+		}
+		if w.rconFailing.IsZero() {
+			w.rconFailing = now
+		}
+		if now.Sub(w.rconFailing) >= time.Duration(p.RCONFailMinutes)*time.Minute {
+			return fmt.Sprintf("rcon not responding since %s: %v", w.rconFailing.UTC().Format(time.RFC3339), err)
+		}
+	}
+	return ""
+}
+
+// recover 按策略处理一次崩溃或卡死：退避后通过独占任务拉起，超过每小时上限则放弃。
+func (w *Watchdog) recover(ctx context.Context, p Policy, now time.Time, kind, detail string, status procmgr.Status, action func() error) error {
+	inc := Incident{Kind: kind, Detail: detail, Status: status}
+	w.pruneRestarts(now)
+
+	switch {
+	case !p.RestartOnCrash:
+		inc.Action = ActionSkipped
+		w.wantRunning = false
+		w.runningSince = now
+		w.rconFailing = time.Time{}
+	case p.MaxRestartsPerHour > 0 && len(w.restarts) >= p.MaxRestartsPerHour:
+		inc.Kind = KindExitLoop
+		inc.Detail = fmt.Sprintf("%s; %d automatic restarts in the last hour, giving up", detail, len(w.restarts))
+		inc.Action = ActionGaveUp
+		w.wantRunning = false
+		w.setGaveUp(true)
+	default:
+		err := wait(ctx, p.backoff(w.consecutive))
+		if err == nil {
+			err = w.runRestart(action)
+		}
+		w.restarts = append(w.restarts, now)
+		w.consecutive++
+		inc.Action = ActionRestarted
+		if err != nil {
+			inc.Action = ActionFailed
+			inc.ActionError = err.Error()
+		}
+		// 重启后的状态由下一次检查重新观察。
+		w.runningSince = time.Time{}
+		w.rconFailing = time.Time{}
+		w.prev = procmgr.Status{}
+	}
+	return w.record(now, inc)
+}
+
+func (w *Watchdog) runRestart(action func() error) error {
+	if w.Jobs == nil {
+		return action()
+	}
+	return w.Jobs.Run(jobs.Spec{Kind: RestartJobKind, Exclusive: true}, func(ctx context.Context, r *jobs.Reporter) error {
+		return action()
+	})
+}
+
+// record 附上日志末尾并保存事故；日志读取失败不影响记录。
+func (w *Watchdog) record(now time.Time, inc Incident) error {
+	inc.ID = now.UTC().Format("20060102T150405.000000000Z")
+	inc.Time = now
+	if w.Logs != nil {
+		if lines, err := w.Logs.LastLines(IncidentLogLines); err == nil {
+			inc.Log = lines
+		}
+	}
+	w.mu.Lock()
+	w.state.LastIncident = &inc
+	w.mu.Unlock()
+	if w.Incidents == nil {
+		return nil
+	}
+	if err := w.Incidents.Add(inc); err != nil {
+		return fmt.Errorf("save incident: %w", err)
+	}
+	return nil
+}
+
+func (w *Watchdog) resetBaseline(status procmgr.Status, now time.Time) {
+	w.checked = true
+	w.prev = status
+	w.wantRunning = status.Running()
+	w.runningSince = time.Time{}
+	if status.Running() {
+		w.runningSince = now
+	}
+	w.rconFailing = time.Time{}
+}
+
+func (w *Watchdog) pruneRestarts(now time.Time) {
+	kept := w.restarts[:0]
+	for _, t := range w.restarts {
+		if now.Sub(t) < time.Hour {
+			kept = append(kept, t)
+		}
+	}
+	w.restarts = kept
+}
+
+func (w *Watchdog) update(now time.Time, status procmgr.Status, err error) {
+	w.pruneRestarts(now)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.state.LastCheck = now
+	w.state.Process = status
+	w.state.RestartsLastHour = len(w.restarts)
+	w.state.RCONFailingSince = nil
+	if !w.rconFailing.IsZero() {
+		since := w.rconFailing
+		w.state.RCONFailingSince = &since
+	}
+	w.state.Error = ""
+	if err != nil {
+		w.state.Error = err.Error()
+	}
+}
+
+func (w *Watchdog) setError(now time.Time, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.state.LastCheck = now
+	w.state.Error = err.Error()
+}
+
+func (w *Watchdog) gaveUp() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.state.GaveUp
+}
+
+func (w *Watchdog) setGaveUp(v bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.state.GaveUp = v
+}
+
+func (w *Watchdog) now() time.Time {
+	if w.Now != nil {
+		return w.Now()
+	}
+	return time.Now()
+}
+
+func wait(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
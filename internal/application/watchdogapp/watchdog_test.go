@@ -0,0 +1,272 @@
+package watchdogapp
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"pz-web-backend/internal/infra/procmgr"
+	"pz-web-backend/internal/infra/rcon"
+	"pz-web-backend/internal/jobs"
+)
+
+type fakeProcess struct {
+	status procmgr.Status
+	calls  []string
+	// onStart 为 StartPZServer / RestartPZServer 之后的状态。
+	onStart procmgr.Status
+}
+
+func (f *fakeProcess) StartPZServer() error {
+	f.calls = append(f.calls, "start")
+	f.status = f.onStart
+	return nil
+}
+
+func (f *fakeProcess) RestartPZServer() error {
+	f.calls = append(f.calls, "restart")
+	f.status = f.onStart
+	return nil
+}
+
+func (f *fakeProcess) StopPZServer() error {
+	f.calls = append(f.calls, "stop")
+	f.status = procmgr.Status{State: procmgr.StateStopped}
+	return nil
+}
+
+func (f *fakeProcess) Status() (procmgr.Status, error) { return f.status, nil }
+
+type fakePinger struct{ err error }
+
+func (p fakePinger) Players() ([]string, error) { return nil, p.err }
+
+type fakeLogs struct {
+	lines []string
+	last  time.Time
+}
+
+func (l fakeLogs) LastLines(n int) ([]string, error) { return l.lines, nil }
+func (l fakeLogs) LastActivity() (time.Time, error)  { return l.last, nil }
+
+type memoryPolicies struct{ p Policy }
+
+func (m *memoryPolicies) Load() (Policy, error) { return m.p, nil }
+func (m *memoryPolicies) Save(p Policy) error   { m.p = p; return nil }
+
+type memoryIncidents struct{ items []Incident }
+
+func (m *memoryIncidents) Add(inc Incident) error { m.items = append(m.items, inc); return nil }
+func (m *memoryIncidents) Recent(limit int) ([]Incident, error) {
+	return m.items, nil
+}
+
+func running(pid int) procmgr.Status {
+	return procmgr.Status{State: procmgr.StateRunning, PID: pid}
+}
+
+type fixture struct {
+	w         *Watchdog
+	proc      *fakeProcess
+	incidents *memoryIncidents
+	now       time.Time
+}
+
+func newFixture(p Policy) *fixture {
+	p.BackoffSeconds = 0
+	f := &fixture{
+		proc:      &fakeProcess{status: running(100), onStart: running(200)},
+		incidents: &memoryIncidents{},
+		now:       time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC),
+	}
+	now := func() time.Time { return f.now }
+	f.w = &Watchdog{
+		Process:   f.proc,
+		Logs:      fakeLogs{lines: []string{"line 1", "line 2"}, last: f.now},
+		Jobs:      &jobs.Manager{Now: now},
+		Incidents: f.incidents,
+		Policies:  &memoryPolicies{p: p},
+		Now:       now,
+	}
+	return f
+}
+
+func (f *fixture) tick(d time.Duration) {
+	f.now = f.now.Add(d)
+	f.w.Check(context.Background())
+}
+
+func TestWatchdog_CrashIsRestartedWithLogAttached(t *testing.T) {
+	f := newFixture(DefaultPolicy())
+	f.tick(0)
+	f.proc.status = procmgr.Status{State: procmgr.StateStopped, ExitCode: 139}
+	f.tick(30 * time.Second)
+
+	if !reflect.DeepEqual(f.proc.calls, []string{"start"}) {
+		t.Fatalf("calls=%v", f.proc.calls)
+	}
+	if len(f.incidents.items) != 1 {
+		t.Fatalf("incidents=%+v", f.incidents.items)
+	}
+	inc := f.incidents.items[0]
+	if inc.Kind != KindCrash || inc.Action != ActionRestarted || !strings.Contains(inc.Detail, "139") || len(inc.Log) != 2 {
+		t.Fatalf("incident=%+v", inc)
+	}
+	if st := f.w.State(); st.RestartsLastHour != 1 || st.LastIncident == nil || st.LastIncident.Log != nil {
+		t.Fatalf("state=%+v", st)
+	}
+
+	// 重启后以新 PID 运行，不应再记录事故。
+	f.tick(30 * time.Second)
+	f.tick(30 * time.Second)
+	if len(f.incidents.items) != 1 {
+		t.Fatalf("incidents=%+v", f.incidents.items)
+	}
+}
+
+func TestWatchdog_ManualStopIsNotACrash(t *testing.T) {
+	f := newFixture(DefaultPolicy())
+	f.tick(0)
+	err := f.w.Jobs.Run(jobs.Spec{Kind: "server.stop", Exclusive: true}, func(ctx context.Context, r *jobs.Reporter) error {
+		return f.proc.StopPZServer()
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	f.tick(30 * time.Second)
+	f.tick(30 * time.Second)
+
+	if len(f.incidents.items) != 0 || !reflect.DeepEqual(f.proc.calls, []string{"stop"}) {
+		t.Fatalf("incidents=%+v calls=%v", f.incidents.items, f.proc.calls)
+	}
+}
+
+func TestWatchdog_GivesUpAfterMaxRestartsPerHour(t *testing.T) {
+	p := DefaultPolicy()
+	p.MaxRestartsPerHour = 2
+	f := newFixture(p)
+	// 每次拉起后立刻又退出。
+	f.proc.onStart = procmgr.Status{State: procmgr.StateStopped, ExitCode: 1}
+	f.tick(0)
+	f.proc.status = procmgr.Status{State: procmgr.StateStopped, ExitCode: 1}
+	for i := 0; i < 5; i++ {
+		f.tick(time.Minute)
+	}
+
+	var actions []string
+	for _, inc := range f.incidents.items {
+		actions = append(actions, inc.Kind+"/"+inc.Action)
+	}
+	want := []string{"crash/restarted", "crash/restarted", "exit_loop/gave_up"}
+	if !reflect.DeepEqual(actions, want) {
+		t.Fatalf("actions=%v", actions)
+	}
+	if len(f.proc.calls) != 2 || !f.w.State().GaveUp {
+		t.Fatalf("calls=%v state=%+v", f.proc.calls, f.w.State())
+	}
+
+	if err := f.w.SetPolicy(p); err != nil || f.w.State().GaveUp {
+		t.Fatalf("SetPolicy err=%v state=%+v", err, f.w.State())
+	}
+}
+
+func TestWatchdog_RCONHangRestartsAfterGrace(t *testing.T) {
+	p := DefaultPolicy()
+	p.StartupGraceMinutes = 10
+	p.RCONFailMinutes = 5
+	f := newFixture(p)
+	pinger := &fakePinger{err: errors.New("connection refused")}
+	f.w.RCON = pinger
+	f.tick(0)
+
+	// 宽限期内失败不计时。
+	f.tick(5 * time.Minute)
+	if f.w.State().RCONFailingSince != nil {
+		t.Fatalf("state=%+v", f.w.State())
+	}
+	f.tick(6 * time.Minute)
+	f.tick(4 * time.Minute)
+	if len(f.proc.calls) != 0 {
+		t.Fatalf("restarted too early: %v", f.proc.calls)
+	}
+	f.tick(time.Minute)
+	if !reflect.DeepEqual(f.proc.calls, []string{"restart"}) || f.incidents.items[0].Kind != KindHang {
+		t.Fatalf("calls=%v incidents=%+v", f.proc.calls, f.incidents.items)
+	}
+
+	// 未开启 RCON 不算卡死。
+	pinger.err = rcon.ErrDisabled
+	f.tick(30 * time.Minute)
+	f.tick(30 * time.Minute)
+	if len(f.proc.calls) != 1 {
+		t.Fatalf("calls=%v", f.proc.calls)
+	}
+}
+
+func TestWatchdog_UnexpectedRestartAndDisabledPolicy(t *testing.T) {
+	p := DefaultPolicy()
+	f := newFixture(p)
+	f.tick(0)
+	f.proc.status = running(101)
+	f.tick(30 * time.Second)
+	if len(f.incidents.items) != 1 || f.incidents.items[0].Kind != KindUnexpectedRestart || len(f.proc.calls) != 0 {
+		t.Fatalf("incidents=%+v calls=%v", f.incidents.items, f.proc.calls)
+	}
+
+	p.Enabled = false
+	_ = f.w.SetPolicy(p)
+	f.proc.status = procmgr.Status{State: procmgr.StateFailed}
+	f.tick(30 * time.Second)
+	if len(f.incidents.items) != 1 || len(f.proc.calls) != 0 {
+		t.Fatalf("incidents=%+v calls=%v", f.incidents.items, f.proc.calls)
+	}
+}
+
+func TestPolicy_BackoffAndValidate(t *testing.T) {
+	p := Policy{BackoffSeconds: 10, MaxBackoffSeconds: 60}
+	var got []time.Duration
+	for i := 0; i < 5; i++ {
+		got = append(got, p.backoff(i))
+	}
+	want := []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second, time.Minute, time.Minute}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("backoff=%v", got)
+	}
+	if err := (Policy{BackoffSeconds: 30, MaxBackoffSeconds: 10}).Validate(); !errors.Is(err, ErrInvalidPolicy) {
+		t.Fatalf("err=%v", err)
+	}
+	if err := (Policy{RCONFailMinutes: -1}).Validate(); !errors.Is(err, ErrInvalidPolicy) {
+		t.Fatalf("err=%v", err)
+	}
+}
+
+func TestFileStores_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	policies := &FilePolicyStore{Path: filepath.Join(dir, "pz-web", "watchdog.json")}
+	p, err := policies.Load()
+	if err != nil || p != DefaultPolicy() {
+		t.Fatalf("p=%+v err=%v", p, err)
+	}
+	p.LogSilenceMinutes = 15
+	if err := policies.Save(p); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if got, _ := policies.Load(); got != p {
+		t.Fatalf("got=%+v", got)
+	}
+
+	incidents := &FileIncidentStore{Path: filepath.Join(dir, "pz-web", "incidents.log")}
+	for _, id := range []string{"a", "b", "c"} {
+		if err := incidents.Add(Incident{ID: id, Log: []string{strings.Repeat("x", 100*1024)}}); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+	got, err := incidents.Recent(2)
+	if err != nil || len(got) != 2 || got[0].ID != "c" || got[1].ID != "b" {
+		t.Fatalf("got=%+v err=%v", got, err)
+	}
+}
@@ -0,0 +1,106 @@
+// Package audit 记录敏感操作（查看、轮换密钥等），以 JSON Lines 追加写入文件。
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Entry 一条审计记录。
+type Entry struct {
+	Time   time.Time `json:"time"`
+	Actor  string    `json:"actor"`
+	IP     string    `json:"ip,omitempty"`
+	Action string    `json:"action"`
+	Target string    `json:"target"`
+}
+
+type Logger interface {
+	// Record 追加一条记录；Time 为空时由 Logger 填充。
+	Record(e Entry) error
+	// Recent 按时间倒序返回最近 limit 条记录；limit <= 0 返回全部。
+	Recent(limit int) ([]Entry, error)
+}
+
+// FileLogger 每行一个 JSON 对象，只追加不改写。
+type FileLogger struct {
+	Path string
+	// Now 允许在测试中固定时间。
+	Now func() time.Time
+
+	mu sync.Mutex
+}
+
+func (l *FileLogger) Record(e Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if e.Time.IsZero() {
+		e.Time = l.now().UTC()
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.Path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(l.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (l *FileLogger) Recent(limit int) ([]Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.Open(l.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var all []Entry
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var e Entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			continue
+		}
+		all = append(all, e)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	out := make([]Entry, 0, len(all))
+	for i := len(all) - 1; i >= 0; i-- {
+		if limit > 0 && len(out) == limit {
+			break
+		}
+		out = append(out, all[i])
+	}
+	return out, nil
+}
+
+func (l *FileLogger) now() time.Time {
+	if l.Now != nil {
+		return l.Now()
+	}
+	return time.Now()
+}
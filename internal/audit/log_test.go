@@ -0,0 +1,26 @@
+package audit
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileLogger_RecordAndRecent(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	l := &FileLogger{Path: filepath.Join(t.TempDir(), "sub", "audit.log"), Now: func() time.Time { return now }}
+
+	for _, action := range []string{"reveal", "rotate", "reveal"} {
+		if err := l.Record(Entry{Actor: "alice", Action: action, Target: "RCONPassword"}); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+
+	got, err := l.Recent(2)
+	if err != nil {
+		t.Fatalf("Recent: %v", err)
+	}
+	if len(got) != 2 || got[0].Action != "reveal" || got[1].Action != "rotate" || !got[0].Time.Equal(now) {
+		t.Fatalf("got=%+v", got)
+	}
+}
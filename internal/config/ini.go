@@ -0,0 +1,149 @@
+package config
+
+import "strings"
+
+// INIEntry 文档中的一个 key=value 项。
+type INIEntry struct {
+	Key   string
+	Value string
+}
+
+// INIDocument 保留注释、空行、未知行与键顺序的 INI 文档模型。
+//
+// 修改值时只重写对应行的值部分，其余内容按原样输出，
+// 保证未改动的文件可以逐字节还原。
+type INIDocument struct {
+	lines []iniLine
+	eol   string
+	// trailingEOL 原文件是否以换行结尾。
+	trailingEOL bool
+}
+
+type iniLine struct {
+	raw string
+
+	isEntry bool
+	key     string
+	value   string
+	// prefix 为值之前的原始文本（含 key、"=" 与空白），suffix 为值之后的空白。
+	prefix string
+	suffix string
+}
+
+// ParseINIDocument 解析 INI 文本。无法识别的行会原样保留。
+func ParseINIDocument(data []byte) *INIDocument {
+	text := string(data)
+	doc := &INIDocument{eol: "\n"}
+	if strings.Contains(text, "\r\n") {
+		doc.eol = "\r\n"
+	}
+	if text == "" {
+		doc.trailingEOL = true
+		return doc
+	}
+
+	doc.trailingEOL = strings.HasSuffix(text, "\n")
+	text = strings.TrimSuffix(text, "\n")
+	text = strings.TrimSuffix(text, "\r")
+
+	for _, raw := range strings.Split(text, "\n") {
+		raw = strings.TrimSuffix(raw, "\r")
+		doc.lines = append(doc.lines, parseINILine(raw))
+	}
+	return doc
+}
+
+func parseINILine(raw string) iniLine {
+	line := iniLine{raw: raw}
+
+	trimmed := strings.TrimSpace(strings.TrimPrefix(raw, "\uFEFF"))
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";") {
+		return line
+	}
+
+	eq := strings.Index(raw, "=")
+	if eq < 0 {
+		return line
+	}
+
+	key := strings.TrimSpace(strings.TrimPrefix(raw[:eq], "\uFEFF"))
+	if key == "" {
+		return line
+	}
+
+	rest := raw[eq+1:]
+	value := strings.TrimSpace(rest)
+	lead := len(rest) - len(strings.TrimLeft(rest, " \t"))
+	trail := len(strings.TrimRight(rest, " \t"))
+
+	line.isEntry = true
+	line.key = key
+	line.value = value
+	line.prefix = raw[:eq+1+lead]
+	if trail >= lead {
+		line.suffix = rest[trail:]
+	}
+	return line
+}
+
+// Entries 按文件顺序返回所有 key=value 项。
+func (d *INIDocument) Entries() []INIEntry {
+	var entries []INIEntry
+	for _, l := range d.lines {
+		if l.isEntry {
+			entries = append(entries, INIEntry{Key: l.key, Value: l.value})
+		}
+	}
+	return entries
+}
+
+// Get 返回 key 首次出现时的值。
+func (d *INIDocument) Get(key string) (string, bool) {
+	if i := d.indexOf(key); i >= 0 {
+		return d.lines[i].value, true
+	}
+	return "", false
+}
+
+// Set 修改 key 的值；值未变化时不改动原始行。key 不存在时追加到文件末尾。
+func (d *INIDocument) Set(key string, value string) {
+	i := d.indexOf(key)
+	if i < 0 {
+		d.lines = append(d.lines, iniLine{
+			raw:     key + "=" + value,
+			isEntry: true,
+			key:     key,
+			value:   value,
+			prefix:  key + "=",
+		})
+		return
+	}
+
+	l := &d.lines[i]
+	if l.value == value {
+		return
+	}
+	l.value = value
+	l.raw = l.prefix + value + l.suffix
+}
+
+// String 输出文档文本，保留原有换行风格。
+func (d *INIDocument) String() string {
+	var sb strings.Builder
+	for i, l := range d.lines {
+		sb.WriteString(l.raw)
+		if i < len(d.lines)-1 || d.trailingEOL {
+			sb.WriteString(d.eol)
+		}
+	}
+	return sb.String()
+}
+
+func (d *INIDocument) indexOf(key string) int {
+	for i, l := range d.lines {
+		if l.isEntry && l.key == key {
+			return i
+		}
+	}
+	return -1
+}
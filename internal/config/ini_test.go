@@ -0,0 +1,53 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseINIDocument_RoundTripUnchanged(t *testing.T) {
+	src := "# Players can hurt each other\nPVP=false\n\n; legacy note\nMap = Muldraugh, KY  \nnot a key line\nPassword=\n"
+	doc := ParseINIDocument([]byte(src))
+	if got := doc.String(); got != src {
+		t.Fatalf("round trip mismatch:\n%q\n%q", got, src)
+	}
+
+	entries := doc.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("entries=%+v", entries)
+	}
+	if entries[1].Key != "Map" || entries[1].Value != "Muldraugh, KY" {
+		t.Fatalf("entries[1]=%+v", entries[1])
+	}
+}
+
+func TestINIDocument_SetRewritesOnlyChangedValue(t *testing.T) {
+	src := "# comment\r\nPVP=false\r\nMap = Muldraugh, KY  \r\n"
+	doc := ParseINIDocument([]byte(src))
+	doc.Set("PVP", "false")
+	doc.Set("Map", "Riverside, KY")
+	doc.Set("Public", "true")
+
+	want := "# comment\r\nPVP=false\r\nMap = Riverside, KY  \r\nPublic=true\r\n"
+	if got := doc.String(); got != want {
+		t.Fatalf("got=%q want %q", got, want)
+	}
+	if v, ok := doc.Get("Public"); !ok || v != "true" {
+		t.Fatalf("Get(Public)=%q,%v", v, ok)
+	}
+}
+
+func TestService_UpdateServerINI_KeepsComments(t *testing.T) {
+	src := "# Server welcome message\nServerWelcomeMessage=Hi\n\n# Max players\nMaxPlayers=32\n"
+	out := Service{}.UpdateServerINI([]byte(src), []Item{
+		{Key: "ServerWelcomeMessage", Value: "Hi"},
+		{Key: "MaxPlayers", Value: "16"},
+	})
+
+	if !strings.Contains(out, "# Server welcome message\n") || !strings.Contains(out, "# Max players\n") {
+		t.Fatalf("comments lost:\n%s", out)
+	}
+	if want := "MaxPlayers=16\n"; !strings.Contains(out, want) {
+		t.Fatalf("expected %q in:\n%s", want, out)
+	}
+}
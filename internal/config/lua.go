@@ -25,3 +25,21 @@ func formatLuaValue(val string) string {
 	}
 	return val
 }
+
+// formatLuaValueAs 按原字面量类型格式化新值：原值为字符串时始终加引号，
+// 避免 "10" 这类字符串在保存后变成数字。
+func formatLuaValueAs(kind LuaKind, val string) string {
+	if kind == LuaString {
+		return quoteLuaString(val)
+	}
+	val = strings.TrimSpace(val)
+	if isBool(val) || isNumber(val) || val == "nil" {
+		return val
+	}
+	return quoteLuaString(val)
+}
+
+func quoteLuaString(val string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`)
+	return `"` + r.Replace(val) + `"`
+}
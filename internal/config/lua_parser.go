@@ -0,0 +1,706 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 本文件实现一个只覆盖 Lua 字面量子集的分词器与解析器，
+// 足以处理 SandboxVars.lua / spawnregions.lua 这类“配置型”Lua 文件：
+//   - Name = <value>
+//   - local Name = <value>
+//   - function Name() return <value> end
+//   - return <value>
+// 值支持 table / string / number / true / false / nil / 标识符。
+// 解析结果记录每个节点在源文本中的字节区间，便于只替换改动过的值。
+
+type luaTokenKind int
+
+const (
+	luaTokEOF luaTokenKind = iota
+	luaTokName
+	luaTokString
+	luaTokNumber
+	luaTokSymbol
+)
+
+type luaToken struct {
+	kind  luaTokenKind
+	text  string
+	start int
+	end   int
+}
+
+// LuaComment 源文件中的一段注释（含 "--" 前缀）。
+type LuaComment struct {
+	Text  string
+	Start int
+	End   int
+}
+
+type luaLexer struct {
+	src      string
+	pos      int
+	comments []LuaComment
+}
+
+func (lx *luaLexer) errorf(pos int, format string, args ...any) error {
+	line := 1 + strings.Count(lx.src[:pos], "\n")
+	return fmt.Errorf("lua: line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+func (lx *luaLexer) next() (luaToken, error) {
+	for {
+		lx.skipSpace()
+		if !strings.HasPrefix(lx.src[lx.pos:], "--") {
+			break
+		}
+		if err := lx.skipComment(); err != nil {
+			return luaToken{}, err
+		}
+	}
+
+	start := lx.pos
+	if start >= len(lx.src) {
+		return luaToken{kind: luaTokEOF, start: start, end: start}, nil
+	}
+
+	c := lx.src[start]
+	switch {
+	case isLuaNameStart(c):
+		for lx.pos < len(lx.src) && isLuaNameChar(lx.src[lx.pos]) {
+			lx.pos++
+		}
+		return lx.token(luaTokName, start), nil
+	case isDigit(c) || (c == '.' && start+1 < len(lx.src) && isDigit(lx.src[start+1])):
+		lx.scanNumber()
+		return lx.token(luaTokNumber, start), nil
+	case c == '"' || c == '\'':
+		if err := lx.scanQuoted(c); err != nil {
+			return luaToken{}, err
+		}
+		return lx.token(luaTokString, start), nil
+	case c == '[' && longBracketLevel(lx.src[start:]) >= 0:
+		if err := lx.scanLongBracket(); err != nil {
+			return luaToken{}, err
+		}
+		return lx.token(luaTokString, start), nil
+	case strings.ContainsRune("{}[]()=,;.:-+", rune(c)):
+		lx.pos++
+		return lx.token(luaTokSymbol, start), nil
+	}
+	return luaToken{}, lx.errorf(start, "unexpected character %q", c)
+}
+
+func (lx *luaLexer) token(kind luaTokenKind, start int) luaToken {
+	return luaToken{kind: kind, text: lx.src[start:lx.pos], start: start, end: lx.pos}
+}
+
+func (lx *luaLexer) skipSpace() {
+	for lx.pos < len(lx.src) {
+		switch lx.src[lx.pos] {
+		case ' ', '\t', '\r', '\n', '\f', '\v':
+			lx.pos++
+		default:
+			if strings.HasPrefix(lx.src[lx.pos:], "\uFEFF") {
+				lx.pos += len("\uFEFF")
+				continue
+			}
+			return
+		}
+	}
+}
+
+func (lx *luaLexer) skipComment() error {
+	start := lx.pos
+	lx.pos += 2
+	if longBracketLevel(lx.src[lx.pos:]) >= 0 {
+		if err := lx.scanLongBracket(); err != nil {
+			return err
+		}
+	} else {
+		for lx.pos < len(lx.src) && lx.src[lx.pos] != '\n' {
+			lx.pos++
+		}
+	}
+	end := lx.pos
+	text := strings.TrimRight(lx.src[start:end], "\r")
+	lx.comments = append(lx.comments, LuaComment{Text: text, Start: start, End: start + len(text)})
+	return nil
+}
+
+func (lx *luaLexer) scanNumber() {
+	s := lx.src
+	if strings.HasPrefix(s[lx.pos:], "0x") || strings.HasPrefix(s[lx.pos:], "0X") {
+		lx.pos += 2
+		for lx.pos < len(s) && isHexDigit(s[lx.pos]) {
+			lx.pos++
+		}
+		return
+	}
+	for lx.pos < len(s) {
+		c := s[lx.pos]
+		switch {
+		case isDigit(c) || c == '.':
+			lx.pos++
+		case c == 'e' || c == 'E':
+			lx.pos++
+			if lx.pos < len(s) && (s[lx.pos] == '+' || s[lx.pos] == '-') {
+				lx.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (lx *luaLexer) scanQuoted(quote byte) error {
+	start := lx.pos
+	lx.pos++
+	for lx.pos < len(lx.src) {
+		switch lx.src[lx.pos] {
+		case '\\':
+			lx.pos += 2
+		case quote:
+			lx.pos++
+			return nil
+		case '\n':
+			return lx.errorf(start, "unfinished string")
+		default:
+			lx.pos++
+		}
+	}
+	return lx.errorf(start, "unfinished string")
+}
+
+func (lx *luaLexer) scanLongBracket() error {
+	start := lx.pos
+	level := longBracketLevel(lx.src[lx.pos:])
+	closing := "]" + strings.Repeat("=", level) + "]"
+	lx.pos += level + 2
+	idx := strings.Index(lx.src[lx.pos:], closing)
+	if idx < 0 {
+		return lx.errorf(start, "unfinished long bracket")
+	}
+	lx.pos += idx + len(closing)
+	return nil
+}
+
+// longBracketLevel 返回 "[[" / "[==[" 的等号数量，不是长括号时返回 -1。
+func longBracketLevel(s string) int {
+	if !strings.HasPrefix(s, "[") {
+		return -1
+	}
+	i := 1
+	for i < len(s) && s[i] == '=' {
+		i++
+	}
+	if i < len(s) && s[i] == '[' {
+		return i - 1
+	}
+	return -1
+}
+
+func isLuaNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isLuaNameChar(c byte) bool {
+	return isLuaNameStart(c) || isDigit(c)
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func isHexDigit(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+// ---- AST ----
+
+// LuaKind 标量值的字面量类型。
+type LuaKind string
+
+const (
+	LuaString LuaKind = "string"
+	LuaNumber LuaKind = "number"
+	LuaBool   LuaKind = "bool"
+	LuaNil    LuaKind = "nil"
+	// LuaIdent 对其他变量的引用，按原文保留。
+	LuaIdent LuaKind = "ident"
+)
+
+// LuaValue 为 *LuaTable 或 *LuaScalar。
+type LuaValue interface {
+	Span() (start, end int)
+}
+
+// LuaScalar 标量字面量。Raw 为源文本，Value 为解码后的值（字符串去掉引号与转义）。
+type LuaScalar struct {
+	Kind  LuaKind
+	Raw   string
+	Value string
+	Start int
+	End   int
+}
+
+func (v *LuaScalar) Span() (int, int) { return v.Start, v.End }
+
+// LuaField table 中的一个字段。Key 为空表示数组式（位置）字段。
+type LuaField struct {
+	Key   string
+	Value LuaValue
+	Start int
+	End   int
+	// HasSep 字段后是否跟随 "," 或 ";"。
+	HasSep bool
+}
+
+// LuaTable table 构造式，Start 指向 "{"，End 指向 "}" 之后。
+type LuaTable struct {
+	Fields []*LuaField
+	Start  int
+	End    int
+}
+
+func (t *LuaTable) Span() (int, int) { return t.Start, t.End }
+
+// Field 按 key 查找字段。
+func (t *LuaTable) Field(key string) *LuaField {
+	for _, f := range t.Fields {
+		if f.Key == key {
+			return f
+		}
+	}
+	return nil
+}
+
+// LuaStatement 顶层语句：赋值、函数返回值或 chunk 返回值。
+type LuaStatement struct {
+	// Name 为赋值目标或函数名；顶层 return 时为空。
+	Name  string
+	Func  bool
+	Value LuaValue
+	Start int
+	End   int
+}
+
+// LuaChunk 解析后的 Lua 文件。
+type LuaChunk struct {
+	Src        string
+	Statements []*LuaStatement
+	Comments   []LuaComment
+}
+
+// Global 返回顶层赋值 `name = {...}` 的 table。
+func (c *LuaChunk) Global(name string) *LuaTable {
+	for _, st := range c.Statements {
+		if !st.Func && st.Name == name {
+			if t, ok := st.Value.(*LuaTable); ok {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+// FuncReturn 返回 `function name() return {...} end` 中返回的 table。
+func (c *LuaChunk) FuncReturn(name string) *LuaTable {
+	for _, st := range c.Statements {
+		if st.Func && st.Name == name {
+			if t, ok := st.Value.(*LuaTable); ok {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+// CommentsIn 返回位于 [start,end) 区间内的注释。
+func (c *LuaChunk) CommentsIn(start, end int) []LuaComment {
+	var out []LuaComment
+	for _, cm := range c.Comments {
+		if cm.Start >= start && cm.End <= end {
+			out = append(out, cm)
+		}
+	}
+	return out
+}
+
+// ---- parser ----
+
+type luaParser struct {
+	lx  *luaLexer
+	tok luaToken
+	// prevEnd 上一个已消费 token 的结束位置。
+	prevEnd int
+}
+
+// ParseLua 解析 Lua 配置文件。
+func ParseLua(src []byte) (*LuaChunk, error) {
+	p := &luaParser{lx: &luaLexer{src: string(src)}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	chunk := &LuaChunk{Src: string(src)}
+	for p.tok.kind != luaTokEOF {
+		st, err := p.parseStatement()
+		if err != nil {
+			return nil, err
+		}
+		chunk.Statements = append(chunk.Statements, st)
+	}
+	chunk.Comments = p.lx.comments
+	return chunk, nil
+}
+
+func (p *luaParser) advance() error {
+	p.prevEnd = p.tok.end
+	tok, err := p.lx.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *luaParser) is(kind luaTokenKind, text string) bool {
+	return p.tok.kind == kind && p.tok.text == text
+}
+
+func (p *luaParser) expect(kind luaTokenKind, text string) error {
+	if !p.is(kind, text) {
+		return p.lx.errorf(p.tok.start, "expected %q, got %q", text, p.tok.text)
+	}
+	return p.advance()
+}
+
+func (p *luaParser) expectName() (string, error) {
+	if p.tok.kind != luaTokName {
+		return "", p.lx.errorf(p.tok.start, "expected name, got %q", p.tok.text)
+	}
+	name := p.tok.text
+	return name, p.advance()
+}
+
+func (p *luaParser) parseStatement() (*LuaStatement, error) {
+	st := &LuaStatement{Start: p.tok.start}
+
+	switch {
+	case p.is(luaTokName, "function"):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.parseDottedName()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(luaTokSymbol, "("); err != nil {
+			return nil, err
+		}
+		if err := p.expect(luaTokSymbol, ")"); err != nil {
+			return nil, err
+		}
+		if err := p.expect(luaTokName, "return"); err != nil {
+			return nil, err
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		if p.is(luaTokSymbol, ";") {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+		if err := p.expect(luaTokName, "end"); err != nil {
+			return nil, err
+		}
+		st.Name, st.Func, st.Value = name, true, value
+	case p.is(luaTokName, "return"):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		st.Value = value
+	default:
+		if p.is(luaTokName, "local") {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+		name, err := p.parseDottedName()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(luaTokSymbol, "="); err != nil {
+			return nil, err
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		st.Name, st.Value = name, value
+	}
+
+	if p.is(luaTokSymbol, ";") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	st.End = p.prevEnd
+	return st, nil
+}
+
+func (p *luaParser) parseDottedName() (string, error) {
+	name, err := p.expectName()
+	if err != nil {
+		return "", err
+	}
+	for p.is(luaTokSymbol, ".") {
+		if err := p.advance(); err != nil {
+			return "", err
+		}
+		part, err := p.expectName()
+		if err != nil {
+			return "", err
+		}
+		name += "." + part
+	}
+	return name, nil
+}
+
+func (p *luaParser) parseValue() (LuaValue, error) {
+	tok := p.tok
+	switch {
+	case p.is(luaTokSymbol, "{"):
+		return p.parseTable()
+	case p.is(luaTokSymbol, "-"):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if p.tok.kind != luaTokNumber {
+			return nil, p.lx.errorf(p.tok.start, "expected number after '-'")
+		}
+		end := p.tok.end
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		raw := p.lx.src[tok.start:end]
+		return &LuaScalar{Kind: LuaNumber, Raw: raw, Value: raw, Start: tok.start, End: end}, nil
+	case tok.kind == luaTokNumber:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		return &LuaScalar{Kind: LuaNumber, Raw: tok.text, Value: tok.text, Start: tok.start, End: tok.end}, nil
+	case tok.kind == luaTokString:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		return &LuaScalar{Kind: LuaString, Raw: tok.text, Value: unquoteLua(tok.text), Start: tok.start, End: tok.end}, nil
+	case tok.kind == luaTokName:
+		kind := LuaIdent
+		switch tok.text {
+		case "true", "false":
+			kind = LuaBool
+		case "nil":
+			kind = LuaNil
+		case "function", "end", "return", "local":
+			return nil, p.lx.errorf(tok.start, "unsupported expression %q", tok.text)
+		}
+		name, err := p.parseDottedName()
+		if err != nil {
+			return nil, err
+		}
+		return &LuaScalar{Kind: kind, Raw: name, Value: name, Start: tok.start, End: p.prevEnd}, nil
+	}
+	return nil, p.lx.errorf(tok.start, "unexpected %q", tok.text)
+}
+
+func (p *luaParser) parseTable() (*LuaTable, error) {
+	t := &LuaTable{Start: p.tok.start}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	for !p.is(luaTokSymbol, "}") {
+		if p.tok.kind == luaTokEOF {
+			return nil, p.lx.errorf(t.Start, "unclosed table")
+		}
+
+		f := &LuaField{Start: p.tok.start}
+		switch {
+		case p.is(luaTokSymbol, "["):
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			keyVal, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			key, ok := keyVal.(*LuaScalar)
+			if !ok {
+				return nil, p.lx.errorf(f.Start, "table keys must be scalars")
+			}
+			f.Key = key.Value
+			if err := p.expect(luaTokSymbol, "]"); err != nil {
+				return nil, err
+			}
+			if err := p.expect(luaTokSymbol, "="); err != nil {
+				return nil, err
+			}
+		case p.tok.kind == luaTokName && p.peekIsAssign():
+			f.Key = p.tok.text
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if err := p.expect(luaTokSymbol, "="); err != nil {
+				return nil, err
+			}
+		}
+
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		f.Value = value
+		f.End = p.prevEnd
+		t.Fields = append(t.Fields, f)
+
+		if p.is(luaTokSymbol, ",") || p.is(luaTokSymbol, ";") {
+			f.HasSep = true
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if !p.is(luaTokSymbol, "}") {
+			return nil, p.lx.errorf(p.tok.start, "expected ',' or '}', got %q", p.tok.text)
+		}
+	}
+
+	t.End = p.tok.end
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// peekIsAssign 判断当前 Name 之后是否紧跟 "="（区分 `key = v` 与位置字段 `ident`）。
+func (p *luaParser) peekIsAssign() bool {
+	saved := *p.lx
+	saved.comments = nil
+	tok, err := saved.next()
+	return err == nil && tok.kind == luaTokSymbol && tok.text == "="
+}
+
+func unquoteLua(raw string) string {
+	if level := longBracketLevel(raw); level >= 0 {
+		body := raw[level+2 : len(raw)-level-2]
+		return strings.TrimPrefix(body, "\n")
+	}
+	if len(raw) < 2 {
+		return raw
+	}
+
+	body := raw[1 : len(raw)-1]
+	if !strings.Contains(body, "\\") {
+		return body
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		if c != '\\' || i+1 >= len(body) {
+			sb.WriteByte(c)
+			continue
+		}
+		i++
+		switch body[i] {
+		case 'n':
+			sb.WriteByte('\n')
+		case 't':
+			sb.WriteByte('\t')
+		case 'r':
+			sb.WriteByte('\r')
+		case '\\', '"', '\'':
+			sb.WriteByte(body[i])
+		default:
+			if isDigit(body[i]) {
+				j := i
+				for j < len(body) && j < i+3 && isDigit(body[j]) {
+					j++
+				}
+				if n, err := strconv.Atoi(body[i:j]); err == nil && n < 256 {
+					sb.WriteByte(byte(n))
+					i = j - 1
+					continue
+				}
+			}
+			sb.WriteByte('\\')
+			sb.WriteByte(body[i])
+		}
+	}
+	return sb.String()
+}
+
+// ---- editing ----
+
+type luaEdit struct {
+	start int
+	end   int
+	text  string
+	seq   int
+}
+
+// LuaEditor 在原始文本上记录区间替换，未改动部分逐字节保留。
+type LuaEditor struct {
+	src   string
+	edits []luaEdit
+}
+
+func NewLuaEditor(src string) *LuaEditor {
+	return &LuaEditor{src: src}
+}
+
+// Replace 将 [start,end) 替换为 text；start == end 时为插入。
+func (e *LuaEditor) Replace(start, end int, text string) {
+	e.edits = append(e.edits, luaEdit{start: start, end: end, text: text, seq: len(e.edits)})
+}
+
+// Insert 在 pos 处插入 text，同一位置的多次插入按调用顺序排列。
+func (e *LuaEditor) Insert(pos int, text string) {
+	e.Replace(pos, pos, text)
+}
+
+func (e *LuaEditor) String() string {
+	if len(e.edits) == 0 {
+		return e.src
+	}
+	edits := append([]luaEdit(nil), e.edits...)
+	sort.SliceStable(edits, func(i, j int) bool {
+		if edits[i].start != edits[j].start {
+			return edits[i].start < edits[j].start
+		}
+		return edits[i].seq < edits[j].seq
+	})
+
+	var sb strings.Builder
+	pos := 0
+	for _, ed := range edits {
+		if ed.start < pos {
+			// 与前一个编辑重叠，忽略。
+			continue
+		}
+		sb.WriteString(e.src[pos:ed.start])
+		sb.WriteString(ed.text)
+		pos = ed.end
+	}
+	sb.WriteString(e.src[pos:])
+	return sb.String()
+}
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseLua_NestedInlineAndComments(t *testing.T) {
+	src := `--[[ header
+block comment ]]
+SandboxVars = {
+    VERSION = 5,
+    -- line comment
+    Name = "a \"quoted\" value",
+    Neg = -1.5,
+    Map = { AllowMiniMap = false, Inner = { Depth = 3 } },
+    Last = 1
+}
+`
+	chunk, err := ParseLua([]byte(src))
+	if err != nil {
+		t.Fatalf("ParseLua: %v", err)
+	}
+	if len(chunk.Comments) != 2 {
+		t.Fatalf("comments=%+v", chunk.Comments)
+	}
+
+	root := chunk.Global("SandboxVars")
+	if root == nil {
+		t.Fatalf("SandboxVars not found")
+	}
+	entries := flattenSandbox(root, "")
+
+	got := map[string]string{}
+	for _, e := range entries {
+		got[e.Key] = e.Scalar.Value
+	}
+	want := map[string]string{
+		"VERSION":          "5",
+		"Name":             `a "quoted" value`,
+		"Neg":              "-1.5",
+		"Map.AllowMiniMap": "false",
+		"Map.Inner.Depth":  "3",
+		"Last":             "1",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Fatalf("%s=%q want %q (all=%v)", k, got[k], v, got)
+		}
+	}
+}
+
+func TestParseLua_FunctionReturn(t *testing.T) {
+	src := "function SpawnRegions()\n\treturn {\n\t\t{ name = \"A\", file = \"a.lua\" },\n\t}\nend\n"
+	chunk, err := ParseLua([]byte(src))
+	if err != nil {
+		t.Fatalf("ParseLua: %v", err)
+	}
+	table := chunk.FuncReturn("SpawnRegions")
+	if table == nil || len(table.Fields) != 1 || table.Fields[0].Key != "" {
+		t.Fatalf("unexpected table: %+v", table)
+	}
+}
+
+func TestParseLua_ReportsLine(t *testing.T) {
+	_, err := ParseLua([]byte("SandboxVars = {\n    A = 1,\n    B = ,\n}\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Fatalf("err=%v", err)
+	}
+}
+
+func TestService_UpdateSandboxLua_RoundTripFullFixture(t *testing.T) {
+	path := filepath.Join("..", "..", "testdata", "mock_zomboid_full", "mock_zomboid", "Server", "servertest_SandboxVars.lua")
+	original, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	chunk, err := ParseLua(original)
+	if err != nil {
+		t.Fatalf("ParseLua: %v", err)
+	}
+	root, _ := sandboxTable(chunk)
+
+	var items []Item
+	for _, e := range flattenSandbox(root, "") {
+		items = append(items, Item{Key: e.Key, Value: e.Scalar.Value})
+	}
+
+	out, err := Service{}.UpdateSandboxLua(original, items)
+	if err != nil {
+		t.Fatalf("UpdateSandboxLua: %v", err)
+	}
+	if out != string(original) {
+		t.Fatalf("expected byte-for-byte round trip")
+	}
+}
+
+func TestService_UpdateSandboxLua_ChangesOnlyEditedValues(t *testing.T) {
+	src := `SandboxVars = {
+    VERSION = 5,
+    -- keep me
+    Zombies = 3,
+    Label = "10",
+    ZombieLore = {
+        Speed = 2
+    },
+}
+`
+	out, err := Service{}.UpdateSandboxLua([]byte(src), []Item{
+		{Key: "VERSION", Value: "5"},
+		{Key: "Zombies", Value: "4"},
+		{Key: "Label", Value: "11"},
+		{Key: "ZombieLore.Speed", Value: "2"},
+		{Key: "ZombieLore.Memory", Value: "1"},
+		{Key: "Map.AllowMiniMap", Value: "true"},
+	})
+	if err != nil {
+		t.Fatalf("UpdateSandboxLua: %v", err)
+	}
+
+	want := `SandboxVars = {
+    VERSION = 5,
+    -- keep me
+    Zombies = 4,
+    Label = "11",
+    ZombieLore = {
+        Speed = 2,
+        Memory = 1,
+    },
+    Map = {
+        AllowMiniMap = true,
+    },
+}
+`
+	if out != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", out, want)
+	}
+	if _, err := ParseLua([]byte(out)); err != nil {
+		t.Fatalf("output does not parse: %v", err)
+	}
+}
+
+func TestService_GenerateSandboxLua_KeepsVersionAndDeepNesting(t *testing.T) {
+	out := Service{}.GenerateSandboxLua([]Item{
+		{Key: "VERSION", Value: "5"},
+		{Key: "A.B.C", Value: "1"},
+	})
+	if !strings.Contains(out, "VERSION = 5,") {
+		t.Fatalf("missing version:\n%s", out)
+	}
+	chunk, err := ParseLua([]byte(out))
+	if err != nil {
+		t.Fatalf("ParseLua: %v\n%s", err, out)
+	}
+	root, _ := sandboxTable(chunk)
+	entries := flattenSandbox(root, "")
+	if len(entries) != 2 || entries[1].Key != "A.B.C" {
+		t.Fatalf("entries=%+v", entries)
+	}
+}
@@ -14,4 +14,13 @@ type Item struct {
 	Tooltip string   `json:"tooltip"`
 	Section string   `json:"section"`
 	Options []Option `json:"options,omitempty"`
+
+	// 以下字段由 Schema 推断，仅用于展示与校验提示。
+	Type    string   `json:"type,omitempty"`
+	Min     *float64 `json:"min,omitempty"`
+	Max     *float64 `json:"max,omitempty"`
+	Default string   `json:"default,omitempty"`
+
+	// Sensitive 为 true 时 Value 已被替换为 SecretPlaceholder。
+	Sensitive bool `json:"sensitive,omitempty"`
 }
@@ -0,0 +1,272 @@
+package config
+
+// sandboxDefaults 原版 SandboxVars.lua 的默认值（嵌套 table 以 "Parent.Key" 展开），用于确定每个 key 的类型：
+// 文件中的浮点项可能被保存成整数（如 FoodLootNew = 1），不能作为类型依据。
+var sandboxDefaults = map[string]string{
+	"Zombies":                                "3",
+	"Distribution":                           "1",
+	"ZombieVoronoiNoise":                     "true",
+	"ZombieRespawn":                          "2",
+	"ZombieMigrate":                          "true",
+	"DayLength":                              "3",
+	"StartYear":                              "1",
+	"StartMonth":                             "7",
+	"StartDay":                               "9",
+	"StartTime":                              "2",
+	"DayNightCycle":                          "1",
+	"ClimateCycle":                           "12",
+	"FogCycle":                               "1",
+	"WaterShut":                              "2",
+	"ElecShut":                               "2",
+	"AlarmDecay":                             "2",
+	"WaterShutModifier":                      "14",
+	"ElecShutModifier":                       "14",
+	"AlarmDecayModifier":                     "14",
+	"FoodLootNew":                            "0.6",
+	"LiteratureLootNew":                      "0.6",
+	"MedicalLootNew":                         "0.6",
+	"SurvivalGearsLootNew":                   "0.6",
+	"CannedFoodLootNew":                      "0.6",
+	"WeaponLootNew":                          "0.6",
+	"RangedWeaponLootNew":                    "0.6",
+	"AmmoLootNew":                            "0.6",
+	"MechanicsLootNew":                       "0.6",
+	"OtherLootNew":                           "0.6",
+	"ClothingLootNew":                        "0.6",
+	"ContainerLootNew":                       "0.6",
+	"KeyLootNew":                             "0.6",
+	"MediaLootNew":                           "0.6",
+	"MementoLootNew":                         "0.6",
+	"CookwareLootNew":                        "0.6",
+	"MaterialLootNew":                        "0.6",
+	"FarmingLootNew":                         "0.6",
+	"ToolLootNew":                            "0.6",
+	"RollsMultiplier":                        "1.0",
+	"LootItemRemovalList":                    "",
+	"RemoveStoryLoot":                        "false",
+	"RemoveZombieLoot":                       "false",
+	"ZombiePopLootEffect":                    "10",
+	"InsaneLootFactor":                       "0.05",
+	"ExtremeLootFactor":                      "0.2",
+	"RareLootFactor":                         "0.6",
+	"NormalLootFactor":                       "1.0",
+	"CommonLootFactor":                       "2.0",
+	"AbundantLootFactor":                     "3.0",
+	"Temperature":                            "3",
+	"Rain":                                   "3",
+	"ErosionSpeed":                           "3",
+	"ErosionDays":                            "0",
+	"Farming":                                "3",
+	"CompostTime":                            "2",
+	"StatsDecrease":                          "3",
+	"NatureAbundance":                        "3",
+	"Alarm":                                  "4",
+	"LockedHouses":                           "6",
+	"StarterKit":                             "false",
+	"Nutrition":                              "true",
+	"FoodRotSpeed":                           "3",
+	"FridgeFactor":                           "3",
+	"SeenHoursPreventLootRespawn":            "0",
+	"HoursForLootRespawn":                    "0",
+	"MaxItemsForLootRespawn":                 "5",
+	"ConstructionPreventsLootRespawn":        "true",
+	"WorldItemRemovalList":                   "Base.Hat,Base.Glasses,Base.Maggots,Base.Slug,Base.Slug2,Base.Snail,Base.Worm,Base.Dung_Mouse,Base.Dung_Rat",
+	"HoursForWorldItemRemoval":               "24.0",
+	"ItemRemovalListBlacklistToggle":         "false",
+	"TimeSinceApo":                           "1",
+	"PlantResilience":                        "3",
+	"PlantAbundance":                         "3",
+	"EndRegen":                               "3",
+	"Helicopter":                             "2",
+	"MetaEvent":                              "2",
+	"SleepingEvent":                          "1",
+	"GeneratorFuelConsumption":               "0.1",
+	"GeneratorSpawning":                      "4",
+	"AnnotatedMapChance":                     "4",
+	"CharacterFreePoints":                    "0",
+	"ConstructionBonusPoints":                "3",
+	"NightDarkness":                          "3",
+	"NightLength":                            "3",
+	"BoneFracture":                           "true",
+	"InjurySeverity":                         "2",
+	"HoursForCorpseRemoval":                  "216.0",
+	"DecayingCorpseHealthImpact":             "3",
+	"ZombieHealthImpact":                     "false",
+	"BloodLevel":                             "3",
+	"ClothingDegradation":                    "3",
+	"FireSpread":                             "true",
+	"DaysForRottenFoodRemoval":               "-1",
+	"AllowExteriorGenerator":                 "true",
+	"MaxFogIntensity":                        "1",
+	"MaxRainFxIntensity":                     "1",
+	"EnableSnowOnGround":                     "true",
+	"AttackBlockMovements":                   "true",
+	"SurvivorHouseChance":                    "3",
+	"VehicleStoryChance":                     "3",
+	"ZoneStoryChance":                        "3",
+	"AllClothesUnlocked":                     "false",
+	"EnableTaintedWaterText":                 "true",
+	"EnableVehicles":                         "true",
+	"CarSpawnRate":                           "3",
+	"ZombieAttractionMultiplier":             "1.0",
+	"VehicleEasyUse":                         "false",
+	"InitialGas":                             "2",
+	"FuelStationGasInfinite":                 "false",
+	"FuelStationGasMin":                      "0.0",
+	"FuelStationGasMax":                      "0.7",
+	"FuelStationGasEmptyChance":              "20",
+	"LockedCar":                              "3",
+	"CarGasConsumption":                      "1.0",
+	"CarGeneralCondition":                    "2",
+	"CarDamageOnImpact":                      "3",
+	"DamageToPlayerFromHitByACar":            "1",
+	"TrafficJam":                             "true",
+	"CarAlarm":                               "2",
+	"PlayerDamageFromCrash":                  "true",
+	"SirenShutoffHours":                      "0.0",
+	"ChanceHasGas":                           "1",
+	"RecentlySurvivorVehicles":               "2",
+	"MultiHitZombies":                        "false",
+	"RearVulnerability":                      "3",
+	"SirenEffectsZombies":                    "true",
+	"AnimalStatsModifier":                    "4",
+	"AnimalMetaStatsModifier":                "4",
+	"AnimalPregnancyTime":                    "2",
+	"AnimalAgeModifier":                      "3",
+	"AnimalMilkIncModifier":                  "3",
+	"AnimalWoolIncModifier":                  "3",
+	"AnimalRanchChance":                      "7",
+	"AnimalGrassRegrowTime":                  "240",
+	"AnimalMetaPredator":                     "false",
+	"AnimalMatingSeason":                     "true",
+	"AnimalEggHatch":                         "3",
+	"AnimalSoundAttractZombies":              "false",
+	"AnimalTrackChance":                      "4",
+	"AnimalPathChance":                       "4",
+	"MaximumRatIndex":                        "25",
+	"DaysUntilMaximumRatIndex":               "90",
+	"MetaKnowledge":                          "3",
+	"SeeNotLearntRecipe":                     "true",
+	"MaximumLootedBuildingRooms":             "50",
+	"EnablePoisoning":                        "1",
+	"MaggotSpawn":                            "1",
+	"LightBulbLifespan":                      "1.0",
+	"FishAbundance":                          "3",
+	"LevelForMediaXPCutoff":                  "3",
+	"LevelForDismantleXPCutoff":              "0",
+	"BloodSplatLifespanDays":                 "0",
+	"LiteratureCooldown":                     "90",
+	"NegativeTraitsPenalty":                  "1",
+	"MinutesPerPage":                         "0.3",
+	"KillInsideCrops":                        "true",
+	"PlantGrowingSeasons":                    "true",
+	"PlaceDirtAboveground":                   "false",
+	"FarmingSpeedNew":                        "1.0",
+	"FarmingAmountNew":                       "1.0",
+	"MaximumLooted":                          "50",
+	"DaysUntilMaximumLooted":                 "90",
+	"RuralLooted":                            "0.5",
+	"MaximumDiminishedLoot":                  "0",
+	"DaysUntilMaximumDiminishedLoot":         "3650",
+	"MuscleStrainFactor":                     "1.0",
+	"DiscomfortFactor":                       "1.0",
+	"WoundInfectionFactor":                   "0.0",
+	"NoBlackClothes":                         "true",
+	"EasyClimbing":                           "false",
+	"MaximumFireFuelHours":                   "8",
+	"FirearmUseDamageChance":                 "true",
+	"FirearmNoiseMultiplier":                 "1.0",
+	"FirearmJamMultiplier":                   "0.0",
+	"FirearmMoodleMultiplier":                "1.0",
+	"FirearmWeatherMultiplier":               "1.0",
+	"FirearmHeadGearEffect":                  "true",
+	"ClayLakeChance":                         "0.05",
+	"ClayRiverChance":                        "0.05",
+	"GeneratorTileRange":                     "20",
+	"GeneratorVerticalPowerRange":            "3",
+	"Basement.SpawnFrequency":                "4",
+	"Map.AllowMiniMap":                       "false",
+	"Map.AllowWorldMap":                      "true",
+	"Map.MapAllKnown":                        "false",
+	"Map.MapNeedsLight":                      "true",
+	"MultiplierConfig.Global":                "1.0",
+	"MultiplierConfig.GlobalToggle":          "true",
+	"MultiplierConfig.Fitness":               "10.0",
+	"MultiplierConfig.Strength":              "10.0",
+	"MultiplierConfig.Sprinting":             "10.0",
+	"MultiplierConfig.Lightfoot":             "10.0",
+	"MultiplierConfig.Nimble":                "10.0",
+	"MultiplierConfig.Sneak":                 "10.0",
+	"MultiplierConfig.Axe":                   "10.0",
+	"MultiplierConfig.Blunt":                 "10.0",
+	"MultiplierConfig.SmallBlunt":            "10.0",
+	"MultiplierConfig.LongBlade":             "10.0",
+	"MultiplierConfig.SmallBlade":            "10.0",
+	"MultiplierConfig.Spear":                 "10.0",
+	"MultiplierConfig.Maintenance":           "10.0",
+	"MultiplierConfig.Woodwork":              "10.0",
+	"MultiplierConfig.Cooking":               "10.0",
+	"MultiplierConfig.Farming":               "10.0",
+	"MultiplierConfig.Doctor":                "10.0",
+	"MultiplierConfig.Electricity":           "10.0",
+	"MultiplierConfig.MetalWelding":          "10.0",
+	"MultiplierConfig.Mechanics":             "10.0",
+	"MultiplierConfig.Tailoring":             "10.0",
+	"MultiplierConfig.Aiming":                "10.0",
+	"MultiplierConfig.Reloading":             "10.0",
+	"MultiplierConfig.Fishing":               "10.0",
+	"MultiplierConfig.Trapping":              "10.0",
+	"MultiplierConfig.PlantScavenging":       "10.0",
+	"MultiplierConfig.FlintKnapping":         "10.0",
+	"MultiplierConfig.Masonry":               "10.0",
+	"MultiplierConfig.Pottery":               "10.0",
+	"MultiplierConfig.Carving":               "20.0",
+	"MultiplierConfig.Husbandry":             "10.0",
+	"MultiplierConfig.Tracking":              "10.0",
+	"MultiplierConfig.Blacksmith":            "10.0",
+	"MultiplierConfig.Butchering":            "10.0",
+	"MultiplierConfig.Glassmaking":           "10.0",
+	"ZombieConfig.PopulationMultiplier":      "0.65",
+	"ZombieConfig.PopulationStartMultiplier": "1.0",
+	"ZombieConfig.PopulationPeakMultiplier":  "1.5",
+	"ZombieConfig.PopulationPeakDay":         "28",
+	"ZombieConfig.RespawnHours":              "72.0",
+	"ZombieConfig.RespawnUnseenHours":        "16.0",
+	"ZombieConfig.RespawnMultiplier":         "0.1",
+	"ZombieConfig.RedistributeHours":         "12.0",
+	"ZombieConfig.FollowSoundDistance":       "100",
+	"ZombieConfig.RallyGroupSize":            "20",
+	"ZombieConfig.RallyGroupSizeVariance":    "50",
+	"ZombieConfig.RallyTravelDistance":       "20",
+	"ZombieConfig.RallyGroupSeparation":      "15",
+	"ZombieConfig.RallyGroupRadius":          "3",
+	"ZombieConfig.ZombiesCountBeforeDelete":  "300",
+	"ZombieLore.Speed":                       "2",
+	"ZombieLore.SprinterPercentage":          "0",
+	"ZombieLore.Strength":                    "2",
+	"ZombieLore.Toughness":                   "2",
+	"ZombieLore.Transmission":                "1",
+	"ZombieLore.Mortality":                   "5",
+	"ZombieLore.Reanimate":                   "3",
+	"ZombieLore.Cognition":                   "3",
+	"ZombieLore.CrawlUnderVehicle":           "5",
+	"ZombieLore.Memory":                      "2",
+	"ZombieLore.Sight":                       "2",
+	"ZombieLore.Hearing":                     "2",
+	"ZombieLore.SpottedLogic":                "true",
+	"ZombieLore.ThumpNoChasing":              "false",
+	"ZombieLore.ThumpOnConstruction":         "true",
+	"ZombieLore.ActiveOnly":                  "1",
+	"ZombieLore.TriggerHouseAlarm":           "false",
+	"ZombieLore.ZombiesDragDown":             "true",
+	"ZombieLore.ZombiesCrawlersDragDown":     "false",
+	"ZombieLore.ZombiesFenceLunge":           "true",
+	"ZombieLore.ZombiesArmorFactor":          "2.0",
+	"ZombieLore.ZombiesMaxDefense":           "85",
+	"ZombieLore.ChanceOfAttachedWeapon":      "6",
+	"ZombieLore.ZombiesFallDamage":           "1.0",
+	"ZombieLore.DisableFakeDead":             "1",
+	"ZombieLore.PlayerSpawnZombieRemoval":    "1",
+	"ZombieLore.FenceThumpersRequired":       "50",
+	"ZombieLore.FenceDamageMultiplier":       "1.0",
+}
@@ -0,0 +1,200 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+const sandboxVarsName = "SandboxVars"
+
+// sandboxEntry SandboxVars 中的一个标量，Key 为以 "." 连接的完整路径。
+type sandboxEntry struct {
+	Key    string
+	Scalar *LuaScalar
+}
+
+// sandboxTable 返回 SandboxVars 顶层 table。
+func sandboxTable(chunk *LuaChunk) (*LuaTable, error) {
+	t := chunk.Global(sandboxVarsName)
+	if t == nil {
+		return nil, fmt.Errorf("%s table not found", sandboxVarsName)
+	}
+	return t, nil
+}
+
+// flattenSandbox 深度优先展开嵌套 table，保持文件中的顺序。
+func flattenSandbox(t *LuaTable, prefix string) []sandboxEntry {
+	var out []sandboxEntry
+	for _, f := range t.Fields {
+		if f.Key == "" {
+			continue
+		}
+		key := f.Key
+		if prefix != "" {
+			key = prefix + "." + f.Key
+		}
+		switch v := f.Value.(type) {
+		case *LuaTable:
+			out = append(out, flattenSandbox(v, key)...)
+		case *LuaScalar:
+			out = append(out, sandboxEntry{Key: key, Scalar: v})
+		}
+	}
+	return out
+}
+
+// updateSandbox 将 items 写回 chunk 对应的源文本，只替换值发生变化的字面量。
+// 文件中不存在的 key 按所属 table 归并后插入到该 table 末尾。
+func updateSandbox(chunk *LuaChunk, items []Item) (string, error) {
+	root, err := sandboxTable(chunk)
+	if err != nil {
+		return "", err
+	}
+
+	ed := NewLuaEditor(chunk.Src)
+	pending := make(map[*LuaTable]*sandboxNode)
+	var order []*LuaTable
+	addPending := func(table *LuaTable, path []string, value string) {
+		node, ok := pending[table]
+		if !ok {
+			node = &sandboxNode{isTable: true}
+			pending[table] = node
+			order = append(order, table)
+		}
+		for _, part := range path[:len(path)-1] {
+			node = node.child(part)
+			node.isTable = true
+		}
+		node.child(path[len(path)-1]).value = value
+	}
+
+	for _, item := range items {
+		path := strings.Split(item.Key, ".")
+		table := root
+		missing := -1
+		for i, part := range path[:len(path)-1] {
+			f := table.Field(part)
+			if f == nil {
+				missing = i
+				break
+			}
+			sub, ok := f.Value.(*LuaTable)
+			if !ok {
+				return "", fmt.Errorf("%s: %s is not a table", item.Key, strings.Join(path[:i+1], "."))
+			}
+			table = sub
+		}
+
+		if missing >= 0 {
+			addPending(table, path[missing:], item.Value)
+			continue
+		}
+
+		f := table.Field(path[len(path)-1])
+		if f == nil {
+			addPending(table, path[len(path)-1:], item.Value)
+			continue
+		}
+		scalar, ok := f.Value.(*LuaScalar)
+		if !ok {
+			return "", fmt.Errorf("%s is a table", item.Key)
+		}
+		if scalar.Value == item.Value {
+			continue
+		}
+		ed.Replace(scalar.Start, scalar.End, formatLuaValueAs(scalar.Kind, item.Value))
+	}
+
+	for _, table := range order {
+		insertSandboxFields(ed, chunk.Src, table, pending[table])
+	}
+	return ed.String(), nil
+}
+
+// insertSandboxFields 在 table 最后一个字段之后插入 node 的子节点。
+func insertSandboxFields(ed *LuaEditor, src string, table *LuaTable, node *sandboxNode) {
+	var sb strings.Builder
+
+	pos := table.Start + 1
+	if n := len(table.Fields); n > 0 {
+		last := table.Fields[n-1]
+		pos = last.End
+		if last.HasSep {
+			pos = skipSeparator(src, last.End)
+		} else {
+			sb.WriteString(",")
+		}
+	}
+
+	var body strings.Builder
+	node.write(&body, fieldIndent(src, table))
+	sb.WriteString("\n")
+	sb.WriteString(strings.TrimSuffix(body.String(), "\n"))
+	if len(table.Fields) == 0 {
+		sb.WriteString("\n" + lineIndent(src, table.End-1))
+	}
+	ed.Insert(pos, sb.String())
+}
+
+// fieldIndent 推断 table 内字段的缩进。
+func fieldIndent(src string, table *LuaTable) string {
+	if len(table.Fields) > 0 {
+		return lineIndent(src, table.Fields[0].Start)
+	}
+	return lineIndent(src, table.Start) + "    "
+}
+
+// lineIndent 返回 pos 所在行的前导空白。
+func lineIndent(src string, pos int) string {
+	lineStart := strings.LastIndex(src[:pos], "\n") + 1
+	i := lineStart
+	for i < len(src) && (src[i] == ' ' || src[i] == '\t') {
+		i++
+	}
+	return src[lineStart:i]
+}
+
+func skipSeparator(src string, pos int) int {
+	for pos < len(src) {
+		switch src[pos] {
+		case ',', ';':
+			return pos + 1
+		case ' ', '\t':
+			pos++
+		default:
+			return pos
+		}
+	}
+	return pos
+}
+
+// sandboxNode 用于从扁平 items 重建嵌套结构，保持首次出现的顺序。
+type sandboxNode struct {
+	key      string
+	value    string
+	children []*sandboxNode
+	isTable  bool
+}
+
+func (n *sandboxNode) child(key string) *sandboxNode {
+	for _, c := range n.children {
+		if c.key == key {
+			return c
+		}
+	}
+	c := &sandboxNode{key: key}
+	n.children = append(n.children, c)
+	return c
+}
+
+func (n *sandboxNode) write(sb *strings.Builder, pad string) {
+	for _, c := range n.children {
+		if c.isTable {
+			sb.WriteString(fmt.Sprintf("%s%s = {\n", pad, c.key))
+			c.write(sb, pad+"    ")
+			sb.WriteString(pad + "},\n")
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("%s%s = %s,\n", pad, c.key, formatLuaValue(c.value)))
+	}
+}
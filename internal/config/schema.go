@@ -0,0 +1,245 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"pz-web-backend/internal/i18n"
+)
+
+// ValueType 配置项的值类型。
+type ValueType string
+
+const (
+	TypeBool   ValueType = "bool"
+	TypeInt    ValueType = "int"
+	TypeFloat  ValueType = "float"
+	TypeEnum   ValueType = "enum"
+	TypeString ValueType = "string"
+)
+
+// FieldSchema 单个 key 的类型、取值范围与默认值。
+type FieldSchema struct {
+	Key     string
+	Type    ValueType
+	Min     *float64
+	Max     *float64
+	Default string
+	// Options 枚举类型允许的取值。
+	Options []string
+}
+
+// Schema 以完整 key（沙盒为 "Parent.Key"）索引的字段定义。
+type Schema map[string]FieldSchema
+
+// FieldError 单个配置项的校验错误。
+type FieldError struct {
+	Key     string `json:"key"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors 保存前校验失败的配置项集合。
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	if len(e) == 1 {
+		return fmt.Sprintf("invalid value for %s: %s", e[0].Key, e[0].Message)
+	}
+	return fmt.Sprintf("%d invalid config values", len(e))
+}
+
+// serverRanges 已知的服务器参数取值范围（其余数值项只校验类型）。
+var serverRanges = map[string][2]float64{
+	"MaxPlayers":            {1, 100},
+	"DefaultPort":           {1, 65535},
+	"UDPPort":               {1, 65535},
+	"RCONPort":              {1, 65535},
+	"PingLimit":             {0, 100000},
+	"MaxAccountsPerUser":    {0, 100},
+	"SaveWorldEveryMinutes": {0, 1440},
+}
+
+// serverStringKeys 以分号分隔的列表项，只有一个元素时看起来像数字，但必须按字符串处理。
+var serverStringKeys = map[string]bool{
+	"Mods":          true,
+	"WorkshopItems": true,
+	"Map":           true,
+	"SpawnItems":    true,
+}
+
+// ServerSchema 字段类型取自原版默认值（serverDefaults），原版没有的 key 才按文件中的值推断；
+// 敏感项与列表项始终是字符串。Default 为文件中的现有值。
+func (s Service) ServerSchema(doc *INIDocument) Schema {
+	schema := make(Schema, len(serverDefaults))
+	for key, v := range serverDefaults {
+		schema[key] = serverField(key, v, v)
+	}
+	for _, entry := range doc.Entries() {
+		typeFrom := entry.Value
+		if v, ok := serverDefaults[entry.Key]; ok {
+			typeFrom = v
+		}
+		schema[entry.Key] = serverField(entry.Key, typeFrom, entry.Value)
+	}
+	return schema
+}
+
+func serverField(key string, typeFrom string, value string) FieldSchema {
+	field := FieldSchema{Key: key, Type: inferValueType(typeFrom), Default: value}
+	if serverStringKeys[key] || IsSecretKey(key) {
+		field.Type = TypeString
+	}
+	if r, ok := serverRanges[key]; ok && field.Type != TypeBool && field.Type != TypeString {
+		field.Min, field.Max = floatPtr(r[0]), floatPtr(r[1])
+	}
+	return field
+}
+
+// SandboxSchema 字段类型取自原版默认值（sandboxDefaults），原版没有的 key 才按文件中的字面量推断；
+// 在翻译文件中带 "_optionN" 的 key 视为枚举，取值范围为 1..N。Default 为文件中的现有值。
+func (s Service) SandboxSchema(chunk *LuaChunk) (Schema, error) {
+	root, err := sandboxTable(chunk)
+	if err != nil {
+		return nil, err
+	}
+	return s.sandboxSchema(flattenSandbox(root, "")), nil
+}
+
+// DefaultSandboxSchema 文件不存在时使用，只包含原版 key。
+func (s Service) DefaultSandboxSchema() Schema {
+	return s.sandboxSchema(nil)
+}
+
+func (s Service) sandboxSchema(entries []sandboxEntry) Schema {
+	var dict i18n.TranslationMap
+	if s.I18n != nil {
+		dict = s.I18n.GetTranslationMap("EN")
+	}
+
+	schema := make(Schema, len(sandboxDefaults))
+	for key, v := range sandboxDefaults {
+		schema[key] = sandboxField(dict, key, inferValueType(v), v)
+	}
+	for _, entry := range entries {
+		var typ ValueType
+		switch entry.Scalar.Kind {
+		case LuaBool:
+			typ = TypeBool
+		case LuaNumber:
+			typ = inferValueType(entry.Scalar.Value)
+		default:
+			typ = TypeString
+		}
+		if v, ok := sandboxDefaults[entry.Key]; ok {
+			typ = inferValueType(v)
+		}
+		schema[entry.Key] = sandboxField(dict, entry.Key, typ, entry.Scalar.Value)
+	}
+	return schema
+}
+
+func sandboxField(dict i18n.TranslationMap, key string, typ ValueType, value string) FieldSchema {
+	field := FieldSchema{Key: key, Type: typ, Default: value}
+	rawKey := key[strings.LastIndex(key, ".")+1:]
+	if options := sandboxOptions(dict, rawKey); len(options) > 0 && field.Type == TypeInt {
+		field.Type = TypeEnum
+		for _, o := range options {
+			field.Options = append(field.Options, o.Value)
+		}
+		field.Min, field.Max = floatPtr(1), floatPtr(float64(len(options)))
+	}
+	return field
+}
+
+// Validate 校验 items；schema 中不存在的 key 不做限制。
+func (sc Schema) Validate(items []Item) ValidationErrors {
+	var errs ValidationErrors
+	for _, item := range items {
+		field, ok := sc[item.Key]
+		if !ok {
+			continue
+		}
+		if msg := field.check(strings.TrimSpace(item.Value)); msg != "" {
+			errs = append(errs, FieldError{Key: item.Key, Message: msg})
+		}
+	}
+	return errs
+}
+
+// Annotate 将类型信息写入 items，便于前端选择控件。
+func (sc Schema) Annotate(items []Item) {
+	for i := range items {
+		field, ok := sc[items[i].Key]
+		if !ok {
+			continue
+		}
+		items[i].Type = string(field.Type)
+		items[i].Min = field.Min
+		items[i].Max = field.Max
+		items[i].Default = field.Default
+	}
+}
+
+func (f FieldSchema) check(v string) string {
+	switch f.Type {
+	case TypeBool:
+		if !isBool(v) {
+			return fmt.Sprintf("%q is not a boolean (true/false)", v)
+		}
+		return ""
+	case TypeInt:
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Sprintf("%q is not an integer", v)
+		}
+		return f.checkRange(float64(n))
+	case TypeFloat:
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Sprintf("%q is not a number", v)
+		}
+		return f.checkRange(n)
+	case TypeEnum:
+		for _, o := range f.Options {
+			if v == o {
+				return ""
+			}
+		}
+		return fmt.Sprintf("%q is not one of %s", v, strings.Join(f.Options, ", "))
+	}
+	return ""
+}
+
+func (f FieldSchema) checkRange(n float64) string {
+	if f.Min != nil && n < *f.Min {
+		return fmt.Sprintf("must be >= %s", formatFloat(*f.Min))
+	}
+	if f.Max != nil && n > *f.Max {
+		return fmt.Sprintf("must be <= %s", formatFloat(*f.Max))
+	}
+	return ""
+}
+
+func inferValueType(v string) ValueType {
+	v = strings.TrimSpace(v)
+	switch {
+	case isBool(v):
+		return TypeBool
+	case v == "":
+		return TypeString
+	}
+	if _, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return TypeInt
+	}
+	if _, err := strconv.ParseFloat(v, 64); err == nil {
+		return TypeFloat
+	}
+	return TypeString
+}
+
+func floatPtr(v float64) *float64 { return &v }
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
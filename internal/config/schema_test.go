@@ -0,0 +1,136 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"pz-web-backend/internal/i18n"
+)
+
+func TestService_SandboxSchema_ValidatesTypesAndEnums(t *testing.T) {
+	base := t.TempDir()
+	langDir := filepath.Join(base, "lua/shared/Translate/EN")
+	if err := os.MkdirAll(langDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	sandboxContent := `{
+Sandbox_Speed_option1 = "Sprinters",
+Sandbox_Speed_option2 = "Fast Shamblers",
+Sandbox_Speed_option3 = "Shamblers",
+}`
+	if err := os.WriteFile(filepath.Join(langDir, "Sandbox_EN.txt"), []byte(sandboxContent), 0o644); err != nil {
+		t.Fatalf("write sandbox: %v", err)
+	}
+
+	chunk, err := ParseLua([]byte(`SandboxVars = {
+    Zombies = 3,
+    StarterKit = false,
+    RollsMultiplier = 1.0,
+    LootItemRemovalList = "",
+    ZombieLore = {
+        Speed = 2,
+    },
+}`))
+	if err != nil {
+		t.Fatalf("ParseLua: %v", err)
+	}
+
+	svc := Service{I18n: i18n.NewLoader(base)}
+	schema, err := svc.SandboxSchema(chunk)
+	if err != nil {
+		t.Fatalf("SandboxSchema: %v", err)
+	}
+	if got := schema["ZombieLore.Speed"].Type; got != TypeEnum {
+		t.Fatalf("Speed type=%q", got)
+	}
+
+	errs := schema.Validate([]Item{
+		{Key: "Zombies", Value: "4"},
+		{Key: "StarterKit", Value: "tru"},
+		{Key: "RollsMultiplier", Value: "2"},
+		{Key: "LootItemRemovalList", Value: "Base.Axe"},
+		{Key: "ZombieLore.Speed", Value: "4"},
+		{Key: "Unknown", Value: "whatever"},
+	})
+	if len(errs) != 2 {
+		t.Fatalf("errs=%+v", errs)
+	}
+	if errs[0].Key != "StarterKit" || errs[1].Key != "ZombieLore.Speed" {
+		t.Fatalf("errs=%+v", errs)
+	}
+}
+
+func TestService_ServerSchema_InfersTypesAndKnownRanges(t *testing.T) {
+	doc := ParseINIDocument([]byte("PVP=true\nMaxPlayers=32\nPublicName=My Server\n"))
+	schema := Service{}.ServerSchema(doc)
+
+	if schema["PVP"].Type != TypeBool || schema["MaxPlayers"].Type != TypeInt || schema["PublicName"].Type != TypeString {
+		t.Fatalf("schema=%+v", schema)
+	}
+
+	errs := schema.Validate([]Item{
+		{Key: "PVP", Value: "yes"},
+		{Key: "MaxPlayers", Value: "500"},
+		{Key: "PublicName", Value: "123"},
+	})
+	if len(errs) != 2 || errs[1].Key != "MaxPlayers" || errs[1].Message != "must be <= 100" {
+		t.Fatalf("errs=%+v", errs)
+	}
+}
+
+func TestService_ServerSchema_TypesFromVanillaDefaults(t *testing.T) {
+	doc := ParseINIDocument([]byte("Password=1234\nRCONPassword=5678\nWorkshopItems=2392709985\nMods=123\nMinutesPerPage=2\nCustomKey=7\n"))
+	schema := Service{}.ServerSchema(doc)
+
+	for _, key := range []string{"Password", "RCONPassword", "WorkshopItems", "Mods"} {
+		if schema[key].Type != TypeString {
+			t.Fatalf("%s type=%s", key, schema[key].Type)
+		}
+	}
+	// 原版为 1.0，文件里写成整数也按浮点数校验；原版没有的 key 按文件推断。
+	if schema["MinutesPerPage"].Type != TypeFloat || schema["CustomKey"].Type != TypeInt || schema["MinutesPerPage"].Default != "2" {
+		t.Fatalf("schema=%+v", schema)
+	}
+	// 文件中没有的原版 key 同样参与校验。
+	if schema["MaxPlayers"].Type != TypeInt || schema["MaxPlayers"].Max == nil {
+		t.Fatalf("MaxPlayers=%+v", schema["MaxPlayers"])
+	}
+
+	errs := schema.Validate([]Item{
+		{Key: "WorkshopItems", Value: "1;2"},
+		{Key: "RCONPassword", Value: "s3cret"},
+		{Key: "MinutesPerPage", Value: "1.5"},
+		{Key: "MaxPlayers", Value: "many"},
+	})
+	if len(errs) != 1 || errs[0].Key != "MaxPlayers" {
+		t.Fatalf("errs=%+v", errs)
+	}
+}
+
+func TestService_SandboxSchema_TypesFromVanillaDefaults(t *testing.T) {
+	// 原版为 0.6，上次保存成 1 之后仍按浮点数校验。
+	chunk, err := ParseLua([]byte("SandboxVars = {\n    FoodLootNew = 1,\n    CustomInt = 2,\n    ZombieConfig = {\n        RespawnHours = 72,\n    },\n}\n"))
+	if err != nil {
+		t.Fatalf("ParseLua: %v", err)
+	}
+	schema, err := Service{}.SandboxSchema(chunk)
+	if err != nil {
+		t.Fatalf("SandboxSchema: %v", err)
+	}
+	if schema["FoodLootNew"].Type != TypeFloat || schema["FoodLootNew"].Default != "1" || schema["CustomInt"].Type != TypeInt {
+		t.Fatalf("FoodLootNew=%+v CustomInt=%+v", schema["FoodLootNew"], schema["CustomInt"])
+	}
+	if schema["StarterKit"].Type != TypeBool {
+		t.Fatalf("StarterKit=%+v", schema["StarterKit"])
+	}
+
+	errs := schema.Validate([]Item{
+		{Key: "FoodLootNew", Value: "1.5"},
+		{Key: "ZombieConfig.RespawnHours", Value: "36.5"},
+		{Key: "CustomInt", Value: "1.5"},
+	})
+	if len(errs) != 1 || errs[0].Key != "CustomInt" {
+		t.Fatalf("errs=%+v", errs)
+	}
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"regexp"
+	"strings"
+)
+
+// SecretPlaceholder API 返回的敏感值占位符；保存时原样传回表示保留原值。
+const SecretPlaceholder = "********"
+
+// secretKeys servertest.ini 中需要遮盖的 key。
+var secretKeys = map[string]bool{
+	"Password":     true,
+	"RCONPassword": true,
+	"DiscordToken": true,
+}
+
+// SecretKeys 返回所有敏感 key，顺序固定。
+func SecretKeys() []string {
+	return []string{"Password", "RCONPassword", "DiscordToken"}
+}
+
+func IsSecretKey(key string) bool {
+	return secretKeys[key]
+}
+
+// MaskSecrets 标记敏感项，将非空值替换为占位符并清除由文件推断的 Default；
+// 空值保持为空，便于区分“未设置”。
+func MaskSecrets(items []Item) {
+	for i := range items {
+		if !IsSecretKey(items[i].Key) {
+			continue
+		}
+		items[i].Sensitive = true
+		items[i].Default = ""
+		if items[i].Value != "" {
+			items[i].Value = SecretPlaceholder
+		}
+	}
+}
+
+// KeepSecrets 返回 items 的副本，其中值仍为占位符的敏感项换回 doc 中的当前值。
+// doc 为 nil（文件不存在）时占位符换为空串。
+func KeepSecrets(items []Item, doc *INIDocument) []Item {
+	out := make([]Item, len(items))
+	copy(out, items)
+	for i := range out {
+		if !IsSecretKey(out[i].Key) || out[i].Value != SecretPlaceholder {
+			continue
+		}
+		out[i].Value = ""
+		if doc != nil {
+			out[i].Value, _ = doc.Get(out[i].Key)
+		}
+	}
+	return out
+}
+
+var iniSecretLine = regexp.MustCompile(`(?m)^([ +-]?[ \t]*(?:Password|RCONPassword|DiscordToken)[ \t]*=[ \t]*)([^\r\n]+)`)
+
+// MaskINISecrets 遮盖 INI 文本或其 unified diff 中敏感 key 的值。
+func MaskINISecrets(text string) string {
+	if !strings.Contains(text, "Password") && !strings.Contains(text, "DiscordToken") {
+		return text
+	}
+	return iniSecretLine.ReplaceAllString(text, "${1}"+SecretPlaceholder)
+}
@@ -0,0 +1,17 @@
+package config
+
+import "testing"
+
+func TestMaskINISecrets(t *testing.T) {
+	in := "@@ -1,3 +1,3 @@\n Password=abc\n-RCONPassword=old\n+RCONPassword=new\n AdminPassword=keep\nDiscordToken = tok\n"
+	want := "@@ -1,3 +1,3 @@\n Password=********\n-RCONPassword=********\n+RCONPassword=********\n AdminPassword=keep\nDiscordToken = ********\n"
+	if got := MaskINISecrets(in); got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	items := []Item{{Key: "Password", Value: "", Default: ""}, {Key: "RCONPassword", Value: "x", Default: "x"}}
+	MaskSecrets(items)
+	if items[0].Value != "" || items[1].Value != SecretPlaceholder || items[1].Default != "" || !items[1].Sensitive {
+		t.Fatalf("items=%+v", items)
+	}
+}
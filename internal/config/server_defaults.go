@@ -0,0 +1,145 @@
+package config
+
+// serverDefaults B41 原版 servertest.ini 的默认值，用于确定每个 key 的类型：
+// 现有文件中的值可能被改成别的形式（如纯数字的密码），不能作为类型依据。
+var serverDefaults = map[string]string{
+	"PVP":                                          "false",
+	"PauseEmpty":                                   "true",
+	"GlobalChat":                                   "true",
+	"ChatStreams":                                  "s,r,a,w,y,sh,f,all",
+	"Open":                                         "true",
+	"ServerWelcomeMessage":                         "Welcome to Project Zomboid Multiplayer!",
+	"AutoCreateUserInWhiteList":                    "false",
+	"DisplayUserName":                              "true",
+	"ShowFirstAndLastName":                         "false",
+	"SpawnPoint":                                   "0,0,0",
+	"SafetySystem":                                 "true",
+	"ShowSafety":                                   "true",
+	"SafetyToggleTimer":                            "2",
+	"SafetyCooldownTimer":                          "3",
+	"SpawnItems":                                   "",
+	"DefaultPort":                                  "16261",
+	"UDPPort":                                      "16262",
+	"ResetID":                                      "0",
+	"Mods":                                         "",
+	"Map":                                          "Muldraugh, KY",
+	"DoLuaChecksum":                                "true",
+	"DenyLoginOnOverloadedServer":                  "true",
+	"Public":                                       "false",
+	"PublicName":                                   "My PZ Server",
+	"PublicDescription":                            "",
+	"MaxPlayers":                                   "32",
+	"PingLimit":                                    "400",
+	"HoursForLootRespawn":                          "0",
+	"MaxItemsForLootRespawn":                       "4",
+	"ConstructionPreventsLootRespawn":              "true",
+	"DropOffWhiteListAfterDeath":                   "false",
+	"NoFire":                                       "false",
+	"AnnounceDeath":                                "false",
+	"MinutesPerPage":                               "1.0",
+	"SaveWorldEveryMinutes":                        "0",
+	"PlayerSafehouse":                              "false",
+	"AdminSafehouse":                               "false",
+	"SafehouseAllowTrepass":                        "true",
+	"SafehouseAllowFire":                           "true",
+	"SafehouseAllowLoot":                           "true",
+	"SafehouseAllowRespawn":                        "false",
+	"SafehouseDaySurvivedToClaim":                  "0",
+	"SafeHouseRemovalTime":                         "144",
+	"SafehouseAllowNonResidential":                 "false",
+	"AllowDestructionBySledgehammer":               "true",
+	"SledgehammerOnlyInSafehouse":                  "false",
+	"KickFastPlayers":                              "false",
+	"ServerPlayerID":                               "0",
+	"RCONPort":                                     "27015",
+	"RCONPassword":                                 "",
+	"DiscordEnable":                                "false",
+	"DiscordToken":                                 "",
+	"DiscordChannel":                               "",
+	"DiscordChannelID":                             "",
+	"Password":                                     "",
+	"MaxAccountsPerUser":                           "0",
+	"AllowCoop":                                    "true",
+	"SleepAllowed":                                 "false",
+	"SleepNeeded":                                  "false",
+	"KnockedDownAllowed":                           "true",
+	"SneakModeHideFromOtherPlayers":                "true",
+	"WorkshopItems":                                "",
+	"SteamScoreboard":                              "true",
+	"SteamVAC":                                     "true",
+	"UPnP":                                         "true",
+	"VoiceEnable":                                  "true",
+	"VoiceMinDistance":                             "10.0",
+	"VoiceMaxDistance":                             "100.0",
+	"Voice3D":                                      "true",
+	"SpeedLimit":                                   "70.0",
+	"LoginQueueEnabled":                            "false",
+	"LoginQueueConnectTimeout":                     "60",
+	"server_browser_announced_ip":                  "",
+	"PlayerRespawnWithSelf":                        "false",
+	"PlayerRespawnWithOther":                       "false",
+	"FastForwardMultiplier":                        "40.0",
+	"DisableSafehouseWhenPlayerConnected":          "false",
+	"Faction":                                      "true",
+	"FactionDaySurvivedToCreate":                   "0",
+	"FactionPlayersRequiredForTag":                 "1",
+	"DisableRadioStaff":                            "false",
+	"DisableRadioAdmin":                            "true",
+	"DisableRadioGM":                               "true",
+	"DisableRadioOverseer":                         "false",
+	"DisableRadioModerator":                        "false",
+	"DisableRadioInvisible":                        "true",
+	"ClientCommandFilter":                          "-vehicle.*;+vehicle.damageWindow;+vehicle.fixPart;+vehicle.installPart;+vehicle.uninstallPart",
+	"ClientActionLogs":                             "ISEnterVehicle;ISExitVehicle;ISTakeEngineParts;",
+	"PerkLogs":                                     "true",
+	"ItemNumbersLimitPerContainer":                 "0",
+	"BloodSplatLifespanDays":                       "0",
+	"AllowNonAsciiUsername":                        "false",
+	"BanKickGlobalSound":                           "true",
+	"RemovePlayerCorpsesOnCorpseRemoval":           "false",
+	"TrashDeleteAll":                               "false",
+	"PVPMeleeWhileHitReaction":                     "false",
+	"MouseOverToSeeDisplayName":                    "true",
+	"HidePlayersBehindYou":                         "true",
+	"PVPMeleeDamageModifier":                       "30.0",
+	"PVPFirearmDamageModifier":                     "50.0",
+	"CarEngineAttractionModifier":                  "0.5",
+	"PlayerBumpPlayer":                             "false",
+	"MapRemotePlayerVisibility":                    "1",
+	"BackupsCount":                                 "5",
+	"BackupsOnStart":                               "true",
+	"BackupsOnVersionChange":                       "true",
+	"BackupsPeriod":                                "0",
+	"AntiCheatProtectionType1":                     "true",
+	"AntiCheatProtectionType2":                     "true",
+	"AntiCheatProtectionType3":                     "true",
+	"AntiCheatProtectionType4":                     "true",
+	"AntiCheatProtectionType5":                     "true",
+	"AntiCheatProtectionType6":                     "true",
+	"AntiCheatProtectionType7":                     "true",
+	"AntiCheatProtectionType8":                     "true",
+	"AntiCheatProtectionType9":                     "true",
+	"AntiCheatProtectionType10":                    "true",
+	"AntiCheatProtectionType11":                    "true",
+	"AntiCheatProtectionType12":                    "true",
+	"AntiCheatProtectionType13":                    "true",
+	"AntiCheatProtectionType14":                    "true",
+	"AntiCheatProtectionType15":                    "true",
+	"AntiCheatProtectionType16":                    "true",
+	"AntiCheatProtectionType17":                    "true",
+	"AntiCheatProtectionType18":                    "true",
+	"AntiCheatProtectionType19":                    "true",
+	"AntiCheatProtectionType20":                    "true",
+	"AntiCheatProtectionType21":                    "true",
+	"AntiCheatProtectionType22":                    "true",
+	"AntiCheatProtectionType23":                    "true",
+	"AntiCheatProtectionType24":                    "true",
+	"AntiCheatProtectionType2ThresholdMultiplier":  "3.0",
+	"AntiCheatProtectionType3ThresholdMultiplier":  "1.0",
+	"AntiCheatProtectionType4ThresholdMultiplier":  "1.0",
+	"AntiCheatProtectionType9ThresholdMultiplier":  "1.0",
+	"AntiCheatProtectionType15ThresholdMultiplier": "1.0",
+	"AntiCheatProtectionType20ThresholdMultiplier": "1.0",
+	"AntiCheatProtectionType22ThresholdMultiplier": "1.0",
+	"AntiCheatProtectionType24ThresholdMultiplier": "6.0",
+}
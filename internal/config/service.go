@@ -1,11 +1,8 @@
 package config
 
 import (
-	"bufio"
 	"fmt"
 	"os"
-	"regexp"
-	"sort"
 	"strings"
 
 	"pz-web-backend/internal/i18n"
@@ -21,127 +18,96 @@ type Service struct {
 }
 
 func (s Service) ParseServerINI(path string, lang string) ([]Item, error) {
-	dict := s.I18n.GetTranslationMap(lang)
-	f, err := os.Open(path)
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
-	defer f.Close()
-
-	var items []Item
-	scanner := bufio.NewScanner(f)
-
-	buf := make([]byte, 0, 64*1024)
-	scanner.Buffer(buf, 1024*1024)
-
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
-			continue
-		}
-
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) != 2 {
-			continue
-		}
+	return s.ServerINIItems(ParseINIDocument(data), lang), nil
+}
 
-		key := strings.TrimSpace(parts[0])
-		val := strings.TrimSpace(parts[1])
+// ServerINIItems 将 INI 文档转换为带翻译与分组的配置项列表。
+func (s Service) ServerINIItems(doc *INIDocument, lang string) []Item {
+	dict := s.I18n.GetTranslationMap(lang)
 
-		_, tooltip := i18n.TranslateKey(dict, key, "UI_ServerOption_")
+	var items []Item
+	for _, entry := range doc.Entries() {
+		_, tooltip := i18n.TranslateKey(dict, entry.Key, "UI_ServerOption_")
 		if tooltip == "" {
-			tooltip = key
+			tooltip = entry.Key
 		}
 
-		sectionKey := inferServerSectionKey(key)
+		sectionKey := inferServerSectionKey(entry.Key)
 		section := s.resolveSectionLabel(lang, sectionKey)
 
 		items = append(items, Item{
-			Key:     key,
-			Value:   val,
+			Key:     entry.Key,
+			Value:   entry.Value,
 			Label:   tooltip,
 			Section: section,
 		})
 	}
+	s.ServerSchema(doc).Annotate(items)
+	return items
+}
 
-	if err := scanner.Err(); err != nil {
+func (s Service) ParseSandboxLua(path string, lang string) ([]Item, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
 		return nil, err
 	}
-	return items, nil
+	chunk, err := ParseLua(data)
+	if err != nil {
+		return nil, err
+	}
+	return s.SandboxLuaItems(chunk, lang)
 }
 
-func (s Service) ParseSandboxLua(path string, lang string) ([]Item, error) {
-	dict := s.I18n.GetTranslationMap(lang)
-	f, err := os.Open(path)
+// SandboxLuaItems 将 SandboxVars table 展开为配置项；嵌套 table 的 key 以 "." 连接。
+func (s Service) SandboxLuaItems(chunk *LuaChunk, lang string) ([]Item, error) {
+	root, err := sandboxTable(chunk)
 	if err != nil {
 		return nil, err
 	}
-	defer f.Close()
+	dict := s.I18n.GetTranslationMap(lang)
 
 	var items []Item
+	for _, entry := range flattenSandbox(root, "") {
+		rawKey := entry.Key[strings.LastIndex(entry.Key, ".")+1:]
 
-	reTableStart := regexp.MustCompile(`^\s*([A-Za-z0-9_]+)\s*=\s*\{\s*$`)
-	reTableEnd := regexp.MustCompile(`^\s*\},?\s*$`)
-	reValue := regexp.MustCompile(`^\s*([A-Za-z0-9_]+)\s*=\s*(.*),`)
-
-	scanner := bufio.NewScanner(f)
-	currentContext := ""
-
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" || strings.HasPrefix(line, "--") {
-			continue
-		}
-
-		if matches := reTableStart.FindStringSubmatch(line); len(matches) == 2 {
-			tableName := matches[1]
-			if tableName != "SandboxVars" {
-				currentContext = tableName
-				continue
-			}
-		}
+		label, tooltip := i18n.TranslateKey(dict, rawKey, "Sandbox_")
+		options := sandboxOptions(dict, rawKey)
 
-		if reTableEnd.MatchString(line) {
-			currentContext = ""
-			continue
-		}
-
-		if matches := reValue.FindStringSubmatch(line); len(matches) == 3 {
-			rawKey := matches[1]
-			val := matches[2]
-
-			val = strings.TrimSuffix(val, ",")
-			val = strings.Trim(val, "\"")
-
-			fullKey := rawKey
-			if currentContext != "" {
-				fullKey = currentContext + "." + rawKey
-			}
-
-			label, tooltip := i18n.TranslateKey(dict, rawKey, "Sandbox_")
-			options := sandboxOptions(dict, rawKey)
-
-			sectionKey := inferSandboxSectionKey(fullKey)
-			section := s.resolveSectionLabel(lang, sectionKey)
+		sectionKey := inferSandboxSectionKey(entry.Key)
+		section := s.resolveSectionLabel(lang, sectionKey)
 
-			items = append(items, Item{
-				Key:     fullKey,
-				Value:   val,
-				Label:   label,
-				Tooltip: tooltip,
-				Options: options,
-				Section: section,
-			})
-		}
+		items = append(items, Item{
+			Key:     entry.Key,
+			Value:   entry.Scalar.Value,
+			Label:   label,
+			Tooltip: tooltip,
+			Options: options,
+			Section: section,
+		})
 	}
 
-	if err := scanner.Err(); err != nil {
+	schema, err := s.SandboxSchema(chunk)
+	if err != nil {
 		return nil, err
 	}
-
+	schema.Annotate(items)
 	return items, nil
 }
 
+// UpdateServerINI 将 items 的值写回原始 INI 文本，保留注释、空行与键顺序。
+// 原文件中不存在的 key 追加到末尾。
+func (s Service) UpdateServerINI(original []byte, items []Item) string {
+	doc := ParseINIDocument(original)
+	for _, item := range items {
+		doc.Set(item.Key, item.Value)
+	}
+	return doc.String()
+}
+
 func (s Service) GenerateServerINI(items []Item) string {
 	var sb strings.Builder
 	for _, item := range items {
@@ -150,53 +116,41 @@ func (s Service) GenerateServerINI(items []Item) string {
 	return sb.String()
 }
 
-func (s Service) GenerateSandboxLua(items []Item) string {
-	var sb strings.Builder
-
-	globals := []Item{}
-	nestedMap := make(map[string][]Item)
-
-	for _, item := range items {
-		if strings.Contains(item.Key, ".") {
-			parts := strings.SplitN(item.Key, ".", 2)
-			parent := parts[0]
-			childKey := parts[1]
-
-			subItem := item
-			subItem.Key = childKey
-			nestedMap[parent] = append(nestedMap[parent], subItem)
-		} else {
-			globals = append(globals, item)
-		}
+// UpdateSandboxLua 将 items 写回原始 SandboxVars.lua 文本。
+// 未改动的值、注释、VERSION 与格式逐字节保留。
+func (s Service) UpdateSandboxLua(original []byte, items []Item) (string, error) {
+	chunk, err := ParseLua(original)
+	if err != nil {
+		return "", err
 	}
+	return updateSandbox(chunk, items)
+}
 
-	sb.WriteString("SandboxVars = {\n")
-	sb.WriteString("    VERSION = 6,\n")
-
-	for _, item := range globals {
+// GenerateSandboxLua 在没有原始文件时从 items 生成 SandboxVars.lua。
+// items 中带 VERSION 时沿用其值，否则写入 6。
+func (s Service) GenerateSandboxLua(items []Item) string {
+	version := "6"
+	root := &sandboxNode{isTable: true}
+	for _, item := range items {
 		if item.Key == "VERSION" {
+			if v := strings.TrimSpace(item.Value); v != "" {
+				version = v
+			}
 			continue
 		}
-		val := formatLuaValue(item.Value)
-		sb.WriteString(fmt.Sprintf("    %s = %s,\n", item.Key, val))
-	}
-
-	var sortedTables []string
-	for k := range nestedMap {
-		sortedTables = append(sortedTables, k)
-	}
-	sort.Strings(sortedTables)
-
-	for _, tableName := range sortedTables {
-		subItems := nestedMap[tableName]
-		sb.WriteString(fmt.Sprintf("    %s = {\n", tableName))
-		for _, item := range subItems {
-			val := formatLuaValue(item.Value)
-			sb.WriteString(fmt.Sprintf("        %s = %s,\n", item.Key, val))
+		node := root
+		parts := strings.Split(item.Key, ".")
+		for _, part := range parts[:len(parts)-1] {
+			node = node.child(part)
+			node.isTable = true
 		}
-		sb.WriteString("    },\n")
+		node.child(parts[len(parts)-1]).value = item.Value
 	}
 
+	var sb strings.Builder
+	sb.WriteString("SandboxVars = {\n")
+	sb.WriteString(fmt.Sprintf("    VERSION = %s,\n", version))
+	root.write(&sb, "    ")
 	sb.WriteString("}\n")
 	return sb.String()
 }
@@ -285,7 +239,7 @@ func inferSandboxSectionKey(fullKey string) string {
 	key := fullKey
 	if len(parts) > 1 {
 		parent = parts[0]
-		key = parts[1]
+		key = parts[len(parts)-1]
 	}
 
 	switch parent {
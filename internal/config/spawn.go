@@ -0,0 +1,457 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	spawnRegionsFunc = "SpawnRegions"
+	spawnPointsFunc  = "SpawnPoints"
+)
+
+// SpawnRegion spawnregions.lua 中的一个出生区域。
+type SpawnRegion struct {
+	Name string `json:"name"`
+	// File 游戏目录中的 spawnpoints.lua；ServerFile 为 Server 目录下的文件名，二者取其一。
+	File       string `json:"file,omitempty"`
+	ServerFile string `json:"serverfile,omitempty"`
+	// Disabled 表示该条目在文件中被 "--" 注释掉。
+	Disabled bool `json:"disabled"`
+}
+
+// SpawnPoint 一个出生坐标。WorldX/WorldY 为地图格（cell），PosX/PosY/PosZ 为格内坐标。
+type SpawnPoint struct {
+	WorldX int `json:"worldX"`
+	WorldY int `json:"worldY"`
+	PosX   int `json:"posX"`
+	PosY   int `json:"posY"`
+	PosZ   int `json:"posZ"`
+}
+
+// ProfessionSpawns 某个职业的出生点列表。
+type ProfessionSpawns struct {
+	Profession string       `json:"profession"`
+	Points     []SpawnPoint `json:"points"`
+}
+
+// SpawnRegions 解析 `function SpawnRegions() return {...} end`，
+// 被注释掉的条目以 Disabled 返回，顺序与文件一致。
+func (s Service) SpawnRegions(chunk *LuaChunk) ([]SpawnRegion, error) {
+	table := chunk.FuncReturn(spawnRegionsFunc)
+	if table == nil {
+		return nil, fmt.Errorf("%s() not found", spawnRegionsFunc)
+	}
+	regions, _, err := spawnRegionEntries(chunk, table)
+	return regions, err
+}
+
+// UpdateSpawnRegions 重写返回的 table，保留函数外壳、缩进以及非条目的注释。
+func (s Service) UpdateSpawnRegions(original []byte, regions []SpawnRegion) (string, error) {
+	chunk, err := ParseLua(original)
+	if err != nil {
+		return "", err
+	}
+	table := chunk.FuncReturn(spawnRegionsFunc)
+	if table == nil {
+		return "", fmt.Errorf("%s() not found", spawnRegionsFunc)
+	}
+	existing, notes, err := spawnRegionEntries(chunk, table)
+	if err != nil {
+		return "", err
+	}
+
+	indent := lineIndent(chunk.Src, table.Start) + "\t"
+	if len(table.Fields) > 0 {
+		indent = lineIndent(chunk.Src, table.Fields[0].Start)
+	}
+
+	// 注释跟随其后的条目；条目被删除时注释移到末尾。
+	byName := make(map[string][]string)
+	var trailing []string
+	for _, n := range notes {
+		if n.before < len(existing) {
+			byName[existing[n.before].Name] = append(byName[existing[n.before].Name], n.text)
+		} else {
+			trailing = append(trailing, n.text)
+		}
+	}
+
+	var lines []string
+	for _, r := range regions {
+		for _, text := range byName[r.Name] {
+			lines = append(lines, indent+text)
+		}
+		delete(byName, r.Name)
+		line := indent + formatSpawnRegion(r) + ","
+		if r.Disabled {
+			line = "--" + line
+		}
+		lines = append(lines, line)
+	}
+	for _, r := range existing {
+		for _, text := range byName[r.Name] {
+			lines = append(lines, indent+text)
+		}
+		delete(byName, r.Name)
+	}
+	for _, text := range trailing {
+		lines = append(lines, indent+text)
+	}
+
+	return replaceTableBody(chunk.Src, table, lines), nil
+}
+
+// GenerateSpawnRegions 文件不存在时生成默认的 SpawnRegions() 文件。
+func (s Service) GenerateSpawnRegions(regions []SpawnRegion) string {
+	out, _ := s.UpdateSpawnRegions([]byte("function "+spawnRegionsFunc+"()\n\treturn {\n\t}\nend\n"), regions)
+	return out
+}
+
+// ValidateSpawnRegions 检查必填项；错误 key 形如 "2.name"。
+func ValidateSpawnRegions(regions []SpawnRegion) ValidationErrors {
+	var errs ValidationErrors
+	for i, r := range regions {
+		if strings.TrimSpace(r.Name) == "" {
+			errs = append(errs, FieldError{Key: fmt.Sprintf("%d.name", i), Message: "name is required"})
+		}
+		switch {
+		case r.File == "" && r.ServerFile == "":
+			errs = append(errs, FieldError{Key: fmt.Sprintf("%d.file", i), Message: "file or serverfile is required"})
+		case r.File != "" && r.ServerFile != "":
+			errs = append(errs, FieldError{Key: fmt.Sprintf("%d.file", i), Message: "only one of file and serverfile may be set"})
+		case strings.ContainsAny(r.ServerFile, `/\`):
+			errs = append(errs, FieldError{Key: fmt.Sprintf("%d.serverfile", i), Message: "serverfile must be a file name in the Server directory"})
+		}
+	}
+	return errs
+}
+
+// spawnNote table 中不是条目的注释，before 为其后第一个条目的下标。
+type spawnNote struct {
+	text   string
+	before int
+}
+
+func spawnRegionEntries(chunk *LuaChunk, table *LuaTable) ([]SpawnRegion, []spawnNote, error) {
+	type positioned struct {
+		pos    int
+		region *SpawnRegion
+		note   string
+	}
+	var all []positioned
+
+	for i, f := range table.Fields {
+		t, ok := f.Value.(*LuaTable)
+		if !ok {
+			return nil, nil, fmt.Errorf("%s entry %d is not a table", spawnRegionsFunc, i+1)
+		}
+		r := spawnRegionFromTable(t)
+		all = append(all, positioned{pos: f.Start, region: &r})
+	}
+	for _, cm := range chunk.CommentsIn(table.Start, table.End) {
+		if r, ok := parseDisabledRegion(cm.Text); ok {
+			all = append(all, positioned{pos: cm.Start, region: &r})
+			continue
+		}
+		all = append(all, positioned{pos: cm.Start, note: cm.Text})
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].pos < all[j].pos })
+
+	var regions []SpawnRegion
+	var notes []spawnNote
+	for _, p := range all {
+		if p.region != nil {
+			regions = append(regions, *p.region)
+			continue
+		}
+		notes = append(notes, spawnNote{text: p.note, before: len(regions)})
+	}
+	return regions, notes, nil
+}
+
+func spawnRegionFromTable(t *LuaTable) SpawnRegion {
+	return SpawnRegion{
+		Name:       scalarField(t, "name"),
+		File:       scalarField(t, "file"),
+		ServerFile: scalarField(t, "serverfile"),
+	}
+}
+
+// parseDisabledRegion 识别 `-- { name = ..., file = ... },` 形式的注释条目。
+func parseDisabledRegion(comment string) (SpawnRegion, bool) {
+	text := strings.TrimSpace(strings.TrimLeft(comment, "-"))
+	if strings.HasPrefix(text, "[") || !strings.HasPrefix(text, "{") {
+		return SpawnRegion{}, false
+	}
+	text = strings.TrimSuffix(strings.TrimSuffix(text, ";"), ",")
+	chunk, err := ParseLua([]byte("return " + text))
+	if err != nil || len(chunk.Statements) != 1 {
+		return SpawnRegion{}, false
+	}
+	t, ok := chunk.Statements[0].Value.(*LuaTable)
+	if !ok || t.Field("name") == nil {
+		return SpawnRegion{}, false
+	}
+	r := spawnRegionFromTable(t)
+	r.Disabled = true
+	return r, true
+}
+
+func formatSpawnRegion(r SpawnRegion) string {
+	parts := []string{"name = " + quoteLuaString(r.Name)}
+	if r.File != "" {
+		parts = append(parts, "file = "+quoteLuaString(r.File))
+	}
+	if r.ServerFile != "" {
+		parts = append(parts, "serverfile = "+quoteLuaString(r.ServerFile))
+	}
+	return "{ " + strings.Join(parts, ", ") + " }"
+}
+
+// SpawnPoints 解析 `function SpawnPoints() return { profession = { {...}, ... } } end`。
+func (s Service) SpawnPoints(chunk *LuaChunk) ([]ProfessionSpawns, error) {
+	table := chunk.FuncReturn(spawnPointsFunc)
+	if table == nil {
+		return nil, fmt.Errorf("%s() not found", spawnPointsFunc)
+	}
+	out := []ProfessionSpawns{}
+	for _, f := range table.Fields {
+		list, ok := f.Value.(*LuaTable)
+		if f.Key == "" || !ok {
+			return nil, fmt.Errorf("%s: expected profession = { ... }", spawnPointsFunc)
+		}
+		prof := ProfessionSpawns{Profession: f.Key, Points: []SpawnPoint{}}
+		for _, pf := range list.Fields {
+			pt, ok := pf.Value.(*LuaTable)
+			if !ok {
+				return nil, fmt.Errorf("%s.%s: spawn point is not a table", spawnPointsFunc, f.Key)
+			}
+			point, err := spawnPointFromTable(pt)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", spawnPointsFunc, f.Key, err)
+			}
+			prof.Points = append(prof.Points, point)
+		}
+		out = append(out, prof)
+	}
+	return out, nil
+}
+
+// UpdateSpawnPoints 重写返回的 table，保留函数外壳与缩进；
+// 职业之间的注释放在其后的职业之前，职业列表内的注释（如注释掉的出生点）留在该职业的列表中，
+// 位于原本其后的那个出生点之前。
+func (s Service) UpdateSpawnPoints(original []byte, professions []ProfessionSpawns) (string, error) {
+	chunk, err := ParseLua(original)
+	if err != nil {
+		return "", err
+	}
+	table := chunk.FuncReturn(spawnPointsFunc)
+	if table == nil {
+		return "", fmt.Errorf("%s() not found", spawnPointsFunc)
+	}
+
+	indent := lineIndent(chunk.Src, table.Start) + "\t"
+	pointIndent := indent + "\t"
+	if len(table.Fields) > 0 {
+		indent = lineIndent(chunk.Src, table.Fields[0].Start)
+		pointIndent = indent + "\t"
+		if list, ok := table.Fields[0].Value.(*LuaTable); ok && len(list.Fields) > 0 {
+			pointIndent = lineIndent(chunk.Src, list.Fields[0].Start)
+		}
+	}
+
+	notes := make(map[string][]string)
+	inner := make(map[string][]spawnNote)
+	var trailing []string
+	for _, cm := range chunk.CommentsIn(table.Start, table.End) {
+		var owner *LuaField
+		for _, f := range table.Fields {
+			if f.End > cm.Start {
+				owner = f
+				break
+			}
+		}
+		switch {
+		case owner == nil:
+			trailing = append(trailing, cm.Text)
+		case cm.Start < owner.Start:
+			notes[owner.Key] = append(notes[owner.Key], cm.Text)
+		default:
+			inner[owner.Key] = append(inner[owner.Key], spawnNote{before: pointsBefore(owner, cm.Start), text: cm.Text})
+		}
+	}
+
+	var lines []string
+	for i, prof := range professions {
+		for _, text := range notes[prof.Profession] {
+			lines = append(lines, indent+text)
+		}
+		delete(notes, prof.Profession)
+
+		lines = append(lines, indent+prof.Profession+" = {")
+		body := inner[prof.Profession]
+		delete(inner, prof.Profession)
+		for j, p := range prof.Points {
+			for len(body) > 0 && body[0].before <= j {
+				lines = append(lines, pointIndent+body[0].text)
+				body = body[1:]
+			}
+			lines = append(lines, pointIndent+formatSpawnPoint(p)+separator(j, len(prof.Points)))
+		}
+		for _, n := range body {
+			lines = append(lines, pointIndent+n.text)
+		}
+		lines = append(lines, indent+"}"+separator(i, len(professions)))
+	}
+	// 被删除的职业的注释保留在 table 末尾。
+	for _, f := range table.Fields {
+		for _, text := range notes[f.Key] {
+			lines = append(lines, indent+text)
+		}
+		for _, n := range inner[f.Key] {
+			lines = append(lines, indent+n.text)
+		}
+	}
+	for _, text := range trailing {
+		lines = append(lines, indent+text)
+	}
+
+	return replaceTableBody(chunk.Src, table, lines), nil
+}
+
+// pointsBefore 职业列表中在 pos 之前结束的出生点数量。
+func pointsBefore(f *LuaField, pos int) int {
+	list, ok := f.Value.(*LuaTable)
+	if !ok {
+		return 0
+	}
+	n := 0
+	for _, pf := range list.Fields {
+		if pf.End <= pos {
+			n++
+		}
+	}
+	return n
+}
+
+// GenerateSpawnPoints 文件不存在时生成默认的 SpawnPoints() 文件。
+func (s Service) GenerateSpawnPoints(professions []ProfessionSpawns) string {
+	out, _ := s.UpdateSpawnPoints([]byte("function "+spawnPointsFunc+"()\n\treturn {\n\t}\nend\n"), professions)
+	return out
+}
+
+// ValidateSpawnPoints 检查职业名与坐标；错误 key 形如 "unemployed.0.posX"。
+func ValidateSpawnPoints(professions []ProfessionSpawns) ValidationErrors {
+	var errs ValidationErrors
+	seen := make(map[string]bool)
+	for i, prof := range professions {
+		if !isLuaIdent(prof.Profession) {
+			errs = append(errs, FieldError{Key: fmt.Sprintf("%d.profession", i), Message: fmt.Sprintf("%q is not a valid profession id", prof.Profession)})
+			continue
+		}
+		if seen[prof.Profession] {
+			errs = append(errs, FieldError{Key: fmt.Sprintf("%d.profession", i), Message: fmt.Sprintf("duplicate profession %q", prof.Profession)})
+		}
+		seen[prof.Profession] = true
+		for j, p := range prof.Points {
+			for _, c := range []struct {
+				name string
+				v    int
+			}{{"worldX", p.WorldX}, {"worldY", p.WorldY}, {"posX", p.PosX}, {"posY", p.PosY}} {
+				if c.v < 0 {
+					errs = append(errs, FieldError{Key: fmt.Sprintf("%s.%d.%s", prof.Profession, j, c.name), Message: "must be >= 0"})
+				}
+			}
+			if p.PosZ < -32 || p.PosZ > 31 {
+				errs = append(errs, FieldError{Key: fmt.Sprintf("%s.%d.posZ", prof.Profession, j), Message: "must be between -32 and 31"})
+			}
+		}
+	}
+	return errs
+}
+
+func spawnPointFromTable(t *LuaTable) (SpawnPoint, error) {
+	var p SpawnPoint
+	for _, f := range []struct {
+		key string
+		dst *int
+	}{
+		{"worldX", &p.WorldX}, {"worldY", &p.WorldY},
+		{"posX", &p.PosX}, {"posY", &p.PosY}, {"posZ", &p.PosZ},
+	} {
+		raw := scalarField(t, f.key)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return p, fmt.Errorf("%s=%q is not a number", f.key, raw)
+		}
+		// 坐标按整数写回，带小数的值截断后保存会悄悄移动出生点。
+		if n != math.Trunc(n) || math.Abs(n) > math.MaxInt32 {
+			return p, fmt.Errorf("%s=%q is not an integer", f.key, raw)
+		}
+		*f.dst = int(n)
+	}
+	return p, nil
+}
+
+// formatSpawnPoint 只在非零时写出 posZ；旧格式 worldX/worldY 为零时省略（B42 绝对坐标）。
+func formatSpawnPoint(p SpawnPoint) string {
+	var parts []string
+	if p.WorldX != 0 || p.WorldY != 0 {
+		parts = append(parts, fmt.Sprintf("worldX = %d", p.WorldX), fmt.Sprintf("worldY = %d", p.WorldY))
+	}
+	parts = append(parts, fmt.Sprintf("posX = %d", p.PosX), fmt.Sprintf("posY = %d", p.PosY))
+	if p.PosZ != 0 {
+		parts = append(parts, fmt.Sprintf("posZ = %d", p.PosZ))
+	}
+	return "{ " + strings.Join(parts, ", ") + " }"
+}
+
+// replaceTableBody 用 lines 替换 table 花括号之间的内容，保留 "}" 所在行的缩进。
+func replaceTableBody(src string, table *LuaTable, lines []string) string {
+	body := "\n"
+	if len(lines) > 0 {
+		body += strings.Join(lines, "\n") + "\n"
+	}
+	body += lineIndent(src, table.End-1)
+
+	ed := NewLuaEditor(src)
+	ed.Replace(table.Start+1, table.End-1, body)
+	return ed.String()
+}
+
+func scalarField(t *LuaTable, key string) string {
+	f := t.Field(key)
+	if f == nil {
+		return ""
+	}
+	if v, ok := f.Value.(*LuaScalar); ok {
+		return v.Value
+	}
+	return ""
+}
+
+func separator(i, n int) string {
+	if i < n-1 {
+		return ","
+	}
+	return ""
+}
+
+func isLuaIdent(s string) bool {
+	if s == "" || !isLuaNameStart(s[0]) {
+		return false
+	}
+	for i := 1; i < len(s); i++ {
+		if !isLuaNameChar(s[i]) {
+			return false
+		}
+	}
+	return true
+}
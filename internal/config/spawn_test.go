@@ -0,0 +1,167 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readFullFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "..", "testdata", "mock_zomboid_full", "mock_zomboid", "Server", name))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	return data
+}
+
+func TestService_SpawnRegions_RoundTripFixture(t *testing.T) {
+	original := readFullFixture(t, "servertest_spawnregions.lua")
+	chunk, err := ParseLua(original)
+	if err != nil {
+		t.Fatalf("ParseLua: %v", err)
+	}
+	regions, err := Service{}.SpawnRegions(chunk)
+	if err != nil {
+		t.Fatalf("SpawnRegions: %v", err)
+	}
+	if len(regions) != 5 {
+		t.Fatalf("regions=%+v", regions)
+	}
+	last := regions[4]
+	if !last.Disabled || last.Name != "Twiggy's Bar" || last.ServerFile != "servertest_spawnpoints.lua" {
+		t.Fatalf("last=%+v", last)
+	}
+
+	out, err := Service{}.UpdateSpawnRegions(original, regions)
+	if err != nil {
+		t.Fatalf("UpdateSpawnRegions: %v", err)
+	}
+	if out != string(original) {
+		t.Fatalf("expected byte-for-byte round trip, got:\n%s", out)
+	}
+}
+
+func TestService_UpdateSpawnRegions_EnableAndRemove(t *testing.T) {
+	original := readFullFixture(t, "servertest_spawnregions.lua")
+	chunk, _ := ParseLua(original)
+	regions, _ := Service{}.SpawnRegions(chunk)
+
+	regions[4].Disabled = false
+	regions = append(regions[:1], regions[2:]...)
+
+	out, err := Service{}.UpdateSpawnRegions(original, regions)
+	if err != nil {
+		t.Fatalf("UpdateSpawnRegions: %v", err)
+	}
+	if !strings.HasPrefix(out, "function SpawnRegions()\n\treturn {\n") || !strings.HasSuffix(out, "\t}\nend\n") {
+		t.Fatalf("wrapper lost:\n%s", out)
+	}
+	if strings.Contains(out, "West Point") {
+		t.Fatalf("removed region still present:\n%s", out)
+	}
+	want := "\t\t-- Uncomment the line below to add a custom spawnpoint for this server.\n" +
+		"\t\t{ name = \"Twiggy's Bar\", serverfile = \"servertest_spawnpoints.lua\" },\n"
+	if !strings.Contains(out, want) {
+		t.Fatalf("enabled entry missing:\n%s", out)
+	}
+
+	reparsed, err := ParseLua([]byte(out))
+	if err != nil {
+		t.Fatalf("output does not parse: %v", err)
+	}
+	if got, _ := (Service{}).SpawnRegions(reparsed); len(got) != 4 || got[3].Disabled {
+		t.Fatalf("reparsed=%+v", got)
+	}
+}
+
+func TestService_SpawnPoints_RoundTripAndGenerate(t *testing.T) {
+	original := readFullFixture(t, "servertest_spawnpoints.lua")
+	chunk, err := ParseLua(original)
+	if err != nil {
+		t.Fatalf("ParseLua: %v", err)
+	}
+	profs, err := Service{}.SpawnPoints(chunk)
+	if err != nil {
+		t.Fatalf("SpawnPoints: %v", err)
+	}
+	if len(profs) != 1 || profs[0].Profession != "unemployed" || profs[0].Points[0] != (SpawnPoint{WorldX: 40, WorldY: 22, PosX: 67, PosY: 201}) {
+		t.Fatalf("profs=%+v", profs)
+	}
+
+	out, err := Service{}.UpdateSpawnPoints(original, profs)
+	if err != nil {
+		t.Fatalf("UpdateSpawnPoints: %v", err)
+	}
+	if out != string(original) {
+		t.Fatalf("expected byte-for-byte round trip, got:\n%s", out)
+	}
+
+	profs = append(profs, ProfessionSpawns{Profession: "fireofficer", Points: []SpawnPoint{{PosX: 10592, PosY: 9800, PosZ: 1}}})
+	generated := Service{}.GenerateSpawnPoints(profs)
+	reparsed, err := ParseLua([]byte(generated))
+	if err != nil {
+		t.Fatalf("generated does not parse: %v\n%s", err, generated)
+	}
+	got, _ := Service{}.SpawnPoints(reparsed)
+	if len(got) != 2 || got[1].Points[0].PosZ != 1 || got[1].Points[0].WorldX != 0 {
+		t.Fatalf("got=%+v\n%s", got, generated)
+	}
+}
+
+func TestService_UpdateSpawnPoints_KeepsCommentsInsideList(t *testing.T) {
+	original := []byte("function SpawnPoints()\n\treturn {\n\t\t-- default\n\t\tunemployed = {\n\t\t\t{ worldX = 40, worldY = 22, posX = 67, posY = 201 },\n\t\t\t-- { worldX = 1, worldY = 2, posX = 3, posY = 4 },\n\t\t\t{ worldX = 41, worldY = 22, posX = 1, posY = 2 }\n\t\t}\n\t}\nend\n")
+	profs := []ProfessionSpawns{{Profession: "unemployed", Points: []SpawnPoint{
+		{WorldX: 40, WorldY: 22, PosX: 67, PosY: 201},
+		{WorldX: 41, WorldY: 22, PosX: 1, PosY: 2},
+		{WorldX: 42, WorldY: 22, PosX: 5, PosY: 6},
+	}}}
+
+	out, err := Service{}.UpdateSpawnPoints(original, profs)
+	if err != nil {
+		t.Fatalf("UpdateSpawnPoints: %v", err)
+	}
+	want := "function SpawnPoints()\n\treturn {\n\t\t-- default\n\t\tunemployed = {\n" +
+		"\t\t\t{ worldX = 40, worldY = 22, posX = 67, posY = 201 },\n" +
+		"\t\t\t-- { worldX = 1, worldY = 2, posX = 3, posY = 4 },\n" +
+		"\t\t\t{ worldX = 41, worldY = 22, posX = 1, posY = 2 },\n" +
+		"\t\t\t{ worldX = 42, worldY = 22, posX = 5, posY = 6 }\n" +
+		"\t\t}\n\t}\nend\n"
+	if out != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestService_SpawnPoints_RejectsFractionalCoordinates(t *testing.T) {
+	for src, wantErr := range map[string]string{
+		"function SpawnPoints()\n\treturn {\n\t\tunemployed = { { posX = 10.0, posY = 1e3 } }\n\t}\nend\n": "",
+		"function SpawnPoints()\n\treturn {\n\t\tunemployed = { { posX = 10.5, posY = 20 } }\n\t}\nend\n":  `posX="10.5" is not an integer`,
+	} {
+		chunk, err := ParseLua([]byte(src))
+		if err != nil {
+			t.Fatalf("ParseLua: %v", err)
+		}
+		profs, err := Service{}.SpawnPoints(chunk)
+		if wantErr == "" {
+			if err != nil || profs[0].Points[0] != (SpawnPoint{PosX: 10, PosY: 1000}) {
+				t.Fatalf("profs=%+v err=%v", profs, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("err=%v", err)
+		}
+	}
+}
+
+func TestValidateSpawn(t *testing.T) {
+	errs := ValidateSpawnRegions([]SpawnRegion{{Name: "A"}, {Name: "B", ServerFile: "../x.lua"}})
+	if len(errs) != 2 || errs[0].Key != "0.file" || errs[1].Key != "1.serverfile" {
+		t.Fatalf("errs=%+v", errs)
+	}
+	errs = ValidateSpawnPoints([]ProfessionSpawns{{Profession: "bad name"}, {Profession: "a", Points: []SpawnPoint{{PosX: -1}}}})
+	if len(errs) != 2 || errs[0].Key != "0.profession" || errs[1].Key != "a.0.posX" {
+		t.Fatalf("errs=%+v", errs)
+	}
+}
@@ -0,0 +1,205 @@
+// Package cron 解析标准 5 段 cron 表达式（分 时 日 月 周）并计算下一次触发时间。
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalid 表达式无法解析。
+var ErrInvalid = errors.New("invalid cron expression")
+
+// Schedule 解析后的表达式，每个字段是允许取值的位图。
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	// domStar / dowStar 记录日、周字段是否以 * 开头（含 */2）：两者都受限时按 cron 惯例任一匹配即可。
+	domStar, dowStar bool
+}
+
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = field{name: "minute", min: 0, max: 59}
+	hourField   = field{name: "hour", min: 0, max: 23}
+	domField    = field{name: "day of month", min: 1, max: 31}
+	monthField  = field{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 周日可以写作 0 或 7。
+	dowField = field{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse 支持 *、列表（1,15）、范围（1-5）、步长（*/15、0-30/10）、月份与星期英文缩写，
+// 以及 @hourly、@daily 等宏。
+func Parse(expr string) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if m, ok := macros[strings.ToLower(expr)]; ok {
+		expr = m
+	}
+	parts := strings.Fields(expr)
+	if len(parts) != 5 {
+		return Schedule{}, fmt.Errorf("%w: %q: expected 5 fields, got %d", ErrInvalid, expr, len(parts))
+	}
+
+	var s Schedule
+	var err error
+	if s.minute, err = minuteField.parse(parts[0]); err != nil {
+		return Schedule{}, err
+	}
+	if s.hour, err = hourField.parse(parts[1]); err != nil {
+		return Schedule{}, err
+	}
+	if s.dom, err = domField.parse(parts[2]); err != nil {
+		return Schedule{}, err
+	}
+	if s.month, err = monthField.parse(parts[3]); err != nil {
+		return Schedule{}, err
+	}
+	if s.dow, err = dowField.parse(parts[4]); err != nil {
+		return Schedule{}, err
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	// 与 Vixie cron 一致，以 * 开头的字段（如 */2）不算受限，日与周仍需同时匹配。
+	s.domStar = strings.HasPrefix(parts[2], "*") || strings.HasPrefix(parts[2], "?")
+	s.dowStar = strings.HasPrefix(parts[4], "*") || strings.HasPrefix(parts[4], "?")
+	return s, nil
+}
+
+func (f field) parse(spec string) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(spec, ",") {
+		rng, stepText, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepText)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("%w: %s: bad step %q", ErrInvalid, f.name, item)
+			}
+			step = n
+		}
+
+		lo, hi := f.min, f.max
+		switch {
+		case rng == "*" || rng == "?":
+		case strings.Contains(rng, "-"):
+			a, b, _ := strings.Cut(rng, "-")
+			var err error
+			if lo, err = f.value(a); err != nil {
+				return 0, err
+			}
+			if hi, err = f.value(b); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("%w: %s: bad range %q", ErrInvalid, f.name, item)
+			}
+		default:
+			v, err := f.value(rng)
+			if err != nil {
+				return 0, err
+			}
+			lo = v
+			// “5/10” 表示从 5 开始每 10 个单位一次。
+			if !hasStep {
+				hi = v
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func (f field) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("%w: %s: %q out of range %d-%d", ErrInvalid, f.name, s, f.min, f.max)
+	}
+	return v, nil
+}
+
+// Next 返回严格晚于 t 的下一次触发时间，按 t 所在时区的墙上时间计算。
+// 夏令时跳过的时刻不会触发；五年内没有匹配时返回零值（例如 2 月 30 日）。
+func (s Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if !has(s.month, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !has(s.hour, t.Hour()) {
+			next := time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			// 夏令时回拨时 Date 可能返回不晚于 t 的时间。
+			if !next.After(t) {
+				next = t.Truncate(time.Hour).Add(time.Hour)
+			}
+			t = next
+			continue
+		}
+		if !has(s.minute, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// NextN 返回之后的 n 次触发时间。
+func (s Schedule) NextN(t time.Time, n int) []time.Time {
+	out := make([]time.Time, 0, n)
+	for len(out) < n {
+		t = s.Next(t)
+		if t.IsZero() {
+			break
+		}
+		out = append(out, t)
+	}
+	return out
+}
+
+func (s Schedule) dayMatches(t time.Time) bool {
+	dom := has(s.dom, t.Day())
+	dow := has(s.dow, int(t.Weekday()))
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+func has(bits uint64, v int) bool {
+	return bits&(1<<uint(v)) != 0
+}
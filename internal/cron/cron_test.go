@@ -0,0 +1,71 @@
+package cron
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSchedule_Next(t *testing.T) {
+	base := time.Date(2024, 3, 15, 10, 17, 30, 0, time.UTC) // 周五
+	cases := []struct {
+		expr string
+		want string
+	}{
+		{"*/15 * * * *", "2024-03-15T10:30:00Z"},
+		{"0 */6 * * *", "2024-03-15T12:00:00Z"},
+		{"@hourly", "2024-03-15T11:00:00Z"},
+		{"@daily", "2024-03-16T00:00:00Z"},
+		{"30 4 * * mon-wed", "2024-03-18T04:30:00Z"},
+		{"0 0 1 * *", "2024-04-01T00:00:00Z"},
+		{"0 12 * feb,mar sun", "2024-03-17T12:00:00Z"},
+		{"0 0 * * 7", "2024-03-17T00:00:00Z"},
+		// 日与周都受限时任一匹配即可：20 号或下一个周一。
+		{"0 0 20 * 1", "2024-03-18T00:00:00Z"},
+		// 以 * 开头的步长字段不算受限：单数日且是周一；1 号且是周日 / 二 / 四 / 六。
+		{"0 4 */2 * 1", "2024-03-25T04:00:00Z"},
+		{"0 4 1 * */2", "2024-06-01T04:00:00Z"},
+		{"5/20 10 * * *", "2024-03-15T10:25:00Z"},
+		{"0 0 29 2 *", "2028-02-29T00:00:00Z"},
+	}
+	for _, c := range cases {
+		s, err := Parse(c.expr)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", c.expr, err)
+		}
+		if got := s.Next(base).Format(time.RFC3339); got != c.want {
+			t.Fatalf("Next(%q)=%s want %s", c.expr, got, c.want)
+		}
+	}
+}
+
+func TestSchedule_NextInTimezone(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	s, _ := Parse("30 2 * * *")
+	// 2024-03-10 夏令时开始，02:30 不存在，跳到第二天。
+	got := s.NextN(time.Date(2024, 3, 9, 3, 0, 0, 0, loc), 2)
+	if len(got) != 2 || got[0].Format(time.RFC3339) != "2024-03-11T02:30:00-04:00" || got[1].Day() != 12 {
+		t.Fatalf("got=%v", got)
+	}
+
+	s, _ = Parse("0 4 * * *")
+	got = s.NextN(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).In(loc), 1)
+	if got[0].UTC().Format(time.RFC3339) != "2024-01-01T09:00:00Z" {
+		t.Fatalf("got=%v", got)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "*/0 * * * *", "5-1 * * * *", "* * * foo *"} {
+		if _, err := Parse(expr); !errors.Is(err, ErrInvalid) {
+			t.Fatalf("Parse(%q) err=%v", expr, err)
+		}
+	}
+	s, _ := Parse("0 0 30 2 *")
+	if !s.Next(time.Now()).IsZero() {
+		t.Fatalf("Feb 30 should never fire")
+	}
+}
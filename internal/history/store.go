@@ -0,0 +1,190 @@
+// Package history 保存配置文件的历史快照，用于查看、比较与回滚。
+package history
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotFound 快照不存在。
+var ErrNotFound = errors.New("snapshot not found")
+
+// DefaultKeep 每类配置默认保留的快照数量。
+const DefaultKeep = 100
+
+// Snapshot 一次保存后的文件快照元信息。
+type Snapshot struct {
+	ID        string    `json:"id"`
+	Kind      string    `json:"kind"`
+	Author    string    `json:"author"`
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"created_at"`
+	Size      int       `json:"size"`
+	Hash      string    `json:"hash"`
+}
+
+type Store interface {
+	// Add 保存快照；ID / CreatedAt / Size / Hash 由 Store 填充。
+	Add(snap Snapshot, content []byte) (Snapshot, error)
+	// List 按时间倒序返回 kind 的快照；kind 为空返回全部。
+	List(kind string) ([]Snapshot, error)
+	Get(id string) (Snapshot, []byte, error)
+}
+
+// FileStore 以目录保存快照：<id>.json 为元信息，<id>.snap 为文件内容。
+// 快照中含有明文密码，目录与文件只允许所有者访问。
+type FileStore struct {
+	Dir string
+	// Keep 每类配置保留的快照数量，<= 0 时使用 DefaultKeep。
+	Keep int
+	// Now 允许在测试中固定时间。
+	Now func() time.Time
+
+	mu sync.Mutex
+}
+
+func (s *FileStore) Add(snap Snapshot, content []byte) (Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.Dir, 0o700); err != nil {
+		return Snapshot{}, err
+	}
+	// 旧版本以 0755 创建过目录时收紧权限。
+	if err := os.Chmod(s.Dir, 0o700); err != nil {
+		return Snapshot{}, err
+	}
+
+	snap.CreatedAt = s.now().UTC()
+	snap.Size = len(content)
+	sum := sha256.Sum256(content)
+	snap.Hash = hex.EncodeToString(sum[:])
+	snap.ID = s.newID(snap.CreatedAt, snap.Kind)
+
+	if err := os.WriteFile(filepath.Join(s.Dir, snap.ID+".snap"), content, 0o600); err != nil {
+		return Snapshot{}, err
+	}
+	meta, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return Snapshot{}, err
+	}
+	if err := os.WriteFile(filepath.Join(s.Dir, snap.ID+".json"), meta, 0o600); err != nil {
+		return Snapshot{}, err
+	}
+	if err := s.prune(snap.Kind); err != nil {
+		return Snapshot{}, fmt.Errorf("prune history: %w", err)
+	}
+	return snap, nil
+}
+
+// prune 删除 kind 超出保留数量的最旧快照。
+func (s *FileStore) prune(kind string) error {
+	keep := s.Keep
+	if keep <= 0 {
+		keep = DefaultKeep
+	}
+	all, err := s.List(kind)
+	if err != nil {
+		return err
+	}
+	for i := keep; i < len(all); i++ {
+		// 先删元信息，中途失败时残留的 .snap 不会出现在列表中。
+		for _, ext := range []string{".json", ".snap"} {
+			if err := os.Remove(filepath.Join(s.Dir, all[i].ID+ext)); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *FileStore) List(kind string) ([]Snapshot, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Snapshot
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		snap, err := s.readMeta(strings.TrimSuffix(e.Name(), ".json"))
+		if err != nil {
+			continue
+		}
+		if kind != "" && snap.Kind != kind {
+			continue
+		}
+		out = append(out, snap)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].ID > out[j].ID })
+	return out, nil
+}
+
+func (s *FileStore) Get(id string) (Snapshot, []byte, error) {
+	if !validID(id) {
+		return Snapshot{}, nil, ErrNotFound
+	}
+	snap, err := s.readMeta(id)
+	if err != nil {
+		return Snapshot{}, nil, err
+	}
+	content, err := os.ReadFile(filepath.Join(s.Dir, id+".snap"))
+	if err != nil {
+		return Snapshot{}, nil, err
+	}
+	return snap, content, nil
+}
+
+func (s *FileStore) readMeta(id string) (Snapshot, error) {
+	data, err := os.ReadFile(filepath.Join(s.Dir, id+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return Snapshot{}, ErrNotFound
+	}
+	if err != nil {
+		return Snapshot{}, err
+	}
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return Snapshot{}, fmt.Errorf("decode snapshot %s: %w", id, err)
+	}
+	return snap, nil
+}
+
+// newID 生成按时间排序的 ID；同一时刻的重复 ID 追加序号。
+func (s *FileStore) newID(t time.Time, kind string) string {
+	base := t.Format("20060102T150405.000000000Z") + "-" + kind
+	id := base
+	for i := 1; ; i++ {
+		if _, err := os.Stat(filepath.Join(s.Dir, id+".json")); errors.Is(err, os.ErrNotExist) {
+			return id
+		}
+		id = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+func (s *FileStore) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
+
+// validID 拒绝包含路径分隔符的 ID，避免读取快照目录之外的文件。
+func validID(id string) bool {
+	return id != "" && !strings.ContainsAny(id, `/\`) && !strings.Contains(id, "..")
+}
@@ -0,0 +1,114 @@
+package history
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileStore_AddListGet(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	store := &FileStore{Dir: t.TempDir(), Now: func() time.Time { return now }}
+
+	first, err := store.Add(Snapshot{Kind: "server", Author: "alice", Note: "pvp on"}, []byte("PVP=true\n"))
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	second, err := store.Add(Snapshot{Kind: "server", Author: "bob"}, []byte("PVP=false\n"))
+	if err != nil {
+		t.Fatalf("Add2: %v", err)
+	}
+	if first.ID == second.ID {
+		t.Fatalf("duplicate id %q", first.ID)
+	}
+	if _, err := store.Add(Snapshot{Kind: "sandbox"}, []byte("SandboxVars = {}\n")); err != nil {
+		t.Fatalf("Add3: %v", err)
+	}
+
+	list, err := store.List("server")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(list) != 2 || list[0].ID != second.ID {
+		t.Fatalf("list=%+v", list)
+	}
+
+	snap, content, err := store.Get(first.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if snap.Author != "alice" || snap.Note != "pvp on" || string(content) != "PVP=true\n" {
+		t.Fatalf("snap=%+v content=%q", snap, content)
+	}
+}
+
+func TestFileStore_GetRejectsTraversal(t *testing.T) {
+	store := &FileStore{Dir: t.TempDir()}
+	if _, _, err := store.Get("../secret"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err=%v", err)
+	}
+}
+
+func TestFileStore_AddPrunesOldestPerKind(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	store := &FileStore{Dir: dir, Keep: 2, Now: func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}}
+
+	var server []Snapshot
+	for i := 0; i < 3; i++ {
+		snap, err := store.Add(Snapshot{Kind: "server"}, []byte{byte('a' + i)})
+		if err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+		server = append(server, snap)
+	}
+	sandbox, err := store.Add(Snapshot{Kind: "sandbox"}, []byte("x"))
+	if err != nil {
+		t.Fatalf("Add sandbox: %v", err)
+	}
+
+	list, err := store.List("server")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(list) != 2 || list[0].ID != server[2].ID || list[1].ID != server[1].ID {
+		t.Fatalf("list=%+v", list)
+	}
+	if _, _, err := store.Get(server[0].ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("oldest err=%v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, server[0].ID+".snap")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("oldest content stat err=%v", err)
+	}
+	if _, _, err := store.Get(sandbox.ID); err != nil {
+		t.Fatalf("other kind pruned: %v", err)
+	}
+}
+
+func TestFileStore_AddRestrictsPermissions(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "history")
+	store := &FileStore{Dir: dir}
+	snap, err := store.Add(Snapshot{Kind: "server"}, []byte("Password=hunter2\n"))
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	for path, want := range map[string]os.FileMode{
+		dir:                                 0o700,
+		filepath.Join(dir, snap.ID+".snap"): 0o600,
+		filepath.Join(dir, snap.ID+".json"): 0o600,
+	} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("stat: %v", err)
+		}
+		if info.Mode().Perm() != want {
+			t.Fatalf("%s mode=%v want %v", filepath.Base(path), info.Mode().Perm(), want)
+		}
+	}
+}
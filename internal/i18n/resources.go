@@ -57,9 +57,11 @@ var WebUIResources = map[string]map[string]string{
 		"msg_parse_fail":          "解析失败",
 		"msg_mod_list_updated":    "模组列表已更新，请记得点击保存按钮",
 		"msg_save_fail":           "保存失败",
+		"msg_save_conflict":       "配置文件已被他人修改。是否放弃本地修改并重新加载？",
 		"msg_save_success":        "保存成功",
 		"msg_cmd_sent":            "命令已发送",
 		"msg_exec_fail":           "执行失败",
+		"msg_server_update_done":  "服务器更新完成",
 		"prompt_mod_id_manual":    "已找到: {0}\n但无法自动识别 Mod ID。\n请输入 Mod ID:",
 		"confirm_save_restart":    "确定要保存并重启服务器吗？",
 		"mod_section_title":       "模组管理 (Mods)",
@@ -135,9 +137,11 @@ var WebUIResources = map[string]map[string]string{
 		"msg_parse_fail":          "Parsing failed",
 		"msg_mod_list_updated":    "Mod list updated, remember to click save",
 		"msg_save_fail":           "Save failed",
+		"msg_save_conflict":       "The file was changed by someone else. Discard your edits and reload?",
 		"msg_save_success":        "Save successful",
 		"msg_cmd_sent":            "Command sent",
 		"msg_exec_fail":           "Execution failed",
+		"msg_server_update_done":  "Server update finished",
 		"prompt_mod_id_manual":    "Found: {0}\nBut cannot automatically identify Mod ID.\nPlease enter Mod ID:",
 		"confirm_save_restart":    "Are you sure you want to save and restart the server?",
 		"mod_section_title":       "Mods Management",
@@ -213,9 +217,11 @@ var WebUIResources = map[string]map[string]string{
 		"msg_parse_fail":          "解析失敗",
 		"msg_mod_list_updated":    "模組清單已更新，請記得點選儲存按鈕",
 		"msg_save_fail":           "儲存失敗",
+		"msg_save_conflict":       "設定檔已被他人修改。是否放棄本地修改並重新載入？",
 		"msg_save_success":        "儲存成功",
 		"msg_cmd_sent":            "指令已傳送",
 		"msg_exec_fail":           "執行失敗",
+		"msg_server_update_done":  "伺服器更新完成",
 		"prompt_mod_id_manual":    "已找到: {0}\n但無法自動識別 Mod ID。\n請輸入 Mod ID:",
 		"confirm_save_restart":    "確定要儲存並重新啟動伺服器嗎？",
 		"mod_section_title":       "模組管理 (Mods)",
@@ -291,9 +297,11 @@ var WebUIResources = map[string]map[string]string{
 		"msg_parse_fail":          "解析失敗",
 		"msg_mod_list_updated":    "MODリストを更新しました、保存ボタンをクリックしてください",
 		"msg_save_fail":           "保存失敗",
+		"msg_save_conflict":       "ファイルは他のユーザーによって変更されました。編集を破棄して再読み込みしますか？",
 		"msg_save_success":        "保存成功",
 		"msg_cmd_sent":            "コマンドを送信しました",
 		"msg_exec_fail":           "実行失敗",
+		"msg_server_update_done":  "サーバーの更新が完了しました",
 		"prompt_mod_id_manual":    "見つかりました: {0}\nただしMOD IDを自動識別できません。\nMOD IDを入力してください:",
 		"confirm_save_restart":    "サーバーを保存して再起動しますか？",
 		"mod_section_title":       "MOD管理 (Mods)",
@@ -369,9 +377,11 @@ var WebUIResources = map[string]map[string]string{
 		"msg_parse_fail":          "구문 분석 실패",
 		"msg_mod_list_updated":    "모드 목록이 업데이트되었습니다, 저장 버튼을 클릭하세요",
 		"msg_save_fail":           "저장 실패",
+		"msg_save_conflict":       "다른 사용자가 파일을 변경했습니다. 편집 내용을 버리고 다시 불러오시겠습니까?",
 		"msg_save_success":        "저장 성공",
 		"msg_cmd_sent":            "명령어 전송됨",
 		"msg_exec_fail":           "실행 실패",
+		"msg_server_update_done":  "서버 업데이트 완료",
 		"prompt_mod_id_manual":    "발견됨: {0}\n하지만 모드 ID를 자동으로 식별할 수 없습니다.\n모드 ID를 입력하세요:",
 		"confirm_save_restart":    "서버를 저장하고 재시작하시겠습니까?",
 		"mod_section_title":       "모드 관리 (Mods)",
@@ -447,9 +457,11 @@ var WebUIResources = map[string]map[string]string{
 		"msg_parse_fail":          "Ошибка разбора",
 		"msg_mod_list_updated":    "Список модов обновлен, не забудьте нажать сохранить",
 		"msg_save_fail":           "Сохранение не удалось",
+		"msg_save_conflict":       "Файл был изменён другим пользователем. Отменить ваши правки и перезагрузить?",
 		"msg_save_success":        "Успешно сохранено",
 		"msg_cmd_sent":            "Команда отправлена",
 		"msg_exec_fail":           "Ошибка выполнения",
+		"msg_server_update_done":  "Обновление сервера завершено",
 		"prompt_mod_id_manual":    "Найдено: {0}\nНо не удалось автоматически определить ID мода.\nВведите ID мода:",
 		"confirm_save_restart":    "Вы уверены, что хотите сохранить и перезапустить сервер?",
 		"mod_section_title":       "Управление модами (Mods)",
@@ -525,9 +537,11 @@ var WebUIResources = map[string]map[string]string{
 		"msg_parse_fail":          "Parsen fehlgeschlagen",
 		"msg_mod_list_updated":    "Mod-Liste aktualisiert, bitte Speichern nicht vergessen",
 		"msg_save_fail":           "Speichern fehlgeschlagen",
+		"msg_save_conflict":       "Die Datei wurde von jemand anderem geändert. Änderungen verwerfen und neu laden?",
 		"msg_save_success":        "Erfolgreich gespeichert",
 		"msg_cmd_sent":            "Befehl gesendet",
 		"msg_exec_fail":           "Ausführung fehlgeschlagen",
+		"msg_server_update_done":  "Server-Update abgeschlossen",
 		"prompt_mod_id_manual":    "Gefunden: {0}\nAber Mod-ID kann nicht automatisch erkannt werden.\nBitte Mod-ID eingeben:",
 		"confirm_save_restart":    "Sind Sie sicher, dass Sie den Server speichern und neu starten möchten?",
 		"mod_section_title":       "Mod-Verwaltung (Mods)",
@@ -603,9 +617,11 @@ var WebUIResources = map[string]map[string]string{
 		"msg_parse_fail":          "Échec de l'analyse",
 		"msg_mod_list_updated":    "Liste de mods mise à jour, n'oubliez pas de sauvegarder",
 		"msg_save_fail":           "Échec de la sauvegarde",
+		"msg_save_conflict":       "Le fichier a été modifié par quelqu'un d'autre. Abandonner vos modifications et recharger ?",
 		"msg_save_success":        "Sauvegarde réussie",
 		"msg_cmd_sent":            "Commande envoyée",
 		"msg_exec_fail":           "Échec de l'exécution",
+		"msg_server_update_done":  "Mise à jour du serveur terminée",
 		"prompt_mod_id_manual":    "Trouvé: {0}\nMais impossible d'identifier automatiquement l'ID du mod.\nVeuillez entrer l'ID du mod:",
 		"confirm_save_restart":    "Êtes-vous sûr de vouloir sauvegarder et redémarrer le serveur?",
 		"mod_section_title":       "Gestion des mods (Mods)",
@@ -681,9 +697,11 @@ var WebUIResources = map[string]map[string]string{
 		"msg_parse_fail":          "Error de análisis",
 		"msg_mod_list_updated":    "Lista de mods actualizada, recuerde guardar",
 		"msg_save_fail":           "Error al guardar",
+		"msg_save_conflict":       "Otra persona modificó el archivo. ¿Descartar tus cambios y recargar?",
 		"msg_save_success":        "Guardado exitoso",
 		"msg_cmd_sent":            "Comando enviado",
 		"msg_exec_fail":           "Error de ejecución",
+		"msg_server_update_done":  "Actualización del servidor completada",
 		"prompt_mod_id_manual":    "Encontrado: {0}\nPero no se puede identificar automáticamente el ID del mod.\nIngrese el ID del mod:",
 		"confirm_save_restart":    "¿Está seguro de que desea guardar y reiniciar el servidor?",
 		"mod_section_title":       "Gestión de mods (Mods)",
@@ -759,9 +777,11 @@ var WebUIResources = map[string]map[string]string{
 		"msg_parse_fail":          "Falha na análise",
 		"msg_mod_list_updated":    "Lista de mods atualizada, lembre-se de salvar",
 		"msg_save_fail":           "Falha ao salvar",
+		"msg_save_conflict":       "O arquivo foi alterado por outra pessoa. Descartar suas edições e recarregar?",
 		"msg_save_success":        "Salvo com sucesso",
 		"msg_cmd_sent":            "Comando enviado",
 		"msg_exec_fail":           "Falha na execução",
+		"msg_server_update_done":  "Atualização do servidor concluída",
 		"prompt_mod_id_manual":    "Encontrado: {0}\nMas não é possível identificar automaticamente o ID do mod.\nDigite o ID do mod:",
 		"confirm_save_restart":    "Tem certeza que deseja salvar e reiniciar o servidor?",
 		"mod_section_title":       "Gerenciamento de Mods (Mods)",
@@ -837,9 +857,11 @@ var WebUIResources = map[string]map[string]string{
 		"msg_parse_fail":          "Błąd analizy",
 		"msg_mod_list_updated":    "Lista modów zaktualizowana, pamiętaj o zapisaniu",
 		"msg_save_fail":           "Błąd zapisu",
+		"msg_save_conflict":       "Plik został zmieniony przez kogoś innego. Odrzucić zmiany i przeładować?",
 		"msg_save_success":        "Zapisano pomyślnie",
 		"msg_cmd_sent":            "Polecenie wysłane",
 		"msg_exec_fail":           "Błąd wykonania",
+		"msg_server_update_done":  "Aktualizacja serwera zakończona",
 		"prompt_mod_id_manual":    "Znaleziono: {0}\nAle nie można automatycznie zidentyfikować ID moda.\nWprowadź ID moda:",
 		"confirm_save_restart":    "Czy na pewno chcesz zapisać i ponownie uruchomić serwer?",
 		"mod_section_title":       "Zarządzanie modami (Mods)",
@@ -915,9 +937,11 @@ var WebUIResources = map[string]map[string]string{
 		"msg_parse_fail":          "Ayrıştırma başarısız",
 		"msg_mod_list_updated":    "Mod listesi güncellendi, kaydetmeyi unutmayın",
 		"msg_save_fail":           "Kaydetme başarısız",
+		"msg_save_conflict":       "Dosya başka biri tarafından değiştirildi. Düzenlemeleriniz atılıp yeniden yüklensin mi?",
 		"msg_save_success":        "Başarıyla kaydedildi",
 		"msg_cmd_sent":            "Komut gönderildi",
 		"msg_exec_fail":           "Yürütme başarısız",
+		"msg_server_update_done":  "Sunucu güncellemesi tamamlandı",
 		"prompt_mod_id_manual":    "Bulundu: {0}\nAncak Mod ID otomatik olarak tanımlanamıyor.\nLütfen Mod ID girin:",
 		"confirm_save_restart":    "Sunucuyu kaydedip yeniden başlatmak istediğinizden emin misiniz?",
 		"mod_section_title":       "Mod Yönetimi (Mods)",
@@ -993,9 +1017,11 @@ var WebUIResources = map[string]map[string]string{
 		"msg_parse_fail":          "Помилка аналізу",
 		"msg_mod_list_updated":    "Список модів оновлено, не забудьте натиснути зберегти",
 		"msg_save_fail":           "Помилка збереження",
+		"msg_save_conflict":       "Файл змінено іншим користувачем. Скасувати ваші зміни та перезавантажити?",
 		"msg_save_success":        "Успішно збережено",
 		"msg_cmd_sent":            "Команду надіслано",
 		"msg_exec_fail":           "Помилка виконання",
+		"msg_server_update_done":  "Оновлення сервера завершено",
 		"prompt_mod_id_manual":    "Знайдено: {0}\nАле не вдалося автоматично визначити ID мода.\nБудь ласка, введіть ID мода:",
 		"confirm_save_restart":    "Ви впевнені, що хочете зберегти та перезапустити сервер?",
 		"mod_section_title":       "Управління модами (Mods)",
@@ -1071,9 +1097,11 @@ var WebUIResources = map[string]map[string]string{
 		"msg_parse_fail":          "فشل التحليل",
 		"msg_mod_list_updated":    "تم تحديث قائمة الإضافات، تذكر النقر على حفظ",
 		"msg_save_fail":           "فشل الحفظ",
+		"msg_save_conflict":       "قام شخص آخر بتعديل الملف. هل تريد تجاهل تعديلاتك وإعادة التحميل؟",
 		"msg_save_success":        "تم الحفظ بنجاح",
 		"msg_cmd_sent":            "تم إرسال الأمر",
 		"msg_exec_fail":           "فشل التنفيذ",
+		"msg_server_update_done":  "اكتمل تحديث الخادم",
 		"prompt_mod_id_manual":    "تم العثور على: {0}\nلكن لا يمكن التعرف تلقائياً على معرف الإضافة.\nالرجاء إدخال معرف الإضافة:",
 		"confirm_save_restart":    "هل أنت متأكد أنك تريد حفظ وإعادة تشغيل الخادم؟",
 		"mod_section_title":       "إدارة الإضافات (Mods)",
@@ -1149,9 +1177,11 @@ var WebUIResources = map[string]map[string]string{
 		"msg_parse_fail":          "Error d'anàlisi",
 		"msg_mod_list_updated":    "Llista de mods actualitzada, recordeu desar",
 		"msg_save_fail":           "Error en desar",
+		"msg_save_conflict":       "Algú altre ha modificat el fitxer. Vols descartar els canvis i tornar a carregar?",
 		"msg_save_success":        "Desat amb èxit",
 		"msg_cmd_sent":            "Comanda enviada",
 		"msg_exec_fail":           "Error d'execució",
+		"msg_server_update_done":  "Actualització del servidor completada",
 		"prompt_mod_id_manual":    "Trobat: {0}\nPerò no es pot identificar automàticament l'ID del mod.\nIntroduïu l'ID del mod:",
 		"confirm_save_restart":    "Esteu segur que voleu desar i reiniciar el servidor?",
 		"mod_section_title":       "Gestió de mods (Mods)",
@@ -1227,9 +1257,11 @@ var WebUIResources = map[string]map[string]string{
 		"msg_parse_fail":          "Chyba analýzy",
 		"msg_mod_list_updated":    "Seznam modů aktualizován, nezapomeňte uložit",
 		"msg_save_fail":           "Uložení selhalo",
+		"msg_save_conflict":       "Soubor byl změněn někým jiným. Zahodit vaše úpravy a znovu načíst?",
 		"msg_save_success":        "Úspěšně uloženo",
 		"msg_cmd_sent":            "Příkaz odeslán",
 		"msg_exec_fail":           "Chyba provedení",
+		"msg_server_update_done":  "Aktualizace serveru dokončena",
 		"prompt_mod_id_manual":    "Nalezeno: {0}\nAle nelze automaticky identifikovat ID modu.\nZadejte ID modu:",
 		"confirm_save_restart":    "Opravdu chcete uložit a restartovat server?",
 		"mod_section_title":       "Správa modů (Mods)",
@@ -1305,9 +1337,11 @@ var WebUIResources = map[string]map[string]string{
 		"msg_parse_fail":          "Analysen mislykkedes",
 		"msg_mod_list_updated":    "Mod-liste opdateret, husk at gemme",
 		"msg_save_fail":           "Gemmen mislykkedes",
+		"msg_save_conflict":       "Filen er blevet ændret af en anden. Kassér dine ændringer og genindlæs?",
 		"msg_save_success":        "Gemt succesfuldt",
 		"msg_cmd_sent":            "Kommando sendt",
 		"msg_exec_fail":           "Udførelse mislykkedes",
+		"msg_server_update_done":  "Serveropdatering fuldført",
 		"prompt_mod_id_manual":    "Fundet: {0}\nMen kan ikke automatisk identificere Mod ID.\nIndtast Mod ID:",
 		"confirm_save_restart":    "Er du sikker på at du vil gemme og genstarte serveren?",
 		"mod_section_title":       "Mod Administration (Mods)",
@@ -1383,9 +1417,11 @@ var WebUIResources = map[string]map[string]string{
 		"msg_parse_fail":          "Jäsennys epäonnistui",
 		"msg_mod_list_updated":    "Modi-lista päivitetty, muista tallentaa",
 		"msg_save_fail":           "Tallennus epäonnistui",
+		"msg_save_conflict":       "Joku muu on muuttanut tiedostoa. Hylätäänkö muutoksesi ja ladataanko uudelleen?",
 		"msg_save_success":        "Tallennettu onnistuneesti",
 		"msg_cmd_sent":            "Komento lähetetty",
 		"msg_exec_fail":           "Suoritus epäonnistui",
+		"msg_server_update_done":  "Palvelimen päivitys valmis",
 		"prompt_mod_id_manual":    "Löytyi: {0}\nMutta modin ID:tä ei voi tunnistaa automaattisesti.\nAnna modin ID:",
 		"confirm_save_restart":    "Haluatko varmasti tallentaa ja käynnistää palvelimen uudelleen?",
 		"mod_section_title":       "Modien hallinta (Mods)",
@@ -1461,9 +1497,11 @@ var WebUIResources = map[string]map[string]string{
 		"msg_parse_fail":          "Elemzés sikertelen",
 		"msg_mod_list_updated":    "Mod lista frissítve, ne felejtse el menteni",
 		"msg_save_fail":           "Mentés sikertelen",
+		"msg_save_conflict":       "A fájlt valaki más módosította. Elveti a módosításait és újratölti?",
 		"msg_save_success":        "Sikeres mentés",
 		"msg_cmd_sent":            "Parancs elküldve",
 		"msg_exec_fail":           "Végrehajtás sikertelen",
+		"msg_server_update_done":  "A szerver frissítése befejeződött",
 		"prompt_mod_id_manual":    "Találat: {0}\nDe a Mod ID nem azonosítható automatikusan.\nKérjük adja meg a Mod ID-t:",
 		"confirm_save_restart":    "Biztos benne, hogy menteni és újraindítani szeretné a szervert?",
 		"mod_section_title":       "Mod kezelés (Mods)",
@@ -1539,9 +1577,11 @@ var WebUIResources = map[string]map[string]string{
 		"msg_parse_fail":          "Parsing gagal",
 		"msg_mod_list_updated":    "Daftar mod diperbarui, ingat untuk menyimpan",
 		"msg_save_fail":           "Gagal menyimpan",
+		"msg_save_conflict":       "Berkas telah diubah oleh orang lain. Buang perubahan Anda dan muat ulang?",
 		"msg_save_success":        "Berhasil disimpan",
 		"msg_cmd_sent":            "Perintah dikirim",
 		"msg_exec_fail":           "Eksekusi gagal",
+		"msg_server_update_done":  "Pembaruan server selesai",
 		"prompt_mod_id_manual":    "Ditemukan: {0}\nTetapi tidak dapat mengidentifikasi ID Mod secara otomatis.\nMasukkan ID Mod:",
 		"confirm_save_restart":    "Yakin ingin menyimpan dan restart server?",
 		"mod_section_title":       "Manajemen Mod (Mods)",
@@ -1617,9 +1657,11 @@ var WebUIResources = map[string]map[string]string{
 		"msg_parse_fail":          "Analisi fallita",
 		"msg_mod_list_updated":    "Lista mod aggiornata, ricorda di salvare",
 		"msg_save_fail":           "Salvataggio fallito",
+		"msg_save_conflict":       "Il file è stato modificato da qualcun altro. Scartare le modifiche e ricaricare?",
 		"msg_save_success":        "Salvato con successo",
 		"msg_cmd_sent":            "Comando inviato",
 		"msg_exec_fail":           "Esecuzione fallita",
+		"msg_server_update_done":  "Aggiornamento del server completato",
 		"prompt_mod_id_manual":    "Trovato: {0}\nMa non posso identificare automaticamente l'ID del mod.\nInserisci l'ID del mod:",
 		"confirm_save_restart":    "Sei sicuro di voler salvare e riavviare il server?",
 		"mod_section_title":       "Gestione Mod (Mods)",
@@ -1695,9 +1737,11 @@ var WebUIResources = map[string]map[string]string{
 		"msg_parse_fail":          "Parsen mislukt",
 		"msg_mod_list_updated":    "Mod-lijst bijgewerkt, vergeet niet op te slaan",
 		"msg_save_fail":           "Opslaan mislukt",
+		"msg_save_conflict":       "Het bestand is door iemand anders gewijzigd. Je wijzigingen verwerpen en opnieuw laden?",
 		"msg_save_success":        "Succesvol opgeslagen",
 		"msg_cmd_sent":            "Opdracht verzonden",
 		"msg_exec_fail":           "Uitvoering mislukt",
+		"msg_server_update_done":  "Serverupdate voltooid",
 		"prompt_mod_id_manual":    "Gevonden: {0}\nMaar kan Mod ID niet automatisch identificeren.\nVoer Mod ID in:",
 		"confirm_save_restart":    "Weet u zeker dat u de server wilt opslaan en opnieuw starten?",
 		"mod_section_title":       "Mod Beheer (Mods)",
@@ -1773,9 +1817,11 @@ var WebUIResources = map[string]map[string]string{
 		"msg_parse_fail":          "Analysering mislyktes",
 		"msg_mod_list_updated":    "Mod-liste oppdatert, husk å lagre",
 		"msg_save_fail":           "Lagring mislyktes",
+		"msg_save_conflict":       "Filen er endret av noen andre. Forkast endringene dine og last inn på nytt?",
 		"msg_save_success":        "Lagret vellykket",
 		"msg_cmd_sent":            "Kommando sendt",
 		"msg_exec_fail":           "Utførelse mislyktes",
+		"msg_server_update_done":  "Serveroppdatering fullført",
 		"prompt_mod_id_manual":    "Funnet: {0}\nMen kan ikke automatisk identifisere Mod ID.\nSkriv inn Mod ID:",
 		"confirm_save_restart":    "Er du sikker på at du vil lagre og starte serveren på nytt?",
 		"mod_section_title":       "Mod Administrasjon (Mods)",
@@ -1851,9 +1897,11 @@ var WebUIResources = map[string]map[string]string{
 		"msg_parse_fail":          "Nabigo ang pag-parse",
 		"msg_mod_list_updated":    "Na-update ang listahan ng mod, tandaan na i-click ang save",
 		"msg_save_fail":           "Nabigo ang pag-save",
+		"msg_save_conflict":       "Binago ng iba ang file. Itapon ang iyong mga pagbabago at i-reload?",
 		"msg_save_success":        "Matagumpay na nai-save",
 		"msg_cmd_sent":            "Naipadala ang command",
 		"msg_exec_fail":           "Nabigo ang execution",
+		"msg_server_update_done":  "Tapos na ang pag-update ng server",
 		"prompt_mod_id_manual":    "Nakita: {0}\nNgunit hindi maaaring awtomatikong makilala ang Mod ID.\nPakilagay ang Mod ID:",
 		"confirm_save_restart":    "Sigurado ka bang gusto mong i-save at i-restart ang server?",
 		"mod_section_title":       "Pamamahala ng Mods (Mods)",
@@ -1929,9 +1977,11 @@ var WebUIResources = map[string]map[string]string{
 		"msg_parse_fail":          "Análise falhou",
 		"msg_mod_list_updated":    "Lista de mods atualizada, lembre-se de guardar",
 		"msg_save_fail":           "Falha ao guardar",
+		"msg_save_conflict":       "O ficheiro foi alterado por outra pessoa. Descartar as suas alterações e recarregar?",
 		"msg_save_success":        "Guardado com sucesso",
 		"msg_cmd_sent":            "Comando enviado",
 		"msg_exec_fail":           "Execução falhou",
+		"msg_server_update_done":  "Atualização do servidor concluída",
 		"prompt_mod_id_manual":    "Encontrado: {0}\nMas não é possível identificar automaticamente o ID do mod.\nIntroduza o ID do mod:",
 		"confirm_save_restart":    "Tem a certeza que quer guardar e reiniciar o servidor?",
 		"mod_section_title":       "Gestão de Mods (Mods)",
@@ -2007,9 +2057,11 @@ var WebUIResources = map[string]map[string]string{
 		"msg_parse_fail":          "Parsare eșuată",
 		"msg_mod_list_updated":    "Lista de moduri actualizată, nu uitați să salvați",
 		"msg_save_fail":           "Salvare eșuată",
+		"msg_save_conflict":       "Fișierul a fost modificat de altcineva. Renunțați la modificări și reîncărcați?",
 		"msg_save_success":        "Salvare reușită",
 		"msg_cmd_sent":            "Comandă trimisă",
 		"msg_exec_fail":           "Execuție eșuată",
+		"msg_server_update_done":  "Actualizarea serverului finalizată",
 		"prompt_mod_id_manual":    "Găsit: {0}\nDar nu pot identifica automat ID-ul modului.\nIntroduceți ID-ul modului:",
 		"confirm_save_restart":    "Sunteți sigur că doriți să salvați și să reporniți serverul?",
 		"mod_section_title":       "Gestiunea Modurilor (Mods)",
@@ -2085,9 +2137,11 @@ var WebUIResources = map[string]map[string]string{
 		"msg_parse_fail":          "การแยกวิเคราะห์ล้มเหลว",
 		"msg_mod_list_updated":    "อัปเดตรายการม็อดแล้ว อย่าลืมคลิกบันทึก",
 		"msg_save_fail":           "บันทึกล้มเหลว",
+		"msg_save_conflict":       "มีผู้อื่นแก้ไขไฟล์แล้ว ต้องการละทิ้งการแก้ไขและโหลดใหม่หรือไม่?",
 		"msg_save_success":        "บันทึกสำเร็จ",
 		"msg_cmd_sent":            "ส่งคำสั่งแล้ว",
 		"msg_exec_fail":           "การดำเนินการล้มเหลว",
+		"msg_server_update_done":  "อัปเดตเซิร์ฟเวอร์เสร็จสิ้น",
 		"prompt_mod_id_manual":    "พบ: {0}\nแต่ไม่สามารถระบุ Mod ID โดยอัตโนมัติ\nกรุณาป้อน Mod ID:",
 		"confirm_save_restart":    "คุณแน่ใจหรือไม่ว่าต้องการบันทึกและรีสตาร์ทเซิร์ฟเวอร์?",
 		"mod_section_title":       "การจัดการม็อด (Mods)",
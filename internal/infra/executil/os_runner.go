@@ -1,9 +1,77 @@
 package executil
 
-import "os/exec"
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"os/exec"
+	"time"
+)
+
+// streamWaitDelay 进程退出或被结束后等待输出管道关闭的时间；
+// 后台子进程继承了管道时 Wait 不会因此一直阻塞。
+const streamWaitDelay = 5 * time.Second
 
 type OSRunner struct{}
 
 func (OSRunner) CombinedOutput(name string, args ...string) ([]byte, error) {
 	return exec.Command(name, args...).CombinedOutput()
 }
+
+// Stream 合并 stdout 与 stderr，按 "\n" 或 "\r" 分行回调；ctx 取消时结束整个进程组。
+func (OSRunner) Stream(ctx context.Context, onLine func(line string), name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	setProcessGroup(cmd)
+	cmd.WaitDelay = streamWaitDelay
+	pr, pw := io.Pipe()
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+
+	if err := cmd.Start(); err != nil {
+		_ = pw.Close()
+		_ = pr.Close()
+		return err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		sc := bufio.NewScanner(pr)
+		sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		sc.Split(scanLinesCR)
+		for sc.Scan() {
+			if line := sc.Text(); line != "" {
+				onLine(line)
+			}
+		}
+		// 读取失败时继续排空，避免子进程阻塞在写管道上。
+		_, _ = io.Copy(io.Discard, pr)
+	}()
+
+	err := cmd.Wait()
+	_ = pw.Close()
+	<-done
+	return err
+}
+
+// scanLinesCR 与 bufio.ScanLines 相同，但也把单独的 "\r" 视为行尾（进度条常用）。
+func scanLinesCR(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		advance := i + 1
+		if data[i] == '\r' && i+1 < len(data) && data[i+1] == '\n' {
+			advance++
+		} else if data[i] == '\r' && i+1 == len(data) && !atEOF {
+			// 可能是被截断的 "\r\n"，等待更多数据。
+			return 0, nil, nil
+		}
+		return advance, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
@@ -0,0 +1,56 @@
+package executil
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOSRunner_StreamSplitsCarriageReturns(t *testing.T) {
+	var lines []string
+	err := OSRunner{}.Stream(context.Background(), func(line string) {
+		lines = append(lines, line)
+	}, "sh", "-c", `printf 'a\rb\r\nc\n'; printf 'err\n' 1>&2`)
+	if err != nil {
+		t.Fatalf("Stream: %v", err)
+	}
+	want := []string{"a", "b", "c", "err"}
+	if !reflect.DeepEqual(lines, want) {
+		t.Fatalf("lines=%q", lines)
+	}
+}
+
+func TestOSRunner_StreamCancelKillsProcessGroup(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{}, 1)
+	done := make(chan error, 1)
+	go func() {
+		// 后台的 sleep 继承了输出管道，只结束 sh 时 Wait 会一直等它。
+		done <- OSRunner{}.Stream(ctx, func(line string) {
+			select {
+			case started <- struct{}{}:
+			default:
+			}
+		}, "sh", "-c", `sleep 30 & echo ready; wait`)
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("command did not start")
+	}
+	begin := time.Now()
+	cancel()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatalf("expected error after cancel")
+		}
+		if d := time.Since(begin); d >= streamWaitDelay {
+			t.Fatalf("took %s, background child kept the pipe open", d)
+		}
+	case <-time.After(2 * streamWaitDelay):
+		t.Fatalf("Stream did not return after cancel")
+	}
+}
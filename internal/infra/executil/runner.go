@@ -1,5 +1,12 @@
 package executil
 
+import "context"
+
 type Runner interface {
 	CombinedOutput(name string, args ...string) ([]byte, error)
 }
+
+// StreamRunner 逐行回调子进程的 stdout/stderr 输出，适用于长时间运行的命令。
+type StreamRunner interface {
+	Stream(ctx context.Context, onLine func(line string), name string, args ...string) error
+}
@@ -0,0 +1,66 @@
+package fs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// WriteFileAtomic 先写入同目录下的临时文件并 fsync，再 rename 覆盖目标，
+// 避免进程崩溃时留下被截断的配置文件。目标已存在时沿用其权限与属主。
+func WriteFileAtomic(name string, data []byte, perm os.FileMode) error {
+	info, statErr := os.Stat(name)
+	if statErr == nil {
+		perm = info.Mode().Perm()
+	} else if !errors.Is(statErr, os.ErrNotExist) {
+		return statErr
+	}
+
+	dir := filepath.Dir(name)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(name)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	cleanup := func() { _ = os.Remove(tmpName) }
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		cleanup()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		cleanup()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		cleanup()
+		return err
+	}
+	if err := os.Chmod(tmpName, perm); err != nil {
+		cleanup()
+		return err
+	}
+	if statErr == nil {
+		// 非 root 运行时可能无权修改属主，此时保持默认属主。
+		_ = chownLike(tmpName, info)
+	}
+
+	if err := os.Rename(tmpName, name); err != nil {
+		cleanup()
+		return err
+	}
+	return syncDir(dir)
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	// 部分文件系统不支持对目录 fsync，忽略该错误。
+	_ = d.Sync()
+	return nil
+}
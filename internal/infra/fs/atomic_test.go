@@ -0,0 +1,63 @@
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteFileAtomic_ReplacesAndKeepsMode(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "servertest.ini")
+
+	if err := WriteFileAtomic(name, []byte("PVP=false\n"), 0o644); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := os.Chmod(name, 0o640); err != nil {
+		t.Fatalf("chmod: %v", err)
+	}
+	if err := WriteFileAtomic(name, []byte("PVP=true\n"), 0o644); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+
+	got, err := os.ReadFile(name)
+	if err != nil || string(got) != "PVP=true\n" {
+		t.Fatalf("got=%q err=%v", got, err)
+	}
+	info, err := os.Stat(name)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if info.Mode().Perm() != 0o640 {
+		t.Fatalf("mode=%v", info.Mode().Perm())
+	}
+	assertNoTempFiles(t, dir)
+}
+
+func TestWriteFileAtomic_RemovesTempFileOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	// 目标是非空目录，rename 失败。
+	name := filepath.Join(dir, "servertest.ini")
+	if err := os.MkdirAll(filepath.Join(name, "keep"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	if err := WriteFileAtomic(name, []byte("PVP=true\n"), 0o644); err == nil {
+		t.Fatalf("expected error")
+	}
+	assertNoTempFiles(t, dir)
+}
+
+func assertNoTempFiles(t *testing.T, dir string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("readdir: %v", err)
+	}
+	for _, e := range entries {
+		if strings.Contains(e.Name(), ".tmp-") {
+			t.Fatalf("temp file left behind: %s", e.Name())
+		}
+	}
+}
@@ -4,6 +4,9 @@ import "os"
 
 type FS interface {
 	MkdirAll(path string, perm os.FileMode) error
+	ReadFile(name string) ([]byte, error)
 	WriteFile(name string, data []byte, perm os.FileMode) error
+	// WriteFileAtomic 通过临时文件 + fsync + rename 替换文件。
+	WriteFileAtomic(name string, data []byte, perm os.FileMode) error
 	ReadDir(name string) ([]os.DirEntry, error)
 }
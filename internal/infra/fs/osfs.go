@@ -8,10 +8,18 @@ func (OSFS) MkdirAll(path string, perm os.FileMode) error {
 	return os.MkdirAll(path, perm)
 }
 
+func (OSFS) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
+
 func (OSFS) WriteFile(name string, data []byte, perm os.FileMode) error {
 	return os.WriteFile(name, data, perm)
 }
 
+func (OSFS) WriteFileAtomic(name string, data []byte, perm os.FileMode) error {
+	return WriteFileAtomic(name, data, perm)
+}
+
 func (OSFS) ReadDir(name string) ([]os.DirEntry, error) {
 	return os.ReadDir(name)
 }
@@ -0,0 +1,7 @@
+//go:build !unix
+
+package fs
+
+import "os"
+
+func chownLike(name string, info os.FileInfo) error { return nil }
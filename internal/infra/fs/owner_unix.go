@@ -0,0 +1,17 @@
+//go:build unix
+
+package fs
+
+import (
+	"os"
+	"syscall"
+)
+
+// chownLike 将 name 的属主设置为与 info 相同。
+func chownLike(name string, info os.FileInfo) error {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+	return os.Chown(name, int(st.Uid), int(st.Gid))
+}
@@ -0,0 +1,233 @@
+package logtail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// FileTailer 纯 Go 实现的 tail -F：输出最后 N 行后持续跟随追加的内容，
+// 文件被截断时从头读起，被替换（inode 变化）或暂时删除时等新文件出现后重新打开。
+type FileTailer struct {
+	// PollInterval 检查新内容与轮转的间隔，默认 250ms。
+	PollInterval time.Duration
+}
+
+// Tail lines 的含义与 OSTailer 相同：FromEnd 只输出之后的新内容，<= 0 时默认 100 行。
+// 文件在调用时必须存在；Close 或 ctx 结束后后台读取随之停止。
+func (t FileTailer) Tail(ctx context.Context, path string, lines int) (io.ReadCloser, error) {
+	if path == "" {
+		return nil, fmt.Errorf("log path is required")
+	}
+	switch {
+	case lines == FromEnd:
+		lines = 0
+	case lines <= 0:
+		lines = 100
+	}
+
+	f, info, err := openTail(path)
+	if err != nil {
+		return nil, err
+	}
+	offset := info.Size()
+	if lines > 0 {
+		if offset, err = lastLinesOffset(f, info.Size(), lines); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	return t.follow(ctx, path, f, info, offset)
+}
+
+// TailLines 直接返回最后 n 行，reader 只输出读取这 n 行之后新写入的内容。
+func (t FileTailer) TailLines(ctx context.Context, path string, n int) ([]string, io.ReadCloser, error) {
+	if path == "" {
+		return nil, nil, fmt.Errorf("log path is required")
+	}
+	f, info, err := openTail(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var lines []string
+	if n > 0 {
+		off, err := lastLinesOffset(f, info.Size(), n)
+		if err != nil {
+			f.Close()
+			return nil, nil, err
+		}
+		buf := make([]byte, info.Size()-off)
+		if _, err := f.ReadAt(buf, off); err != nil && err != io.EOF {
+			f.Close()
+			return nil, nil, err
+		}
+		lines = splitLines(buf)
+	}
+	rc, err := t.follow(ctx, path, f, info, info.Size())
+	if err != nil {
+		return nil, nil, err
+	}
+	return lines, rc, nil
+}
+
+func openTail(path string) (*os.File, os.FileInfo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	return f, info, nil
+}
+
+// follow 从 offset 开始在后台跟随 f，接管 f 的关闭。
+func (t FileTailer) follow(ctx context.Context, path string, f *os.File, info os.FileInfo, offset int64) (io.ReadCloser, error) {
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	pr, pw := io.Pipe()
+	fl := &follower{
+		path:     path,
+		file:     f,
+		info:     info,
+		offset:   offset,
+		interval: t.pollInterval(),
+		out:      pw,
+	}
+	done := make(chan struct{})
+	// ctx 结束时关闭管道，解除阻塞在 Write 上的读取循环。
+	stop := context.AfterFunc(ctx, func() { _ = pw.Close() })
+	go func() {
+		defer close(done)
+		defer stop()
+		err := fl.run(ctx)
+		fl.file.Close()
+		if errors.Is(err, context.Canceled) {
+			err = nil
+		}
+		pw.CloseWithError(err)
+	}()
+	return &tailReader{PipeReader: pr, cancel: cancel, done: done}, nil
+}
+
+func (t FileTailer) pollInterval() time.Duration {
+	if t.PollInterval > 0 {
+		return t.PollInterval
+	}
+	return 250 * time.Millisecond
+}
+
+type follower struct {
+	path     string
+	file     *os.File
+	info     os.FileInfo
+	offset   int64
+	interval time.Duration
+	out      *io.PipeWriter
+}
+
+func (f *follower) run(ctx context.Context) error {
+	buf := make([]byte, 32*1024)
+	for {
+		if err := f.drain(ctx, buf); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(f.interval):
+		}
+		if err := f.checkRotation(ctx, buf); err != nil {
+			return err
+		}
+	}
+}
+
+// drain 读到当前文件末尾并写入管道。
+func (f *follower) drain(ctx context.Context, buf []byte) error {
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		n, err := f.file.Read(buf)
+		if n > 0 {
+			f.offset += int64(n)
+			if _, werr := f.out.Write(buf[:n]); werr != nil {
+				// 读取方已关闭。
+				return context.Canceled
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// checkRotation 路径指向了新文件时读完旧文件剩余内容再切换；同一文件变短时视为截断，从头读起。
+// 路径暂时不存在（轮转过程中）时继续等待。
+func (f *follower) checkRotation(ctx context.Context, buf []byte) error {
+	info, err := os.Stat(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !os.SameFile(f.info, info) {
+		next, err := os.Open(f.path)
+		if err != nil {
+			// 新文件可能还没准备好，下一轮再试。
+			return nil
+		}
+		if err := f.drain(ctx, buf); err != nil {
+			next.Close()
+			return err
+		}
+		nextInfo, err := next.Stat()
+		if err != nil {
+			next.Close()
+			return nil
+		}
+		f.file.Close()
+		f.file = next
+		f.info = nextInfo
+		f.offset = 0
+		return nil
+	}
+
+	if info.Size() < f.offset {
+		if _, err := f.file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		f.offset = 0
+	}
+	f.info = info
+	return nil
+}
+
+type tailReader struct {
+	*io.PipeReader
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Close 停止后台读取并等待其退出。
+func (r *tailReader) Close() error {
+	r.cancel()
+	_ = r.PipeReader.Close()
+	<-r.done
+	return nil
+}
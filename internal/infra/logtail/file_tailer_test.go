@@ -0,0 +1,171 @@
+package logtail
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// readLine 在超时时间内读取一行。
+func readLine(t *testing.T, lines <-chan string) string {
+	t.Helper()
+	select {
+	case l, ok := <-lines:
+		if !ok {
+			t.Fatalf("stream closed")
+		}
+		return l
+	case <-time.After(3 * time.Second):
+		t.Fatalf("timeout waiting for line")
+	}
+	return ""
+}
+
+func startTail(t *testing.T, path string, n int) (io.ReadCloser, <-chan string) {
+	t.Helper()
+	rc, err := FileTailer{PollInterval: 10 * time.Millisecond}.Tail(context.Background(), path, n)
+	if err != nil {
+		t.Fatalf("Tail: %v", err)
+	}
+	lines := make(chan string, 100)
+	go func() {
+		defer close(lines)
+		sc := bufio.NewScanner(rc)
+		for sc.Scan() {
+			lines <- sc.Text()
+		}
+	}()
+	t.Cleanup(func() { rc.Close() })
+	return rc, lines
+}
+
+func appendTo(t *testing.T, path, text string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(text); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileTailer_LastLinesThenFollow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.log")
+	appendTo(t, path, "a\nb\nc\n")
+	_, lines := startTail(t, path, 2)
+	if got := readLine(t, lines) + readLine(t, lines); got != "bc" {
+		t.Fatalf("got=%q", got)
+	}
+	appendTo(t, path, "d\n")
+	if got := readLine(t, lines); got != "d" {
+		t.Fatalf("got=%q", got)
+	}
+}
+
+func TestFileTailer_TailLinesSplitsAtSameOffset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.log")
+	appendTo(t, path, "a\nb\r\nc\n")
+	history, rc, err := FileTailer{PollInterval: 10 * time.Millisecond}.TailLines(context.Background(), path, 2)
+	if err != nil {
+		t.Fatalf("TailLines: %v", err)
+	}
+	defer rc.Close()
+	// 在开始读取 reader 之前写入的行只出现在 reader 中。
+	appendTo(t, path, "d\n")
+	if len(history) != 2 || history[0] != "b" || history[1] != "c" {
+		t.Fatalf("history=%q", history)
+	}
+	sc := bufio.NewScanner(rc)
+	if !sc.Scan() || sc.Text() != "d" {
+		t.Fatalf("next=%q err=%v", sc.Text(), sc.Err())
+	}
+}
+
+func TestFileTailer_FromEndSkipsExistingContent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.log")
+	appendTo(t, path, "old\n")
+	_, lines := startTail(t, path, FromEnd)
+	appendTo(t, path, "new\n")
+	if got := readLine(t, lines); got != "new" {
+		t.Fatalf("got=%q", got)
+	}
+}
+
+func TestFileTailer_HandlesTruncation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.log")
+	appendTo(t, path, "first run line 1\nfirst run line 2\n")
+	_, lines := startTail(t, path, FromEnd)
+	time.Sleep(50 * time.Millisecond)
+
+	if err := os.WriteFile(path, []byte("x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := readLine(t, lines); got != "x" {
+		t.Fatalf("got=%q", got)
+	}
+}
+
+func TestFileTailer_ReopensAfterRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "server.log")
+	appendTo(t, path, "")
+	_, lines := startTail(t, path, FromEnd)
+	time.Sleep(50 * time.Millisecond)
+
+	if err := os.Rename(path, filepath.Join(dir, "server.log.1")); err != nil {
+		t.Fatal(err)
+	}
+	// 轮转后写入旧文件的内容仍会输出。
+	appendTo(t, filepath.Join(dir, "server.log.1"), "tail of old\n")
+	time.Sleep(50 * time.Millisecond)
+	appendTo(t, path, "new file\n")
+
+	if got := readLine(t, lines); got != "tail of old" {
+		t.Fatalf("got=%q", got)
+	}
+	if got := readLine(t, lines); got != "new file" {
+		t.Fatalf("got=%q", got)
+	}
+	appendTo(t, path, "more\n")
+	if got := readLine(t, lines); got != "more" {
+		t.Fatalf("got=%q", got)
+	}
+}
+
+func TestFileTailer_StopsOnCancelAndClose(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.log")
+	appendTo(t, path, "a\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rc, err := FileTailer{PollInterval: 10 * time.Millisecond}.Tail(ctx, path, 10)
+	if err != nil {
+		t.Fatalf("Tail: %v", err)
+	}
+	cancel()
+	done := make(chan error, 1)
+	go func() {
+		_, err := io.ReadAll(rc)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("ReadAll: %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("reader not closed after cancel")
+	}
+	if err := rc.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if _, err := (FileTailer{}).Tail(context.Background(), filepath.Join(t.TempDir(), "missing.log"), 10); !os.IsNotExist(err) {
+		t.Fatalf("err=%v", err)
+	}
+}
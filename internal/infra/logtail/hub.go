@@ -0,0 +1,223 @@
+package logtail
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrSlowSubscriber 订阅者跟不上输出被断开；客户端可凭最后收到的 ID 重新订阅补齐。
+var ErrSlowSubscriber = errors.New("log subscriber too slow, dropped")
+
+const (
+	defaultHubCapacity   = 1000
+	defaultHubBacklog    = 100
+	defaultSubscriberBuf = 256
+)
+
+// Line 一行日志；ID 单调递增，用作 SSE 的 id。
+// 起始值取自读取者首次启动的时间（微秒），面板重启后的 ID 总是大于重启前的。
+type Line struct {
+	ID   uint64
+	Text string
+}
+
+// Hub 每个日志文件只有一个读取者，新行写入环形缓冲并分发给所有订阅者。
+// 读取者在第一次订阅时启动，之后一直运行，断线重连的客户端因此能按 ID 补齐错过的行。
+type Hub struct {
+	Tailer Tailer
+	Path   string
+	// Capacity 环形缓冲保留的行数，默认 1000。
+	Capacity int
+	// Backlog 新订阅者（没有 lastID）收到的历史行数，默认 100。
+	Backlog int
+	// SubscriberBuffer 每个订阅者的缓冲行数，写满时断开该订阅者，默认 256。
+	SubscriberBuffer int
+
+	mu      sync.Mutex
+	running bool
+	ring    []Line
+	start   int
+	lastID  uint64
+	subs    map[*Subscription]struct{}
+}
+
+// Subscription 订阅；C 关闭后 Err 说明原因（读取结束时为 nil）。
+type Subscription struct {
+	C <-chan Line
+
+	hub *Hub
+	ch  chan Line
+	err error
+}
+
+// Subscribe 返回需要补发的行与后续新行的订阅，两者之间不会遗漏或重复。
+// lastID 为客户端最后收到的 ID：在缓冲范围内时只返回之后的行；
+// 为 0 或已超出缓冲（断线太久、面板重启过）时返回最近 Backlog 行。
+func (h *Hub) Subscribe(lastID uint64) ([]Line, *Subscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.running {
+		if err := h.startLocked(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	ch := make(chan Line, h.subscriberBuffer())
+	sub := &Subscription{C: ch, hub: h, ch: ch}
+	if h.subs == nil {
+		h.subs = make(map[*Subscription]struct{})
+	}
+	h.subs[sub] = struct{}{}
+	return h.backlogLocked(lastID), sub, nil
+}
+
+// Close 取消订阅。
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.dropLocked(s, nil)
+}
+
+func (s *Subscription) Err() error {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	return s.err
+}
+
+func (h *Hub) startLocked() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	// 历史行直接读入缓冲而不经过订阅者，否则第一个订阅者会因一次收到上千行而被断开。
+	// 重新启动时缓冲里已有旧行，不再读取，避免同一行以新 ID 重复出现。
+	var history []string
+	var rc io.ReadCloser
+	var err error
+	bt, ok := h.Tailer.(BacklogTailer)
+	switch {
+	case len(h.ring) > 0:
+		rc, err = h.Tailer.Tail(ctx, h.Path, FromEnd)
+	case ok:
+		// 历史行与跟随从同一偏移开始，两者之间写入的行不会重复。
+		history, rc, err = bt.TailLines(ctx, h.Path, h.capacity())
+	default:
+		// 其他 Tailer 无法对齐偏移，两次读取之间写入的行可能重复出现。
+		rc, err = h.Tailer.Tail(ctx, h.Path, FromEnd)
+		if err == nil {
+			history, _ = LastLines(h.Path, h.capacity())
+		}
+	}
+	if err != nil {
+		cancel()
+		return err
+	}
+	h.running = true
+	if h.lastID == 0 {
+		h.lastID = uint64(time.Now().UnixMicro())
+	}
+	for _, l := range history {
+		if l != "" {
+			h.appendLocked(l)
+		}
+	}
+	go func() {
+		defer cancel()
+		defer rc.Close()
+		sc := bufio.NewScanner(rc)
+		sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for sc.Scan() {
+			if text := strings.TrimSuffix(sc.Text(), "\r"); text != "" {
+				h.publish(text)
+			}
+		}
+		h.stop(sc.Err())
+	}()
+	return nil
+}
+
+func (h *Hub) publish(text string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	line := h.appendLocked(text)
+	for sub := range h.subs {
+		select {
+		case sub.ch <- line:
+		default:
+			h.dropLocked(sub, ErrSlowSubscriber)
+		}
+	}
+}
+
+func (h *Hub) appendLocked(text string) Line {
+	h.lastID++
+	line := Line{ID: h.lastID, Text: text}
+	if len(h.ring) < h.capacity() {
+		h.ring = append(h.ring, line)
+	} else {
+		h.ring[h.start] = line
+		h.start = (h.start + 1) % len(h.ring)
+	}
+	return line
+}
+
+// stop 读取结束（文件读取出错或 Tailer 退出）时关闭所有订阅，下一次订阅会重新启动读取。
+func (h *Hub) stop(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.running = false
+	for sub := range h.subs {
+		h.dropLocked(sub, err)
+	}
+}
+
+func (h *Hub) dropLocked(sub *Subscription, err error) {
+	if _, ok := h.subs[sub]; !ok {
+		return
+	}
+	delete(h.subs, sub)
+	sub.err = err
+	close(sub.ch)
+}
+
+func (h *Hub) backlogLocked(lastID uint64) []Line {
+	n := len(h.ring)
+	all := make([]Line, 0, n)
+	all = append(all, h.ring[h.start:]...)
+	all = append(all, h.ring[:h.start]...)
+	if n == 0 {
+		return all
+	}
+
+	oldest := all[0].ID
+	if lastID != 0 && lastID+1 >= oldest && lastID <= h.lastID {
+		return all[lastID+1-oldest:]
+	}
+	if backlog := h.backlog(); n > backlog {
+		return all[n-backlog:]
+	}
+	return all
+}
+
+func (h *Hub) capacity() int {
+	if h.Capacity > 0 {
+		return h.Capacity
+	}
+	return defaultHubCapacity
+}
+
+func (h *Hub) backlog() int {
+	if h.Backlog > 0 {
+		return h.Backlog
+	}
+	return defaultHubBacklog
+}
+
+func (h *Hub) subscriberBuffer() int {
+	if h.SubscriberBuffer > 0 {
+		return h.SubscriberBuffer
+	}
+	return defaultSubscriberBuf
+}
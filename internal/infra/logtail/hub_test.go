@@ -0,0 +1,116 @@
+package logtail
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type countingTailer struct {
+	FileTailer
+	calls atomic.Int32
+}
+
+func (c *countingTailer) Tail(ctx context.Context, path string, lines int) (io.ReadCloser, error) {
+	c.calls.Add(1)
+	return c.FileTailer.Tail(ctx, path, lines)
+}
+
+func (c *countingTailer) TailLines(ctx context.Context, path string, n int) ([]string, io.ReadCloser, error) {
+	c.calls.Add(1)
+	return c.FileTailer.TailLines(ctx, path, n)
+}
+
+func texts(lines []Line) string {
+	var s string
+	for _, l := range lines {
+		s += l.Text
+	}
+	return s
+}
+
+func receive(t *testing.T, sub *Subscription) Line {
+	t.Helper()
+	select {
+	case l, ok := <-sub.C:
+		if !ok {
+			t.Fatalf("subscription closed: %v", sub.Err())
+		}
+		return l
+	case <-time.After(3 * time.Second):
+		t.Fatalf("timeout")
+	}
+	return Line{}
+}
+
+func TestHub_BacklogFanOutAndResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.log")
+	appendTo(t, path, "a\nb\n\nc\nd\ne\n")
+	tailer := &countingTailer{FileTailer: FileTailer{PollInterval: 10 * time.Millisecond}}
+	hub := &Hub{Tailer: tailer, Path: path, Capacity: 4, Backlog: 2}
+
+	backlog, first, err := hub.Subscribe(0)
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	defer first.Close()
+	if texts(backlog) != "de" || backlog[1].ID != backlog[0].ID+1 {
+		t.Fatalf("backlog=%+v", backlog)
+	}
+	_, second, _ := hub.Subscribe(0)
+	defer second.Close()
+
+	appendTo(t, path, "f\n")
+	got := receive(t, first)
+	if got.Text != "f" || got.ID != backlog[1].ID+1 || receive(t, second) != got {
+		t.Fatalf("got=%+v", got)
+	}
+	if tailer.calls.Load() != 1 {
+		t.Fatalf("tail calls=%d", tailer.calls.Load())
+	}
+
+	// 断线重连：只补发 lastID 之后的行。
+	missed, third, _ := hub.Subscribe(backlog[0].ID)
+	defer third.Close()
+	if texts(missed) != "ef" {
+		t.Fatalf("missed=%+v", missed)
+	}
+	// lastID 已超出缓冲时退回最近 Backlog 行。
+	if old, sub, _ := hub.Subscribe(1); texts(old) != "ef" {
+		t.Fatalf("old=%+v", old)
+	} else {
+		sub.Close()
+	}
+}
+
+func TestHub_DropsSlowSubscriber(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.log")
+	appendTo(t, path, "")
+	hub := &Hub{Tailer: FileTailer{PollInterval: 10 * time.Millisecond}, Path: path, SubscriberBuffer: 1}
+
+	_, slow, err := hub.Subscribe(0)
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	_, fast, _ := hub.Subscribe(0)
+	defer fast.Close()
+
+	for _, want := range []string{"1", "2", "3"} {
+		appendTo(t, path, want+"\n")
+		if got := receive(t, fast); got.Text != want {
+			t.Fatalf("got=%+v want %s", got, want)
+		}
+	}
+
+	var n int
+	for range slow.C {
+		n++
+	}
+	if n != 1 || !errors.Is(slow.Err(), ErrSlowSubscriber) {
+		t.Fatalf("n=%d err=%v", n, slow.Err())
+	}
+}
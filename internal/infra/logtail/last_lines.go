@@ -0,0 +1,86 @@
+package logtail
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+// LastLines 从文件末尾向前读取，返回最后 n 行（不含换行符）。
+func LastLines(path string, n int) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if n <= 0 {
+		return nil, nil
+	}
+	off, err := lastLinesOffset(f, info.Size(), n)
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, info.Size()-off)
+	if _, err := f.ReadAt(buf, off); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return splitLines(buf), nil
+}
+
+// splitLines 按行拆分，去掉行尾的 \r 与末尾的空行。
+func splitLines(buf []byte) []string {
+	text := bytes.TrimRight(buf, "\n")
+	if len(text) == 0 {
+		return nil
+	}
+	lines := bytes.Split(text, []byte("\n"))
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		out[i] = string(bytes.TrimSuffix(l, []byte("\r")))
+	}
+	return out
+}
+
+// lastLinesOffset 从 size 处向前按块查找，返回最后 n 行的起始偏移；末尾的换行不算作一行。
+func lastLinesOffset(r io.ReaderAt, size int64, n int) (int64, error) {
+	const chunk = 64 * 1024
+	end := size
+	// 忽略文件末尾的换行，否则最后一行会被算成空行。
+	if end > 0 {
+		var last [1]byte
+		if _, err := r.ReadAt(last[:], end-1); err != nil && err != io.EOF {
+			return 0, err
+		}
+		if last[0] == '\n' {
+			end--
+		}
+	}
+
+	found := 0
+	buf := make([]byte, chunk)
+	for off := end; off > 0; {
+		step := int64(chunk)
+		if off < step {
+			step = off
+		}
+		off -= step
+		part := buf[:step]
+		if _, err := r.ReadAt(part, off); err != nil && err != io.EOF {
+			return 0, err
+		}
+		for i := len(part) - 1; i >= 0; i-- {
+			if part[i] != '\n' {
+				continue
+			}
+			found++
+			if found == n {
+				return off + int64(i) + 1, nil
+			}
+		}
+	}
+	return 0, nil
+}
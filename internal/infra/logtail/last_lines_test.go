@@ -0,0 +1,32 @@
+package logtail
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLastLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.log")
+	var b strings.Builder
+	for i := 1; i <= 5000; i++ {
+		fmt.Fprintf(&b, "line %d\r\n", i)
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	got, err := LastLines(path, 3)
+	if err != nil {
+		t.Fatalf("LastLines: %v", err)
+	}
+	if !reflect.DeepEqual(got, []string{"line 4998", "line 4999", "line 5000"}) {
+		t.Fatalf("got=%q", got)
+	}
+	if got, _ := LastLines(path, 10000); len(got) != 5000 || got[0] != "line 1" {
+		t.Fatalf("len=%d first=%q", len(got), got[0])
+	}
+}
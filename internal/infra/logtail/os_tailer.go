@@ -14,7 +14,10 @@ func (OSTailer) Tail(ctx context.Context, path string, lines int) (io.ReadCloser
 	if path == "" {
 		return nil, fmt.Errorf("log path is required")
 	}
-	if lines <= 0 {
+	switch {
+	case lines == FromEnd:
+		lines = 0
+	case lines <= 0:
 		lines = 100
 	}
 
@@ -5,6 +5,14 @@ import (
 	"io"
 )
 
+// FromEnd 作为 lines 传入时只输出调用之后新写入的行。
+const FromEnd = -1
+
 type Tailer interface {
 	Tail(ctx context.Context, path string, lines int) (io.ReadCloser, error)
 }
+
+// BacklogTailer 在同一偏移上取得最后 n 行并从其后开始跟随，两者之间不会重复或遗漏。
+type BacklogTailer interface {
+	TailLines(ctx context.Context, path string, n int) ([]string, io.ReadCloser, error)
+}
@@ -0,0 +1,244 @@
+package procmgr
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Direct 由面板自己启动并看管 start-server.sh。
+// 子进程独立成进程组，面板重启不会带走服务器；重启后通过 PIDFile 接管。
+type Direct struct {
+	Command string
+	Args    []string
+	Dir     string
+	// LogPath 子进程 stdout/stderr 追加写入的文件，为空时丢弃。
+	LogPath string
+	PIDFile string
+	// StopTimeout SIGTERM 后等待退出的时间，超时发送 SIGKILL，默认 60s。
+	StopTimeout time.Duration
+	Now         func() time.Time
+
+	mu        sync.Mutex
+	pid       int
+	startedAt time.Time
+	exited    chan struct{}
+	exitCode  int
+}
+
+func (d *Direct) StartPZServer() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if pid, _ := d.livePIDLocked(); pid != 0 {
+		return nil
+	}
+
+	cmd := exec.Command(d.Command, d.Args...)
+	cmd.Dir = d.Dir
+	if cmd.Dir == "" {
+		cmd.Dir = filepath.Dir(d.Command)
+	}
+	setProcessGroup(cmd)
+
+	var logFile *os.File
+	if d.LogPath != "" {
+		f, err := os.OpenFile(d.LogPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return fmt.Errorf("open server log: %w", err)
+		}
+		logFile = f
+		cmd.Stdout = f
+		cmd.Stderr = f
+	}
+	if err := cmd.Start(); err != nil {
+		if logFile != nil {
+			logFile.Close()
+		}
+		return fmt.Errorf("start %s: %w", d.Command, err)
+	}
+
+	d.pid = cmd.Process.Pid
+	d.startedAt = d.now()
+	d.exited = make(chan struct{})
+	if d.PIDFile != "" {
+		// PID 文件只用于面板重启后的接管，写入失败不影响本次启动。
+		_ = os.MkdirAll(filepath.Dir(d.PIDFile), 0o755)
+		_ = os.WriteFile(d.PIDFile, []byte(pidFileContent(d.pid)), 0o644)
+	}
+
+	exited := d.exited
+	go func() {
+		err := cmd.Wait()
+		if logFile != nil {
+			logFile.Close()
+		}
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		d.exitCode = exitCode(err)
+		d.pid = 0
+		if d.PIDFile != "" {
+			_ = os.Remove(d.PIDFile)
+		}
+		close(exited)
+	}()
+	return nil
+}
+
+// StopPZServer 向进程组发送 SIGTERM，超时后 SIGKILL；未运行时视为成功。
+func (d *Direct) StopPZServer() error {
+	d.mu.Lock()
+	pid, exited := d.livePIDLocked()
+	d.mu.Unlock()
+	if pid == 0 {
+		return nil
+	}
+
+	if err := terminateGroup(pid); err != nil {
+		return fmt.Errorf("stop pid %d: %w", pid, err)
+	}
+	if waitExit(pid, exited, d.stopTimeout()) {
+		d.clearAdopted(pid)
+		return nil
+	}
+	if err := killGroup(pid); err != nil {
+		return fmt.Errorf("kill pid %d: %w", pid, err)
+	}
+	if !waitExit(pid, exited, 10*time.Second) {
+		return fmt.Errorf("pid %d did not exit", pid)
+	}
+	d.clearAdopted(pid)
+	return nil
+}
+
+func (d *Direct) RestartPZServer() error {
+	if err := d.StopPZServer(); err != nil {
+		return err
+	}
+	return d.StartPZServer()
+}
+
+func (d *Direct) Status() (Status, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	st := Status{Manager: ModeDirect, State: StateStopped, ExitCode: d.exitCode}
+	pid, _ := d.livePIDLocked()
+	if pid == 0 {
+		return st, nil
+	}
+
+	st.State = StateRunning
+	st.PID = pid
+	st.StartedAt = d.startedAt
+	if pid != d.pid {
+		// 接管的进程：以 PID 文件的写入时间作为启动时间。
+		st.Detail = "adopted from " + d.PIDFile
+		if info, err := os.Stat(d.PIDFile); err == nil {
+			st.StartedAt = info.ModTime()
+		}
+	}
+	if !st.StartedAt.IsZero() {
+		st.UptimeSeconds = int64(d.now().Sub(st.StartedAt) / time.Second)
+	}
+	return st, nil
+}
+
+// livePIDLocked 返回正在运行的 PID：优先本进程启动的子进程，其次 PID 文件中仍存活的进程。
+// exited 仅对本进程启动的子进程有效。
+func (d *Direct) livePIDLocked() (int, chan struct{}) {
+	if d.pid != 0 {
+		return d.pid, d.exited
+	}
+	if d.PIDFile == "" {
+		return 0, nil
+	}
+	data, err := os.ReadFile(d.PIDFile)
+	if err != nil {
+		return 0, nil
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0, nil
+	}
+	pid, err := strconv.Atoi(fields[0])
+	if err != nil || pid <= 0 || !processAlive(pid) {
+		return 0, nil
+	}
+	// PID 被系统回收给其他进程时启动时间对不上，不能向它的进程组发信号。
+	// 旧版本写入的 PID 文件没有启动时间，只能按存活判断。
+	if len(fields) > 1 && fields[1] != processStartTime(pid) {
+		_ = os.Remove(d.PIDFile)
+		return 0, nil
+	}
+	return pid, nil
+}
+
+// pidFileContent PID 文件内容为 "<pid> <启动时间>"，无法获取启动时间时只有 PID。
+func pidFileContent(pid int) string {
+	if st := processStartTime(pid); st != "" {
+		return strconv.Itoa(pid) + " " + st
+	}
+	return strconv.Itoa(pid)
+}
+
+// clearAdopted 接管的进程退出后删除 PID 文件。
+func (d *Direct) clearAdopted(pid int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.pid != pid && d.PIDFile != "" {
+		_ = os.Remove(d.PIDFile)
+	}
+}
+
+func (d *Direct) stopTimeout() time.Duration {
+	if d.StopTimeout > 0 {
+		return d.StopTimeout
+	}
+	return 60 * time.Second
+}
+
+func (d *Direct) now() time.Time {
+	if d.Now != nil {
+		return d.Now()
+	}
+	return time.Now()
+}
+
+// waitExit 等待进程退出；本进程的子进程用 exited 通知，接管的进程轮询存活状态。
+func waitExit(pid int, exited chan struct{}, timeout time.Duration) bool {
+	deadline := time.After(timeout)
+	if exited != nil {
+		select {
+		case <-exited:
+			return true
+		case <-deadline:
+			return false
+		}
+	}
+	tick := time.NewTicker(200 * time.Millisecond)
+	defer tick.Stop()
+	for processAlive(pid) {
+		select {
+		case <-tick.C:
+		case <-deadline:
+			return false
+		}
+	}
+	return true
+}
+
+func exitCode(err error) int {
+	var ee *exec.ExitError
+	if errors.As(err, &ee) {
+		return ee.ExitCode()
+	}
+	if err != nil {
+		return -1
+	}
+	return 0
+}
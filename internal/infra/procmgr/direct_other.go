@@ -0,0 +1,25 @@
+//go:build !unix
+
+package procmgr
+
+import (
+	"os"
+	"os/exec"
+)
+
+func setProcessGroup(cmd *exec.Cmd) {}
+
+func terminateGroup(pid int) error { return killGroup(pid) }
+
+func killGroup(pid int) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return nil
+	}
+	return p.Kill()
+}
+
+// processAlive 非 unix 平台无法探测任意 PID，只认本进程启动的子进程。
+func processAlive(pid int) bool { return false }
+
+func processStartTime(pid int) string { return "" }
@@ -0,0 +1,104 @@
+//go:build unix
+
+package procmgr
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestDirect_StartStopAndAdopt(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "start-server.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho \"started $1\"\nexec sleep 30\n"), 0o755); err != nil {
+		t.Fatalf("write script: %v", err)
+	}
+	logPath := filepath.Join(dir, "server.log")
+	pidFile := filepath.Join(dir, "server.pid")
+
+	d := &Direct{Command: script, Args: []string{"-servername"}, LogPath: logPath, PIDFile: pidFile, StopTimeout: 5 * time.Second}
+	if err := d.StartPZServer(); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	st, _ := d.Status()
+	if !st.Running() || st.PID == 0 {
+		t.Fatalf("status=%+v", st)
+	}
+
+	// 脚本输出写入日志后再停服，否则 SIGTERM 可能先于 echo 到达。
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		data, _ := os.ReadFile(logPath)
+		if strings.Contains(string(data), "started -servername") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("log=%q", data)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// 模拟面板重启：新实例通过 PID 文件接管。
+	adopted := &Direct{Command: script, PIDFile: pidFile, StopTimeout: 5 * time.Second}
+	if st2, _ := adopted.Status(); !st2.Running() || st2.PID != st.PID || st2.Detail == "" {
+		t.Fatalf("adopted status=%+v", st2)
+	}
+	if err := adopted.StopPZServer(); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+
+	deadline = time.Now().Add(5 * time.Second)
+	for {
+		st, _ = d.Status()
+		if !st.Running() || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if st.State != StateStopped {
+		t.Fatalf("status=%+v", st)
+	}
+	if _, err := os.Stat(pidFile); !os.IsNotExist(err) {
+		t.Fatalf("pid file still present: %v", err)
+	}
+	if err := d.StopPZServer(); err != nil {
+		t.Fatalf("stop stopped: %v", err)
+	}
+}
+
+func TestDirect_IgnoresReusedPID(t *testing.T) {
+	// 另一个进程占用了 PID 文件里的 PID，但启动时间与记录的不同。
+	other := exec.Command("sleep", "30")
+	other.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := other.Start(); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	defer func() {
+		_ = other.Process.Kill()
+		_ = other.Wait()
+	}()
+
+	pidFile := filepath.Join(t.TempDir(), "server.pid")
+	if err := os.WriteFile(pidFile, []byte(fmt.Sprintf("%d 1", other.Process.Pid)), 0o644); err != nil {
+		t.Fatalf("write pid file: %v", err)
+	}
+	d := &Direct{Command: "/bin/false", PIDFile: pidFile, StopTimeout: time.Second}
+	if st, _ := d.Status(); st.Running() {
+		t.Fatalf("status=%+v", st)
+	}
+	if err := d.StopPZServer(); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if !processAlive(other.Process.Pid) {
+		t.Fatalf("unrelated process was signalled")
+	}
+	if _, err := os.Stat(pidFile); !os.IsNotExist(err) {
+		t.Fatalf("stale pid file kept: %v", err)
+	}
+}
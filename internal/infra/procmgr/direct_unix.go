@@ -0,0 +1,52 @@
+//go:build unix
+
+package procmgr
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// setProcessGroup 让子进程独立成组，信号可以送达 start-server.sh 启动的 java 进程。
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+func terminateGroup(pid int) error { return signalGroup(pid, syscall.SIGTERM) }
+
+func killGroup(pid int) error { return signalGroup(pid, syscall.SIGKILL) }
+
+func signalGroup(pid int, sig syscall.Signal) error {
+	err := syscall.Kill(-pid, sig)
+	if err == syscall.ESRCH {
+		return nil
+	}
+	return err
+}
+
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
+
+// processStartTime 返回 /proc/<pid>/stat 中的 starttime（开机后的时钟滴答数），没有 /proc 时返回空。
+func processStartTime(pid int) string {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return ""
+	}
+	// comm 中可能有空格和括号，从最后一个 ')' 之后开始数，第一项为 state（第 3 项）。
+	s := string(data)
+	i := strings.LastIndexByte(s, ')')
+	if i < 0 {
+		return ""
+	}
+	fields := strings.Fields(s[i+1:])
+	if len(fields) < 20 {
+		return ""
+	}
+	return fields[19]
+}
@@ -0,0 +1,178 @@
+package procmgr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultDockerSocket Docker Engine API 默认的 unix socket。
+const DefaultDockerSocket = "/var/run/docker.sock"
+
+// Docker 通过 Docker Engine API 管理游戏服务器所在的容器。
+type Docker struct {
+	Socket    string
+	Container string
+	// StopTimeout 传给 Engine API 的 t 参数，超时后容器被强制结束。
+	StopTimeout time.Duration
+	Now         func() time.Time
+
+	httpClient *http.Client
+}
+
+func NewDocker(socket, container string) Docker {
+	if socket == "" {
+		socket = DefaultDockerSocket
+	}
+	return Docker{
+		Socket:      socket,
+		Container:   container,
+		StopTimeout: 60 * time.Second,
+		httpClient: &http.Client{Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socket)
+			},
+		}},
+	}
+}
+
+func (d Docker) StartPZServer() error {
+	return d.post("start", nil)
+}
+
+func (d Docker) StopPZServer() error {
+	return d.post("stop", url.Values{"t": {d.stopSeconds()}})
+}
+
+func (d Docker) RestartPZServer() error {
+	return d.post("restart", url.Values{"t": {d.stopSeconds()}})
+}
+
+func (d Docker) Status() (Status, error) {
+	resp, err := d.do(http.MethodGet, "/containers/"+url.PathEscape(d.Container)+"/json", 10*time.Second)
+	if err != nil {
+		return Status{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Status{}, dockerError("inspect", resp)
+	}
+
+	var body struct {
+		State struct {
+			Status    string    `json:"Status"`
+			Pid       int       `json:"Pid"`
+			ExitCode  int       `json:"ExitCode"`
+			Error     string    `json:"Error"`
+			StartedAt time.Time `json:"StartedAt"`
+		} `json:"State"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return Status{}, fmt.Errorf("docker inspect: %w", err)
+	}
+	s := body.State
+	st := Status{
+		Manager:  ModeDocker,
+		State:    dockerState(s.Status),
+		PID:      s.Pid,
+		ExitCode: s.ExitCode,
+		Detail:   strings.TrimSpace(s.Status + " " + s.Error),
+	}
+	if st.Running() {
+		st.StartedAt = s.StartedAt
+		st.UptimeSeconds = int64(d.now().Sub(s.StartedAt) / time.Second)
+	}
+	return st, nil
+}
+
+// post 调用容器操作；304 表示容器已处于目标状态，视为成功。
+func (d Docker) post(action string, query url.Values) error {
+	path := "/containers/" + url.PathEscape(d.Container) + "/" + action
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	// 停止需要等待容器退出，超时要比 StopTimeout 长。
+	resp, err := d.do(http.MethodPost, path, d.StopTimeout+30*time.Second)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusNoContent, http.StatusNotModified:
+		return nil
+	}
+	return dockerError(action, resp)
+}
+
+func (d Docker) do(method, path string, timeout time.Duration) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	req, err := http.NewRequestWithContext(ctx, method, "http://docker"+path, nil)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("docker %s %s: %w", method, path, err)
+	}
+	resp.Body = cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+func (d Docker) stopSeconds() string {
+	return fmt.Sprint(int(d.StopTimeout / time.Second))
+}
+
+func (d Docker) now() time.Time {
+	if d.Now != nil {
+		return d.Now()
+	}
+	return time.Now()
+}
+
+// dockerError 从 Engine API 的 {"message": "..."} 中提取错误信息。
+func dockerError(action string, resp *http.Response) error {
+	var body struct {
+		Message string `json:"message"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if json.Unmarshal(data, &body) != nil || body.Message == "" {
+		body.Message = strings.TrimSpace(string(data))
+	}
+	return fmt.Errorf("docker %s: http %d: %s", action, resp.StatusCode, body.Message)
+}
+
+func dockerState(status string) string {
+	switch status {
+	case "running":
+		return StateRunning
+	case "restarting":
+		return StateStarting
+	case "removing":
+		return StateStopping
+	case "created", "exited", "paused":
+		return StateStopped
+	case "dead":
+		return StateFailed
+	}
+	return StateUnknown
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
@@ -0,0 +1,76 @@
+package procmgr
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDocker_ActionsAndStatus(t *testing.T) {
+	sock := filepath.Join(t.TempDir(), "docker.sock")
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skipf("unix socket unavailable: %v", err)
+	}
+	var calls []string
+	running := false
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.RequestURI())
+		switch r.URL.Path {
+		case "/containers/pz/start":
+			if running {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			running = true
+			w.WriteHeader(http.StatusNoContent)
+		case "/containers/pz/stop":
+			running = false
+			w.WriteHeader(http.StatusNoContent)
+		case "/containers/pz/json":
+			status := "exited"
+			if running {
+				status = "running"
+			}
+			fmt.Fprintf(w, `{"State":{"Status":%q,"Pid":77,"ExitCode":0,"StartedAt":"2024-01-02T03:04:05.123Z"}}`, status)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"No such container: missing"}`)
+		}
+	})}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	d := NewDocker(sock, "pz")
+	d.StopTimeout = 5 * time.Second
+	d.Now = func() time.Time { return time.Date(2024, 1, 2, 3, 5, 5, 123e6, time.UTC) }
+
+	if err := d.StartPZServer(); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := d.StartPZServer(); err != nil {
+		t.Fatalf("start again: %v", err)
+	}
+	st, err := d.Status()
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if !st.Running() || st.PID != 77 || st.UptimeSeconds != 60 {
+		t.Fatalf("status=%+v", st)
+	}
+	if err := d.StopPZServer(); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if calls[len(calls)-1] != "POST /containers/pz/stop?t=5" {
+		t.Fatalf("calls=%v", calls)
+	}
+
+	d.Container = "missing"
+	if _, err := d.Status(); err == nil || !strings.Contains(err.Error(), "No such container") {
+		t.Fatalf("err=%v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package procmgr
+
+import (
+	"fmt"
+	"time"
+
+	"pz-web-backend/internal/infra/executil"
+	"pz-web-backend/internal/infra/supervisor"
+)
+
+// ProcessManager 管理游戏服务器进程：启动、停止、重启与状态查询。
+type ProcessManager interface {
+	supervisor.Controller
+	Status() (Status, error)
+}
+
+// 统一后的进程状态。
+const (
+	StateRunning  = "running"
+	StateStarting = "starting"
+	StateStopping = "stopping"
+	StateStopped  = "stopped"
+	StateFailed   = "failed"
+	StateUnknown  = "unknown"
+)
+
+// Status 与具体进程管理方式无关的进程状态。
+type Status struct {
+	// Manager 进程管理方式，见 Mode*。
+	Manager       string    `json:"manager"`
+	State         string    `json:"state"`
+	PID           int       `json:"pid,omitempty"`
+	StartedAt     time.Time `json:"started_at"`
+	UptimeSeconds int64     `json:"uptime_seconds"`
+	ExitCode      int       `json:"exit_code"`
+	// Detail 原始状态描述，例如 supervisord 的 description 或 systemd 的 SubState。
+	Detail string `json:"detail,omitempty"`
+}
+
+// Running 进程是否在运行。
+func (s Status) Running() bool {
+	return s.State == StateRunning
+}
+
+// 可选的进程管理方式。
+const (
+	ModeSupervisord = "supervisord"
+	ModeSystemd     = "systemd"
+	ModeDocker      = "docker"
+	ModeDirect      = "direct"
+)
+
+// Config 选择并配置进程管理方式；未使用的字段会被忽略。
+type Config struct {
+	// Mode 为空时使用 supervisord。
+	Mode string
+
+	SupervisorURL      string
+	SupervisorUser     string
+	SupervisorPassword string
+
+	// SystemdUnit 默认 pzserver.service。
+	SystemdUnit string
+	Runner      executil.Runner
+
+	// DockerSocket 默认 /var/run/docker.sock；DockerContainer 必填。
+	DockerSocket    string
+	DockerContainer string
+
+	// Command direct 模式下启动的脚本，例如 /opt/pzserver/start-server.sh。
+	Command string
+	Args    []string
+	// LogPath direct 模式下子进程输出追加写入的文件。
+	LogPath string
+	// PIDFile direct 模式下记录子进程 PID，面板重启后据此接管仍在运行的服务器。
+	PIDFile string
+}
+
+// New 根据 cfg.Mode 构造 ProcessManager。
+func New(cfg Config) (ProcessManager, error) {
+	switch cfg.Mode {
+	case "", ModeSupervisord:
+		return Supervisord{Client: supervisor.NewClient(cfg.SupervisorURL, cfg.SupervisorUser, cfg.SupervisorPassword)}, nil
+	case ModeSystemd:
+		runner := cfg.Runner
+		if runner == nil {
+			runner = executil.OSRunner{}
+		}
+		return Systemd{Runner: runner, Unit: cfg.SystemdUnit}, nil
+	case ModeDocker:
+		if cfg.DockerContainer == "" {
+			return nil, fmt.Errorf("docker mode requires a container name")
+		}
+		return NewDocker(cfg.DockerSocket, cfg.DockerContainer), nil
+	case ModeDirect:
+		if cfg.Command == "" {
+			return nil, fmt.Errorf("direct mode requires a command")
+		}
+		return &Direct{Command: cfg.Command, Args: cfg.Args, LogPath: cfg.LogPath, PIDFile: cfg.PIDFile}, nil
+	}
+	return nil, fmt.Errorf("unknown process manager %q", cfg.Mode)
+}
@@ -0,0 +1,61 @@
+package procmgr
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"pz-web-backend/internal/infra/supervisor"
+)
+
+func TestNew_SelectsMode(t *testing.T) {
+	cases := map[string]Config{
+		"procmgr.Supervisord": {},
+		"procmgr.Systemd":     {Mode: ModeSystemd},
+		"procmgr.Docker":      {Mode: ModeDocker, DockerContainer: "pzserver"},
+		"*procmgr.Direct":     {Mode: ModeDirect, Command: "/opt/pzserver/start-server.sh"},
+	}
+	for want, cfg := range cases {
+		pm, err := New(cfg)
+		if err != nil {
+			t.Fatalf("%s: %v", want, err)
+		}
+		if got := fmt.Sprintf("%T", pm); got != want {
+			t.Fatalf("mode=%q got %s want %s", cfg.Mode, got, want)
+		}
+	}
+
+	for _, cfg := range []Config{{Mode: "launchd"}, {Mode: ModeDocker}, {Mode: ModeDirect}} {
+		if _, err := New(cfg); err == nil {
+			t.Fatalf("mode=%q expected error", cfg.Mode)
+		}
+	}
+}
+
+type fakeSupervisorClient struct{ info supervisor.ProcessInfo }
+
+func (fakeSupervisorClient) StartPZServer() error   { return nil }
+func (fakeSupervisorClient) StopPZServer() error    { return nil }
+func (fakeSupervisorClient) RestartPZServer() error { return nil }
+func (f fakeSupervisorClient) Status() (supervisor.ProcessInfo, error) {
+	return f.info, nil
+}
+
+func TestSupervisord_StatusMapsState(t *testing.T) {
+	started := time.Unix(1700000000, 0)
+	s := Supervisord{Client: fakeSupervisorClient{supervisor.ProcessInfo{
+		State: "RUNNING", PID: 42, StartedAt: started, UptimeSeconds: 90, Description: "pid 42, uptime 0:01:30",
+	}}}
+	st, err := s.Status()
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if !st.Running() || st.Manager != ModeSupervisord || st.PID != 42 || st.UptimeSeconds != 90 || !st.StartedAt.Equal(started) {
+		t.Fatalf("status=%+v", st)
+	}
+
+	s.Client = fakeSupervisorClient{supervisor.ProcessInfo{State: "FATAL", ExitStatus: 1}}
+	if st, _ := s.Status(); st.State != StateFailed || st.ExitCode != 1 {
+		t.Fatalf("status=%+v", st)
+	}
+}
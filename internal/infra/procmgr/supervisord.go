@@ -0,0 +1,50 @@
+package procmgr
+
+import "pz-web-backend/internal/infra/supervisor"
+
+// SupervisorClient Supervisord 依赖的 supervisor.Client 方法。
+type SupervisorClient interface {
+	supervisor.Controller
+	Status() (supervisor.ProcessInfo, error)
+}
+
+// Supervisord 通过 supervisord XML-RPC 管理进程。
+type Supervisord struct {
+	Client SupervisorClient
+}
+
+func (s Supervisord) StartPZServer() error   { return s.Client.StartPZServer() }
+func (s Supervisord) StopPZServer() error    { return s.Client.StopPZServer() }
+func (s Supervisord) RestartPZServer() error { return s.Client.RestartPZServer() }
+
+func (s Supervisord) Status() (Status, error) {
+	info, err := s.Client.Status()
+	if err != nil {
+		return Status{}, err
+	}
+	return Status{
+		Manager:       ModeSupervisord,
+		State:         supervisordState(info.State),
+		PID:           info.PID,
+		StartedAt:     info.StartedAt,
+		UptimeSeconds: info.UptimeSeconds,
+		ExitCode:      info.ExitStatus,
+		Detail:        info.State + ": " + info.Description,
+	}, nil
+}
+
+func supervisordState(name string) string {
+	switch name {
+	case "RUNNING":
+		return StateRunning
+	case "STARTING":
+		return StateStarting
+	case "STOPPING":
+		return StateStopping
+	case "STOPPED", "EXITED":
+		return StateStopped
+	case "BACKOFF", "FATAL":
+		return StateFailed
+	}
+	return StateUnknown
+}
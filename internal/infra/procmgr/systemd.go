@@ -0,0 +1,100 @@
+package procmgr
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"pz-web-backend/internal/infra/executil"
+)
+
+// DefaultSystemdUnit systemd 模式下默认的服务单元。
+const DefaultSystemdUnit = "pzserver.service"
+
+// Systemd 通过 systemctl 管理服务单元。
+type Systemd struct {
+	Runner executil.Runner
+	Unit   string
+	Now    func() time.Time
+}
+
+func (s Systemd) StartPZServer() error   { return s.ctl("start") }
+func (s Systemd) StopPZServer() error    { return s.ctl("stop") }
+func (s Systemd) RestartPZServer() error { return s.ctl("restart") }
+
+func (s Systemd) Status() (Status, error) {
+	// --timestamp=unix 输出 "@<秒>"：本地时区缩写（CEST、JST 等）无法可靠解析。
+	out, err := s.Runner.CombinedOutput("systemctl", "show", s.unit(), "--no-pager", "--timestamp=unix",
+		"--property=ActiveState,SubState,MainPID,ExecMainStatus,ActiveEnterTimestamp")
+	if err != nil {
+		return Status{}, fmt.Errorf("systemctl show %s: %w: %s", s.unit(), err, strings.TrimSpace(string(out)))
+	}
+	props := parseProperties(out)
+
+	st := Status{
+		Manager: ModeSystemd,
+		State:   systemdState(props["ActiveState"]),
+		Detail:  props["ActiveState"] + "/" + props["SubState"],
+	}
+	st.PID, _ = strconv.Atoi(props["MainPID"])
+	st.ExitCode, _ = strconv.Atoi(props["ExecMainStatus"])
+	if sec, err := strconv.ParseInt(strings.TrimPrefix(props["ActiveEnterTimestamp"], "@"), 10, 64); err == nil && sec > 0 && st.Running() {
+		t := time.Unix(sec, 0)
+		st.StartedAt = t
+		st.UptimeSeconds = int64(s.now().Sub(t) / time.Second)
+	}
+	return st, nil
+}
+
+func (s Systemd) ctl(action string) error {
+	out, err := s.Runner.CombinedOutput("systemctl", action, s.unit())
+	if err != nil {
+		return fmt.Errorf("systemctl %s %s: %w: %s", action, s.unit(), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (s Systemd) unit() string {
+	if s.Unit != "" {
+		return s.Unit
+	}
+	return DefaultSystemdUnit
+}
+
+func (s Systemd) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
+
+// parseProperties 解析 systemctl show 输出的 Key=Value 行。
+func parseProperties(out []byte) map[string]string {
+	props := make(map[string]string)
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		if k, v, ok := strings.Cut(sc.Text(), "="); ok {
+			props[k] = v
+		}
+	}
+	return props
+}
+
+func systemdState(active string) string {
+	switch active {
+	case "active", "reloading":
+		return StateRunning
+	case "activating":
+		return StateStarting
+	case "deactivating":
+		return StateStopping
+	case "inactive":
+		return StateStopped
+	case "failed":
+		return StateFailed
+	}
+	return StateUnknown
+}
@@ -0,0 +1,60 @@
+package procmgr
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeRunner struct {
+	calls [][]string
+	out   string
+	err   error
+}
+
+func (r *fakeRunner) CombinedOutput(name string, args ...string) ([]byte, error) {
+	r.calls = append(r.calls, append([]string{name}, args...))
+	return []byte(r.out), r.err
+}
+
+func TestSystemd_Actions(t *testing.T) {
+	runner := &fakeRunner{}
+	s := Systemd{Runner: runner}
+	_ = s.StartPZServer()
+	_ = s.RestartPZServer()
+	want := [][]string{{"systemctl", "start", "pzserver.service"}, {"systemctl", "restart", "pzserver.service"}}
+	if !reflect.DeepEqual(runner.calls, want) {
+		t.Fatalf("calls=%v", runner.calls)
+	}
+
+	runner.err = errors.New("exit status 5")
+	runner.out = "Failed to stop pz.service: Unit pz.service not loaded.\n"
+	err := Systemd{Runner: runner, Unit: "pz.service"}.StopPZServer()
+	if err == nil || !strings.Contains(err.Error(), "not loaded") {
+		t.Fatalf("err=%v", err)
+	}
+}
+
+func TestSystemd_Status(t *testing.T) {
+	runner := &fakeRunner{out: "ActiveState=active\nSubState=running\nMainPID=1234\nExecMainStatus=0\nActiveEnterTimestamp=@1704164645\n"}
+	now := time.Date(2024, 1, 2, 3, 5, 5, 0, time.UTC)
+	st, err := Systemd{Runner: runner, Now: func() time.Time { return now }}.Status()
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if !st.Running() || st.PID != 1234 || st.UptimeSeconds != 60 || st.Detail != "active/running" {
+		t.Fatalf("status=%+v", st)
+	}
+	if !st.StartedAt.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) || !slices.Contains(runner.calls[0], "--timestamp=unix") {
+		t.Fatalf("started=%v calls=%v", st.StartedAt, runner.calls)
+	}
+
+	runner.out = "ActiveState=failed\nSubState=failed\nMainPID=0\nExecMainStatus=1\nActiveEnterTimestamp=\n"
+	st, _ = Systemd{Runner: runner}.Status()
+	if st.State != StateFailed || st.ExitCode != 1 || !st.StartedAt.IsZero() {
+		t.Fatalf("status=%+v", st)
+	}
+}
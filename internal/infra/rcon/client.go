@@ -0,0 +1,198 @@
+// Package rcon 实现 Source RCON 协议的客户端，用于向 PZ 服务器发送管理命令。
+package rcon
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// ErrAuthFailed 密码错误，服务器以 id = -1 回复认证请求。
+var ErrAuthFailed = errors.New("rcon: authentication failed")
+
+// ErrDisabled RCONPassword 为空时服务器不会开启 RCON。
+var ErrDisabled = errors.New("RCON is disabled: RCONPassword is empty in the server config")
+
+const (
+	defaultDialTimeout = 5 * time.Second
+	defaultTimeout     = 10 * time.Second
+	// defaultSettle 响应包恰好填满 maxBodySize 时，等待后续分包的时间。
+	defaultSettle = 200 * time.Millisecond
+	// probeTimeout 复用连接前检查其是否已被服务器关闭的等待时间。
+	probeTimeout = time.Millisecond
+)
+
+// Client 维持一条已认证的 RCON 连接；连接断开时在下一次 Exec 中自动重连。
+// 可被多个 goroutine 并发使用，命令按顺序执行。
+type Client struct {
+	Addr     string
+	Password string
+
+	DialTimeout time.Duration
+	// Timeout 单条命令（含发送与读取响应）的超时。
+	Timeout time.Duration
+	Settle  time.Duration
+
+	mu   sync.Mutex
+	conn net.Conn
+	// r 包装 conn，检查连接时读到的数据不会丢失。
+	r      *bufio.Reader
+	nextID int32
+}
+
+func NewClient(addr string, password string) *Client {
+	return &Client{Addr: addr, Password: password}
+}
+
+// Exec 执行一条命令并返回完整响应（多个分包按顺序拼接）。
+// 复用的连接发送失败时重连并重试一次；命令发出后不再重试，
+// 读取超时时服务器可能已经执行了命令（quit、banuser 等不能执行两次）。认证失败不重试。
+func (c *Client) Exec(command string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	reused := c.conn != nil
+	out, sent, err := c.exec(command)
+	if err == nil || sent || errors.Is(err, ErrAuthFailed) || !reused {
+		return out, err
+	}
+	// 复用的连接可能已被服务器关闭（例如服务器重启），重连后再试一次。
+	c.closeLocked()
+	out, _, err = c.exec(command)
+	return out, err
+}
+
+// Close 关闭当前连接。
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closeLocked()
+}
+
+// exec 执行一条命令；sent 表示命令已写入连接，此后的失败不能重试。
+func (c *Client) exec(command string) (out string, sent bool, err error) {
+	if len(command) > maxBodySize-10 {
+		return "", false, fmt.Errorf("rcon: command too long (%d bytes)", len(command))
+	}
+	if err := c.connectLocked(); err != nil {
+		return "", false, err
+	}
+
+	_ = c.conn.SetDeadline(time.Now().Add(c.timeout()))
+	id := c.newID()
+	if err := WritePacket(c.conn, Packet{ID: id, Type: TypeExecCommand, Body: command}); err != nil {
+		c.closeLocked()
+		return "", false, err
+	}
+
+	var buf []byte
+	for {
+		p, err := ReadPacket(c.r)
+		if err != nil {
+			var ne net.Error
+			if len(buf) > 0 && errors.As(err, &ne) && ne.Timeout() {
+				// 最后一个分包恰好为 maxBodySize，等待后续分包超时：视为结束。
+				return string(buf), true, nil
+			}
+			c.closeLocked()
+			return "", true, err
+		}
+		if p.ID != id || p.Type != TypeResponseValue {
+			// 之前命令残留的包，忽略。
+			continue
+		}
+		buf = append(buf, p.Body...)
+		if len(p.Body) < maxBodySize {
+			return string(buf), true, nil
+		}
+		_ = c.conn.SetReadDeadline(time.Now().Add(c.settle()))
+	}
+}
+
+func (c *Client) connectLocked() error {
+	if c.conn != nil {
+		if c.aliveLocked() {
+			return nil
+		}
+		c.closeLocked()
+	}
+	conn, err := net.DialTimeout("tcp", c.Addr, c.dialTimeout())
+	if err != nil {
+		return fmt.Errorf("rcon: dial %s: %w", c.Addr, err)
+	}
+	c.conn, c.r = conn, bufio.NewReader(conn)
+
+	_ = conn.SetDeadline(time.Now().Add(c.timeout()))
+	id := c.newID()
+	if err := WritePacket(conn, Packet{ID: id, Type: TypeAuth, Body: c.Password}); err != nil {
+		c.closeLocked()
+		return err
+	}
+	// 部分服务器会先回一个空的 RESPONSE_VALUE，再回 AUTH_RESPONSE。
+	for {
+		p, err := ReadPacket(c.r)
+		if err != nil {
+			c.closeLocked()
+			return fmt.Errorf("rcon: auth: %w", err)
+		}
+		if p.Type != TypeAuthResponse {
+			continue
+		}
+		if p.ID == -1 {
+			c.closeLocked()
+			return ErrAuthFailed
+		}
+		if p.ID == id {
+			return nil
+		}
+	}
+}
+
+// aliveLocked 检查复用的连接是否已被服务器关闭（例如服务器重启），此时还没有发送命令，可以放心重连。
+func (c *Client) aliveLocked() bool {
+	_ = c.conn.SetReadDeadline(time.Now().Add(probeTimeout))
+	_, err := c.r.Peek(1)
+	var ne net.Error
+	return err == nil || (errors.As(err, &ne) && ne.Timeout())
+}
+
+func (c *Client) closeLocked() error {
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn, c.r = nil, nil
+	return err
+}
+
+func (c *Client) newID() int32 {
+	c.nextID++
+	if c.nextID <= 0 {
+		c.nextID = 1
+	}
+	return c.nextID
+}
+
+func (c *Client) dialTimeout() time.Duration {
+	if c.DialTimeout > 0 {
+		return c.DialTimeout
+	}
+	return defaultDialTimeout
+}
+
+func (c *Client) timeout() time.Duration {
+	if c.Timeout > 0 {
+		return c.Timeout
+	}
+	return defaultTimeout
+}
+
+func (c *Client) settle() time.Duration {
+	if c.Settle > 0 {
+		return c.Settle
+	}
+	return defaultSettle
+}
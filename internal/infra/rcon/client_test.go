@@ -0,0 +1,231 @@
+package rcon
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeServer 最小化的 Source RCON 服务器：响应超过 maxBodySize 时按协议拆包。
+type fakeServer struct {
+	t        *testing.T
+	ln       net.Listener
+	password string
+	handle   func(cmd string) string
+
+	mu    sync.Mutex
+	conns []net.Conn
+	auths int
+}
+
+func newFakeServer(t *testing.T, password string, handle func(cmd string) string) *fakeServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeServer{t: t, ln: ln, password: password, handle: handle}
+	go s.serve()
+	t.Cleanup(func() {
+		_ = ln.Close()
+		s.dropConnections()
+	})
+	return s
+}
+
+func (s *fakeServer) addr() string { return s.ln.Addr().String() }
+
+func (s *fakeServer) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns = append(s.conns, conn)
+		s.mu.Unlock()
+		go s.serveConn(conn)
+	}
+}
+
+func (s *fakeServer) serveConn(conn net.Conn) {
+	defer conn.Close()
+	for {
+		p, err := ReadPacket(conn)
+		if err != nil {
+			return
+		}
+		switch p.Type {
+		case TypeAuth:
+			s.mu.Lock()
+			s.auths++
+			s.mu.Unlock()
+			id := p.ID
+			if p.Body != s.password {
+				id = -1
+			}
+			_ = WritePacket(conn, Packet{ID: p.ID, Type: TypeResponseValue})
+			_ = WritePacket(conn, Packet{ID: id, Type: TypeAuthResponse})
+		case TypeExecCommand:
+			out := s.handle(p.Body)
+			if out == "<hang>" {
+				continue
+			}
+			for {
+				n := len(out)
+				if n > maxBodySize {
+					n = maxBodySize
+				}
+				_ = WritePacket(conn, Packet{ID: p.ID, Type: TypeResponseValue, Body: out[:n]})
+				out = out[n:]
+				if n < maxBodySize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// dropConnections 模拟服务器重启：断开所有已建立的连接。
+func (s *fakeServer) dropConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.conns {
+		_ = c.Close()
+	}
+	s.conns = nil
+}
+
+func (s *fakeServer) authCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.auths
+}
+
+func echo(cmd string) string { return "echo: " + cmd }
+
+func TestClient_ExecAuthenticatesOnce(t *testing.T) {
+	srv := newFakeServer(t, "secret", echo)
+	c := NewClient(srv.addr(), "secret")
+	defer c.Close()
+
+	for _, cmd := range []string{"players", "save"} {
+		out, err := c.Exec(cmd)
+		if err != nil {
+			t.Fatalf("Exec(%s): %v", cmd, err)
+		}
+		if out != "echo: "+cmd {
+			t.Fatalf("out=%q", out)
+		}
+	}
+	if srv.authCount() != 1 {
+		t.Fatalf("auths=%d", srv.authCount())
+	}
+}
+
+func TestClient_AuthFailure(t *testing.T) {
+	srv := newFakeServer(t, "secret", echo)
+	c := NewClient(srv.addr(), "wrong")
+	defer c.Close()
+
+	if _, err := c.Exec("players"); !errors.Is(err, ErrAuthFailed) {
+		t.Fatalf("err=%v", err)
+	}
+}
+
+func TestClient_MultiPacketResponse(t *testing.T) {
+	long := strings.Repeat("x", maxBodySize*2+17)
+	exact := strings.Repeat("y", maxBodySize)
+	srv := newFakeServer(t, "secret", func(cmd string) string {
+		if cmd == "exact" {
+			return exact
+		}
+		return long
+	})
+	c := NewClient(srv.addr(), "secret")
+	c.Settle = 50 * time.Millisecond
+	defer c.Close()
+
+	out, err := c.Exec("long")
+	if err != nil {
+		t.Fatalf("Exec: %v", err)
+	}
+	if out != long {
+		t.Fatalf("len=%d want %d", len(out), len(long))
+	}
+
+	out, err = c.Exec("exact")
+	if err != nil || out != exact {
+		t.Fatalf("len=%d err=%v", len(out), err)
+	}
+}
+
+func TestClient_ReconnectsAfterServerDrop(t *testing.T) {
+	srv := newFakeServer(t, "secret", echo)
+	c := NewClient(srv.addr(), "secret")
+	defer c.Close()
+
+	if _, err := c.Exec("a"); err != nil {
+		t.Fatalf("Exec: %v", err)
+	}
+	srv.dropConnections()
+
+	out, err := c.Exec("b")
+	if err != nil {
+		t.Fatalf("Exec after drop: %v", err)
+	}
+	if out != "echo: b" || srv.authCount() != 2 {
+		t.Fatalf("out=%q auths=%d", out, srv.authCount())
+	}
+}
+
+func TestClient_Timeout(t *testing.T) {
+	srv := newFakeServer(t, "secret", func(string) string { return "<hang>" })
+	c := NewClient(srv.addr(), "secret")
+	c.Timeout = 100 * time.Millisecond
+	defer c.Close()
+
+	start := time.Now()
+	_, err := c.Exec("players")
+	var ne net.Error
+	if !errors.As(err, &ne) || !ne.Timeout() {
+		t.Fatalf("err=%v", err)
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Fatalf("timeout not honoured")
+	}
+}
+
+func TestClient_DoesNotRetryAfterCommandSent(t *testing.T) {
+	var mu sync.Mutex
+	quits := 0
+	srv := newFakeServer(t, "secret", func(cmd string) string {
+		if cmd != "quit" {
+			return echo(cmd)
+		}
+		mu.Lock()
+		quits++
+		mu.Unlock()
+		return "<hang>"
+	})
+	c := NewClient(srv.addr(), "secret")
+	c.Timeout = 100 * time.Millisecond
+	defer c.Close()
+
+	if _, err := c.Exec("players"); err != nil {
+		t.Fatalf("Exec: %v", err)
+	}
+	// 复用的连接上命令已发出、读取超时：服务器可能已经执行，不能再发一次。
+	var ne net.Error
+	if _, err := c.Exec("quit"); !errors.As(err, &ne) || !ne.Timeout() {
+		t.Fatalf("err=%v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if quits != 1 {
+		t.Fatalf("quits=%d", quits)
+	}
+}
@@ -0,0 +1,60 @@
+package rcon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Source RCON 数据包类型。EXECCOMMAND 与 AUTH_RESPONSE 取值相同，按方向区分。
+const (
+	TypeResponseValue int32 = 0
+	TypeExecCommand   int32 = 2
+	TypeAuthResponse  int32 = 2
+	TypeAuth          int32 = 3
+)
+
+// maxBodySize 单个数据包的最大 body 长度；更长的响应会被服务器拆成多个包。
+const maxBodySize = 4096
+
+// Packet 一个 RCON 数据包：size(int32) id(int32) type(int32) body 0x00 0x00，均为小端。
+type Packet struct {
+	ID   int32
+	Type int32
+	Body string
+}
+
+// WritePacket 编码并写出 p。
+func WritePacket(w io.Writer, p Packet) error {
+	size := int32(4 + 4 + len(p.Body) + 2)
+	var buf bytes.Buffer
+	buf.Grow(int(size) + 4)
+	_ = binary.Write(&buf, binary.LittleEndian, size)
+	_ = binary.Write(&buf, binary.LittleEndian, p.ID)
+	_ = binary.Write(&buf, binary.LittleEndian, p.Type)
+	buf.WriteString(p.Body)
+	buf.Write([]byte{0, 0})
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// ReadPacket 读取一个完整数据包。
+func ReadPacket(r io.Reader) (Packet, error) {
+	var size int32
+	if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
+		return Packet{}, err
+	}
+	if size < 10 || size > maxBodySize+10 {
+		return Packet{}, fmt.Errorf("rcon: invalid packet size %d", size)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return Packet{}, err
+	}
+	return Packet{
+		ID:   int32(binary.LittleEndian.Uint32(data[0:4])),
+		Type: int32(binary.LittleEndian.Uint32(data[4:8])),
+		Body: string(bytes.TrimRight(data[8:], "\x00")),
+	}, nil
+}
@@ -0,0 +1,99 @@
+// Package steamcmd 构造 SteamCMD 的更新命令并解析其输出中的进度与结果。
+package steamcmd
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PZServerAppID Project Zomboid Dedicated Server 的 Steam AppID。
+const PZServerAppID = "380870"
+
+// DefaultPath 官方 Docker 镜像中 steamcmd 的位置。
+const DefaultPath = "/usr/games/steamcmd"
+
+// Options 一次 app_update 的参数。
+type Options struct {
+	Path       string
+	InstallDir string
+	AppID      string
+	// Beta 分支名，例如 "unstable"；为空表示默认分支。
+	Beta         string
+	BetaPassword string
+	Validate     bool
+}
+
+// Command 返回可执行文件路径与参数；+force_install_dir 必须在 +login 之前。
+func (o Options) Command() (string, []string) {
+	path := o.Path
+	if path == "" {
+		path = DefaultPath
+	}
+	appID := o.AppID
+	if appID == "" {
+		appID = PZServerAppID
+	}
+
+	var args []string
+	if o.InstallDir != "" {
+		args = append(args, "+force_install_dir", o.InstallDir)
+	}
+	args = append(args, "+login", "anonymous", "+app_update", appID)
+	if o.Beta != "" {
+		args = append(args, "-beta", o.Beta)
+		if o.BetaPassword != "" {
+			args = append(args, "-betapassword", o.BetaPassword)
+		}
+	}
+	if o.Validate {
+		args = append(args, "validate")
+	}
+	args = append(args, "+quit")
+	return path, args
+}
+
+// Progress 一行 "Update state (0x61) downloading, progress: 45.32 (1234 / 5678)"。
+type Progress struct {
+	State   string  `json:"state"`
+	Stage   string  `json:"stage"`
+	Percent float64 `json:"percent"`
+	Current int64   `json:"current"`
+	Total   int64   `json:"total"`
+}
+
+var progressLine = regexp.MustCompile(`Update state \((0x[0-9a-fA-F]+)\) ([^,]+), progress: ([0-9.]+) \((\d+) / (\d+)\)`)
+
+// ParseProgress 解析进度行；不是进度行时返回 false。
+func ParseProgress(line string) (Progress, bool) {
+	m := progressLine.FindStringSubmatch(line)
+	if m == nil {
+		return Progress{}, false
+	}
+	p := Progress{State: m[1], Stage: strings.TrimSpace(m[2])}
+	p.Percent, _ = strconv.ParseFloat(m[3], 64)
+	p.Current, _ = strconv.ParseInt(m[4], 10, 64)
+	p.Total, _ = strconv.ParseInt(m[5], 10, 64)
+	return p, true
+}
+
+// Result 命令输出中的最终结果。
+type Result int
+
+const (
+	ResultNone Result = iota
+	ResultSuccess
+	ResultError
+)
+
+// ParseResult 识别 "Success! App '380870' fully installed." 与 "Error! ..." / "ERROR! ..." 行。
+func ParseResult(line string) Result {
+	line = strings.TrimSpace(line)
+	switch {
+	case strings.HasPrefix(line, "Success! App"):
+		return ResultSuccess
+	case strings.HasPrefix(line, "Error! App"), strings.HasPrefix(line, "ERROR! "):
+		return ResultError
+	}
+	return ResultNone
+}
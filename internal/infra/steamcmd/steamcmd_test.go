@@ -0,0 +1,34 @@
+package steamcmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOptions_Command(t *testing.T) {
+	path, args := Options{InstallDir: "/opt/pzserver", Beta: "unstable", Validate: true}.Command()
+	want := []string{"+force_install_dir", "/opt/pzserver", "+login", "anonymous", "+app_update", "380870", "-beta", "unstable", "validate", "+quit"}
+	if path != DefaultPath || !reflect.DeepEqual(args, want) {
+		t.Fatalf("path=%q args=%q", path, args)
+	}
+}
+
+func TestParseProgressAndResult(t *testing.T) {
+	p, ok := ParseProgress(" Update state (0x61) downloading, progress: 45.32 (1234567 / 2724000)")
+	if !ok || p.Stage != "downloading" || p.Percent != 45.32 || p.Current != 1234567 || p.Total != 2724000 {
+		t.Fatalf("p=%+v ok=%v", p, ok)
+	}
+	if p, ok := ParseProgress(" Update state (0x81) verifying update, progress: 3.10 (10 / 300)"); !ok || p.Stage != "verifying update" {
+		t.Fatalf("p=%+v ok=%v", p, ok)
+	}
+	if _, ok := ParseProgress("Loading Steam API...OK"); ok {
+		t.Fatalf("unexpected progress")
+	}
+
+	if ParseResult("Success! App '380870' fully installed.") != ResultSuccess {
+		t.Fatalf("expected success")
+	}
+	if ParseResult("Error! App '380870' state is 0x202 after update job.") != ResultError {
+		t.Fatalf("expected error")
+	}
+}
@@ -0,0 +1,216 @@
+package supervisor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultURL supervisord 默认的 unix socket。
+const DefaultURL = "unix:///var/run/supervisor.sock"
+
+// DefaultProcess supervisord 配置中游戏服务器的进程名。
+const DefaultProcess = "pzserver"
+
+// Client 通过 XML-RPC 调用 supervisord，支持 unix socket 与 HTTP 端口。
+// 同时实现 Controller，操作 Process 指定的进程。
+type Client struct {
+	// URL 例如 unix:///var/run/supervisor.sock 或 http://127.0.0.1:9001/RPC2。
+	URL      string
+	Username string
+	Password string
+	Process  string
+	// Timeout 查询类调用（状态、日志）的超时；启动 / 停止由 supervisord 自身的等待时间约束。
+	Timeout time.Duration
+
+	endpoint   string
+	httpClient *http.Client
+}
+
+// NewClient 根据 URL 构造客户端；unix:// 走 socket，其余按 HTTP 地址处理。
+func NewClient(rawURL, username, password string) *Client {
+	if rawURL == "" {
+		rawURL = DefaultURL
+	}
+	c := &Client{URL: rawURL, Username: username, Password: password, Process: DefaultProcess, Timeout: 5 * time.Second}
+	if path, ok := strings.CutPrefix(rawURL, "unix://"); ok {
+		c.endpoint = "http://localhost/RPC2"
+		c.httpClient = &http.Client{Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", path)
+			},
+		}}
+		return c
+	}
+	c.endpoint = rawURL
+	if u, err := url.Parse(rawURL); err == nil && (u.Path == "" || u.Path == "/") {
+		u.Path = "/RPC2"
+		c.endpoint = u.String()
+	}
+	c.httpClient = &http.Client{}
+	return c
+}
+
+// Call 调用任意 supervisord XML-RPC 方法。
+func (c *Client) Call(ctx context.Context, method string, params ...any) (any, error) {
+	body, err := encodeCall(method, params...)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "text/xml")
+	if c.Username != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("supervisor %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("supervisor %s: %w", method, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("supervisor %s: http %d", method, resp.StatusCode)
+	}
+	return decodeResponse(data)
+}
+
+// ProcessInfo supervisor.getProcessInfo 的结果。
+type ProcessInfo struct {
+	Name  string `json:"name"`
+	Group string `json:"group"`
+	// State supervisord 状态名，例如 RUNNING、STOPPED、BACKOFF、FATAL。
+	State         string    `json:"state"`
+	PID           int       `json:"pid"`
+	StartedAt     time.Time `json:"started_at"`
+	StoppedAt     time.Time `json:"stopped_at"`
+	UptimeSeconds int64     `json:"uptime_seconds"`
+	ExitStatus    int       `json:"exit_status"`
+	SpawnErr      string    `json:"spawn_err,omitempty"`
+	Description   string    `json:"description"`
+}
+
+// Running 进程是否处于 RUNNING 状态。
+func (p ProcessInfo) Running() bool {
+	return p.State == "RUNNING"
+}
+
+func (c *Client) StartProcess(ctx context.Context, name string) error {
+	_, err := c.Call(ctx, "supervisor.startProcess", name, true)
+	return err
+}
+
+func (c *Client) StopProcess(ctx context.Context, name string) error {
+	_, err := c.Call(ctx, "supervisor.stopProcess", name, true)
+	return err
+}
+
+func (c *Client) GetProcessInfo(ctx context.Context, name string) (ProcessInfo, error) {
+	v, err := c.Call(ctx, "supervisor.getProcessInfo", name)
+	if err != nil {
+		return ProcessInfo{}, err
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return ProcessInfo{}, fmt.Errorf("supervisor.getProcessInfo: unexpected result %T", v)
+	}
+	str := func(k string) string { s, _ := m[k].(string); return s }
+	num := func(k string) int { n, _ := m[k].(int); return n }
+	unix := func(k string) time.Time {
+		if n := num(k); n > 0 {
+			return time.Unix(int64(n), 0)
+		}
+		return time.Time{}
+	}
+
+	info := ProcessInfo{
+		Name:        str("name"),
+		Group:       str("group"),
+		State:       str("statename"),
+		PID:         num("pid"),
+		StartedAt:   unix("start"),
+		StoppedAt:   unix("stop"),
+		ExitStatus:  num("exitstatus"),
+		SpawnErr:    str("spawnerr"),
+		Description: str("description"),
+	}
+	if info.Running() && num("now") >= num("start") {
+		info.UptimeSeconds = int64(num("now") - num("start"))
+	}
+	return info, nil
+}
+
+// ReadProcessStdoutLog 读取进程 stdout 日志中从 offset 起的 length 字节；offset 为负数时从末尾倒数。
+func (c *Client) ReadProcessStdoutLog(ctx context.Context, name string, offset, length int) (string, error) {
+	v, err := c.Call(ctx, "supervisor.readProcessStdoutLog", name, offset, length)
+	if err != nil {
+		return "", err
+	}
+	s, _ := v.(string)
+	return s, nil
+}
+
+// Status 返回 Process 的状态。
+func (c *Client) Status() (ProcessInfo, error) {
+	ctx, cancel := c.queryContext()
+	defer cancel()
+	return c.GetProcessInfo(ctx, c.process())
+}
+
+// StartPZServer 启动游戏服务器；已在运行时视为成功。
+func (c *Client) StartPZServer() error {
+	err := c.StartProcess(context.Background(), c.process())
+	if isFault(err, FaultAlreadyStarted) {
+		return nil
+	}
+	return err
+}
+
+// StopPZServer 停止游戏服务器；未在运行时视为成功。
+func (c *Client) StopPZServer() error {
+	err := c.StopProcess(context.Background(), c.process())
+	if isFault(err, FaultNotRunning) {
+		return nil
+	}
+	return err
+}
+
+// RestartPZServer XML-RPC 没有 restart 方法，等价于 stop + start。
+func (c *Client) RestartPZServer() error {
+	if err := c.StopPZServer(); err != nil {
+		return err
+	}
+	return c.StartPZServer()
+}
+
+func (c *Client) process() string {
+	if c.Process != "" {
+		return c.Process
+	}
+	return DefaultProcess
+}
+
+func (c *Client) queryContext() (context.Context, context.CancelFunc) {
+	if c.Timeout > 0 {
+		return context.WithTimeout(context.Background(), c.Timeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+func isFault(err error, code int) bool {
+	var f *Fault
+	return errors.As(err, &f) && f.Code == code
+}
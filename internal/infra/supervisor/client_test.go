@@ -0,0 +1,169 @@
+package supervisor
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeSupervisord 最小化的 supervisord XML-RPC 服务端。
+type fakeSupervisord struct {
+	mu      sync.Mutex
+	calls   []string
+	running bool
+}
+
+func (f *fakeSupervisord) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	var call struct {
+		Method string     `xml:"methodName"`
+		Params []xmlValue `xml:"params>param>value"`
+	}
+	if err := xml.Unmarshal(body, &call); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var args []any
+	for _, p := range call.Params {
+		v, _ := p.decode()
+		args = append(args, v)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, strings.TrimSpace(fmt.Sprintln(append([]any{call.Method}, args...)...)))
+
+	w.Header().Set("Content-Type", "text/xml")
+	switch call.Method {
+	case "supervisor.startProcess":
+		if f.running {
+			writeFault(w, FaultAlreadyStarted, "ALREADY_STARTED: pzserver")
+			return
+		}
+		f.running = true
+		writeParam(w, "<boolean>1</boolean>")
+	case "supervisor.stopProcess":
+		if !f.running {
+			writeFault(w, FaultNotRunning, "NOT_RUNNING: pzserver")
+			return
+		}
+		f.running = false
+		writeParam(w, "<boolean>1</boolean>")
+	case "supervisor.getProcessInfo":
+		writeParam(w, `<struct>
+<member><name>name</name><value><string>pzserver</string></value></member>
+<member><name>group</name><value><string>pzserver</string></value></member>
+<member><name>statename</name><value><string>RUNNING</string></value></member>
+<member><name>pid</name><value><int>4242</int></value></member>
+<member><name>start</name><value><int>1700000000</int></value></member>
+<member><name>stop</name><value><int>0</int></value></member>
+<member><name>now</name><value><int>1700000090</int></value></member>
+<member><name>exitstatus</name><value><int>0</int></value></member>
+<member><name>spawnerr</name><value><string></string></value></member>
+<member><name>description</name><value>pid 4242, uptime 0:01:30</value></member>
+</struct>`)
+	case "supervisor.readProcessStdoutLog":
+		writeParam(w, "<string>LOG  : General     &gt; Saving finished.\n</string>")
+	default:
+		writeFault(w, 1, "UNKNOWN_METHOD")
+	}
+}
+
+func writeParam(w io.Writer, value string) {
+	fmt.Fprintf(w, `<?xml version="1.0"?><methodResponse><params><param><value>%s</value></param></params></methodResponse>`, value)
+}
+
+func writeFault(w io.Writer, code int, msg string) {
+	fmt.Fprintf(w, `<?xml version="1.0"?><methodResponse><fault><value><struct>
+<member><name>faultCode</name><value><int>%d</int></value></member>
+<member><name>faultString</name><value><string>%s</string></value></member>
+</struct></value></fault></methodResponse>`, code, msg)
+}
+
+func TestClient_LifecycleOverUnixSocket(t *testing.T) {
+	sock := filepath.Join(t.TempDir(), "supervisor.sock")
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skipf("unix socket unavailable: %v", err)
+	}
+	fake := &fakeSupervisord{running: true}
+	srv := &http.Server{Handler: fake}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	c := NewClient("unix://"+sock, "", "")
+	if err := c.RestartPZServer(); err != nil {
+		t.Fatalf("restart: %v", err)
+	}
+	// 已启动 / 未运行的 fault 视为成功。
+	if err := c.StartPZServer(); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := c.StopPZServer(); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if err := c.StopPZServer(); err != nil {
+		t.Fatalf("second stop: %v", err)
+	}
+
+	want := []string{
+		"supervisor.stopProcess pzserver true",
+		"supervisor.startProcess pzserver true",
+		"supervisor.startProcess pzserver true",
+		"supervisor.stopProcess pzserver true",
+		"supervisor.stopProcess pzserver true",
+	}
+	if !reflect.DeepEqual(fake.calls, want) {
+		t.Fatalf("calls=%q", fake.calls)
+	}
+}
+
+func TestClient_StatusAndLogOverHTTP(t *testing.T) {
+	var gotPath, gotUser string
+	fake := &fakeSupervisord{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotUser, _, _ = r.BasicAuth()
+		fake.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "admin", "secret")
+	info, err := c.Status()
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if gotPath != "/RPC2" || gotUser != "admin" {
+		t.Fatalf("path=%q user=%q", gotPath, gotUser)
+	}
+	if !info.Running() || info.PID != 4242 || info.UptimeSeconds != 90 || info.StartedAt.Unix() != 1700000000 || !info.StoppedAt.IsZero() {
+		t.Fatalf("info=%+v", info)
+	}
+	if info.Description != "pid 4242, uptime 0:01:30" {
+		t.Fatalf("description=%q", info.Description)
+	}
+
+	log, err := c.ReadProcessStdoutLog(context.Background(), "pzserver", -1024, 1024)
+	if err != nil || log != "LOG  : General     > Saving finished.\n" {
+		t.Fatalf("log=%q err=%v", log, err)
+	}
+	if last := fake.calls[len(fake.calls)-1]; last != "supervisor.readProcessStdoutLog pzserver -1024 1024" {
+		t.Fatalf("call=%q", last)
+	}
+
+	_, err = c.Call(context.Background(), "supervisor.nope")
+	var fault *Fault
+	if !errors.As(err, &fault) || fault.Code != 1 {
+		t.Fatalf("err=%v", err)
+	}
+}
@@ -3,3 +3,10 @@ package supervisor
 type Restarter interface {
 	RestartPZServer() error
 }
+
+// Controller 在 Restarter 基础上支持单独停止与启动，供更新等需要停服的流程使用。
+type Controller interface {
+	Restarter
+	StopPZServer() error
+	StartPZServer() error
+}
@@ -0,0 +1,154 @@
+package supervisor
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Fault XML-RPC 调用返回的 fault。
+type Fault struct {
+	Code   int
+	String string
+}
+
+func (f *Fault) Error() string {
+	return fmt.Sprintf("xmlrpc fault %d: %s", f.Code, f.String)
+}
+
+// supervisord 常用的 fault code。
+const (
+	FaultBadName        = 10
+	FaultAlreadyStarted = 60
+	FaultNotRunning     = 70
+)
+
+// encodeCall 编码 methodCall；参数仅支持 string、int、bool。
+func encodeCall(method string, params ...any) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString("<methodCall><methodName>")
+	if err := xml.EscapeText(&buf, []byte(method)); err != nil {
+		return nil, err
+	}
+	buf.WriteString("</methodName><params>")
+	for _, p := range params {
+		buf.WriteString("<param><value>")
+		switch v := p.(type) {
+		case string:
+			buf.WriteString("<string>")
+			if err := xml.EscapeText(&buf, []byte(v)); err != nil {
+				return nil, err
+			}
+			buf.WriteString("</string>")
+		case int:
+			buf.WriteString("<int>" + strconv.Itoa(v) + "</int>")
+		case bool:
+			b := "0"
+			if v {
+				b = "1"
+			}
+			buf.WriteString("<boolean>" + b + "</boolean>")
+		default:
+			return nil, fmt.Errorf("xmlrpc: unsupported param type %T", p)
+		}
+		buf.WriteString("</value></param>")
+	}
+	buf.WriteString("</params></methodCall>")
+	return buf.Bytes(), nil
+}
+
+type xmlValue struct {
+	String  *string    `xml:"string"`
+	Int     *string    `xml:"int"`
+	I4      *string    `xml:"i4"`
+	Boolean *string    `xml:"boolean"`
+	Double  *string    `xml:"double"`
+	Base64  *string    `xml:"base64"`
+	Struct  *xmlStruct `xml:"struct"`
+	Array   *xmlArray  `xml:"array"`
+	Text    string     `xml:",chardata"`
+}
+
+type xmlStruct struct {
+	Members []xmlMember `xml:"member"`
+}
+
+type xmlMember struct {
+	Name  string   `xml:"name"`
+	Value xmlValue `xml:"value"`
+}
+
+type xmlArray struct {
+	Values []xmlValue `xml:"data>value"`
+}
+
+type methodResponse struct {
+	Params []xmlValue `xml:"params>param>value"`
+	Fault  *xmlValue  `xml:"fault>value"`
+}
+
+// decodeResponse 解析 methodResponse，返回第一个参数；fault 以 *Fault 返回。
+// 结果类型：string、int、bool、float64、[]byte、map[string]any、[]any。
+func decodeResponse(data []byte) (any, error) {
+	var resp methodResponse
+	if err := xml.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("xmlrpc: decode response: %w", err)
+	}
+	if resp.Fault != nil {
+		v, err := resp.Fault.decode()
+		if err != nil {
+			return nil, err
+		}
+		m, _ := v.(map[string]any)
+		code, _ := m["faultCode"].(int)
+		msg, _ := m["faultString"].(string)
+		return nil, &Fault{Code: code, String: msg}
+	}
+	if len(resp.Params) == 0 {
+		return nil, nil
+	}
+	return resp.Params[0].decode()
+}
+
+func (v xmlValue) decode() (any, error) {
+	switch {
+	case v.String != nil:
+		return *v.String, nil
+	case v.Int != nil:
+		return strconv.Atoi(strings.TrimSpace(*v.Int))
+	case v.I4 != nil:
+		return strconv.Atoi(strings.TrimSpace(*v.I4))
+	case v.Boolean != nil:
+		return strings.TrimSpace(*v.Boolean) == "1", nil
+	case v.Double != nil:
+		return strconv.ParseFloat(strings.TrimSpace(*v.Double), 64)
+	case v.Base64 != nil:
+		return base64.StdEncoding.DecodeString(strings.TrimSpace(*v.Base64))
+	case v.Struct != nil:
+		out := make(map[string]any, len(v.Struct.Members))
+		for _, m := range v.Struct.Members {
+			mv, err := m.Value.decode()
+			if err != nil {
+				return nil, fmt.Errorf("member %s: %w", m.Name, err)
+			}
+			out[m.Name] = mv
+		}
+		return out, nil
+	case v.Array != nil:
+		out := make([]any, 0, len(v.Array.Values))
+		for _, item := range v.Array.Values {
+			iv, err := item.decode()
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, iv)
+		}
+		return out, nil
+	}
+	// 未标注类型的值按 string 处理。
+	return v.Text, nil
+}
@@ -0,0 +1,356 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNotFound = errors.New("job not found")
+	// ErrBusy 已有独占任务在运行。
+	ErrBusy = errors.New("another server operation is running")
+	// ErrFinished 任务已结束，无法取消。
+	ErrFinished = errors.New("job already finished")
+)
+
+type State string
+
+const (
+	StateRunning   State = "running"
+	StateSucceeded State = "succeeded"
+	StateFailed    State = "failed"
+	StateCancelled State = "cancelled"
+)
+
+const (
+	defaultMaxOutput = 500
+	defaultMaxJobs   = 50
+)
+
+// Func 任务主体；ctx 在任务被取消时结束。返回 context.Canceled 视为已取消。
+type Func func(ctx context.Context, r *Reporter) error
+
+// Spec 任务的类型与互斥要求。
+type Spec struct {
+	// Kind 例如 "server.update"、"server.restart"、"panel.update"。
+	Kind string
+	// Exclusive 为 true 的任务同一时间只能运行一个（停服、重启、更新等生命周期操作）。
+	Exclusive bool
+}
+
+// Job 任务快照。
+type Job struct {
+	ID        string     `json:"id"`
+	Kind      string     `json:"kind"`
+	Exclusive bool       `json:"exclusive"`
+	State     State      `json:"state"`
+	Stage     string     `json:"stage,omitempty"`
+	Progress  any        `json:"progress,omitempty"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+	Error     string     `json:"error,omitempty"`
+	Output    []string   `json:"output"`
+}
+
+// Event 推送给订阅者的增量事件。
+type Event struct {
+	// Type 为 "log"、"stage"、"progress" 或 "done"。
+	Type     string `json:"type"`
+	Line     string `json:"line,omitempty"`
+	Stage    string `json:"stage,omitempty"`
+	Progress any    `json:"progress,omitempty"`
+	// Job 仅在 done 事件中携带最终快照。
+	Job *Job `json:"job,omitempty"`
+}
+
+// Manager 在进程内运行后台任务并保留最近的任务记录。零值可用。
+type Manager struct {
+	Now func() time.Time
+	// MaxOutput 每个任务保留的输出行数，默认 500。
+	MaxOutput int
+	// MaxJobs 保留的已结束任务数，默认 50。
+	MaxJobs int
+
+	mu        sync.Mutex
+	jobs      map[string]*entry
+	exclusive string
+}
+
+type entry struct {
+	job    Job
+	cancel context.CancelFunc
+	done   chan struct{}
+	subs   map[chan Event]struct{}
+}
+
+// Start 在后台运行 fn 并返回初始快照。
+// 独占任务冲突时返回 ErrBusy，同时返回正在运行的那个任务。
+func (m *Manager) Start(spec Spec, fn Func) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.jobs == nil {
+		m.jobs = make(map[string]*entry)
+	}
+	if spec.Exclusive && m.exclusive != "" {
+		return cloneJob(m.jobs[m.exclusive].job), ErrBusy
+	}
+
+	id, err := newID()
+	if err != nil {
+		return Job{}, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	e := &entry{
+		job: Job{
+			ID:        id,
+			Kind:      spec.Kind,
+			Exclusive: spec.Exclusive,
+			State:     StateRunning,
+			StartedAt: m.now(),
+			Output:    []string{},
+		},
+		cancel: cancel,
+		done:   make(chan struct{}),
+		subs:   make(map[chan Event]struct{}),
+	}
+	m.jobs[id] = e
+	if spec.Exclusive {
+		m.exclusive = id
+	}
+	m.pruneLocked()
+
+	go func() {
+		defer cancel()
+		err := runSafely(ctx, fn, &Reporter{m: m, e: e})
+		m.finish(ctx, e, err)
+	}()
+	return cloneJob(e.job), nil
+}
+
+// Run 运行任务并等待其结束，返回任务的错误。
+func (m *Manager) Run(spec Spec, fn Func) error {
+	job, err := m.Start(spec, fn)
+	if err != nil {
+		return err
+	}
+	job, err = m.Wait(job.ID)
+	if err != nil {
+		return err
+	}
+	if job.State != StateSucceeded {
+		return errors.New(job.Error)
+	}
+	return nil
+}
+
+// Get 返回任务快照。
+func (m *Manager) Get(id string) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.jobs[id]
+	if !ok {
+		return Job{}, ErrNotFound
+	}
+	return cloneJob(e.job), nil
+}
+
+// List 按开始时间倒序返回任务；kind 非空时只返回该类型。
+func (m *Manager) List(kind string) []Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]Job, 0, len(m.jobs))
+	for _, e := range m.jobs {
+		if kind != "" && e.job.Kind != kind {
+			continue
+		}
+		out = append(out, cloneJob(e.job))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].StartedAt.After(out[j].StartedAt) })
+	return out
+}
+
+// ExclusiveSince 报告 t 之后是否有 exceptKind 以外的独占任务运行过（包括仍在运行的），
+// 供看门狗区分人为的停服 / 重启与意外退出。
+func (m *Manager) ExclusiveSince(t time.Time, exceptKind string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.exclusive != "" && m.jobs[m.exclusive].job.Kind != exceptKind {
+		return true
+	}
+	for _, e := range m.jobs {
+		if e.job.Exclusive && e.job.Kind != exceptKind && e.job.EndedAt != nil && !e.job.EndedAt.Before(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Cancel 取消任务的 context；任务何时结束由任务自身决定。
+func (m *Manager) Cancel(id string) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.jobs[id]
+	if !ok {
+		return Job{}, ErrNotFound
+	}
+	if e.job.State != StateRunning {
+		return cloneJob(e.job), ErrFinished
+	}
+	e.cancel()
+	return cloneJob(e.job), nil
+}
+
+// Wait 阻塞到任务结束并返回最终快照。
+func (m *Manager) Wait(id string) (Job, error) {
+	m.mu.Lock()
+	e, ok := m.jobs[id]
+	m.mu.Unlock()
+	if !ok {
+		return Job{}, ErrNotFound
+	}
+	<-e.done
+	return m.Get(id)
+}
+
+// Subscribe 返回当前快照与后续事件；任务结束后 channel 关闭。
+// 订阅者处理过慢时会丢弃事件，最终状态以 done 事件中的快照为准。
+func (m *Manager) Subscribe(id string) (Job, <-chan Event, func(), error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.jobs[id]
+	if !ok {
+		return Job{}, nil, nil, ErrNotFound
+	}
+	ch := make(chan Event, 256)
+	if e.job.State != StateRunning {
+		close(ch)
+		return cloneJob(e.job), ch, func() {}, nil
+	}
+	e.subs[ch] = struct{}{}
+	unsubscribe := func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if _, ok := e.subs[ch]; ok {
+			delete(e.subs, ch)
+			close(ch)
+		}
+	}
+	return cloneJob(e.job), ch, unsubscribe, nil
+}
+
+func (m *Manager) publish(e *entry, ev Event, apply func(j *Job)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if e.job.State != StateRunning {
+		return
+	}
+	apply(&e.job)
+	for ch := range e.subs {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+func (m *Manager) finish(ctx context.Context, e *entry, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ended := m.now()
+	e.job.EndedAt = &ended
+	switch {
+	case err == nil:
+		e.job.State = StateSucceeded
+	case errors.Is(err, context.Canceled) || ctx.Err() != nil:
+		e.job.State = StateCancelled
+		e.job.Error = err.Error()
+	default:
+		e.job.State = StateFailed
+		e.job.Error = err.Error()
+	}
+	if m.exclusive == e.job.ID {
+		m.exclusive = ""
+	}
+
+	final := cloneJob(e.job)
+	for ch := range e.subs {
+		select {
+		case ch <- Event{Type: "done", Job: &final}:
+		default:
+		}
+		close(ch)
+	}
+	e.subs = nil
+	close(e.done)
+}
+
+// pruneLocked 删除最早结束的任务，使记录数不超过 MaxJobs。
+func (m *Manager) pruneLocked() {
+	limit := m.MaxJobs
+	if limit <= 0 {
+		limit = defaultMaxJobs
+	}
+	if len(m.jobs) <= limit {
+		return
+	}
+	var ended []*entry
+	for _, e := range m.jobs {
+		if e.job.State != StateRunning {
+			ended = append(ended, e)
+		}
+	}
+	sort.Slice(ended, func(i, j int) bool { return ended[i].job.StartedAt.Before(ended[j].job.StartedAt) })
+	for _, e := range ended {
+		if len(m.jobs) <= limit {
+			return
+		}
+		delete(m.jobs, e.job.ID)
+	}
+}
+
+func (m *Manager) maxOutput() int {
+	if m.MaxOutput > 0 {
+		return m.MaxOutput
+	}
+	return defaultMaxOutput
+}
+
+func (m *Manager) now() time.Time {
+	if m.Now != nil {
+		return m.Now()
+	}
+	return time.Now()
+}
+
+// runSafely 把任务中的 panic 转成错误，避免拖垮整个面板。
+func runSafely(ctx context.Context, fn Func, r *Reporter) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return fn(ctx, r)
+}
+
+func cloneJob(j Job) Job {
+	j.Output = append([]string{}, j.Output...)
+	if j.EndedAt != nil {
+		ended := *j.EndedAt
+		j.EndedAt = &ended
+	}
+	return j
+}
+
+func newID() (string, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b[:]), nil
+}
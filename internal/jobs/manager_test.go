@@ -0,0 +1,130 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestManager_ExclusiveJobsDoNotOverlap(t *testing.T) {
+	m := &Manager{}
+	release := make(chan struct{})
+	first, err := m.Start(Spec{Kind: "server.restart", Exclusive: true}, func(ctx context.Context, r *Reporter) error {
+		<-release
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	running, err := m.Start(Spec{Kind: "server.update", Exclusive: true}, func(ctx context.Context, r *Reporter) error { return nil })
+	if !errors.Is(err, ErrBusy) || running.ID != first.ID {
+		t.Fatalf("err=%v running=%+v", err, running)
+	}
+	// 非独占任务不受影响。
+	if err := m.Run(Spec{Kind: "mods.lookup"}, func(ctx context.Context, r *Reporter) error { return nil }); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	before := time.Now()
+	if !m.ExclusiveSince(before, "") {
+		t.Fatalf("running exclusive job not reported")
+	}
+	close(release)
+	if _, err := m.Wait(first.ID); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	if !m.ExclusiveSince(before, "") || m.ExclusiveSince(before, "server.restart") || m.ExclusiveSince(time.Now().Add(time.Second), "") {
+		t.Fatalf("ExclusiveSince mismatch")
+	}
+	if _, err := m.Start(Spec{Kind: "server.update", Exclusive: true}, func(ctx context.Context, r *Reporter) error { return nil }); err != nil {
+		t.Fatalf("after finish: %v", err)
+	}
+}
+
+func TestManager_SubscribeReceivesEventsAndFinalState(t *testing.T) {
+	m := &Manager{}
+	release := make(chan struct{})
+	job, _ := m.Start(Spec{Kind: "test"}, func(ctx context.Context, r *Reporter) error {
+		<-release
+		r.Stage("working")
+		r.Logf("step %d", 1)
+		r.Progress(50)
+		return errors.New("boom")
+	})
+
+	_, events, unsubscribe, err := m.Subscribe(job.ID)
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	defer unsubscribe()
+	close(release)
+
+	var types []string
+	var last Event
+	for ev := range events {
+		types = append(types, ev.Type)
+		last = ev
+	}
+	if !reflect.DeepEqual(types, []string{"stage", "log", "progress", "done"}) {
+		t.Fatalf("types=%v", types)
+	}
+	if last.Job.State != StateFailed || last.Job.Error != "boom" || last.Job.Stage != "working" || last.Job.Output[0] != "step 1" || last.Job.EndedAt == nil {
+		t.Fatalf("job=%+v", last.Job)
+	}
+}
+
+func TestManager_CancelAndPanic(t *testing.T) {
+	m := &Manager{}
+	job, _ := m.Start(Spec{Kind: "test"}, func(ctx context.Context, r *Reporter) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if _, err := m.Cancel(job.ID); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	done, _ := m.Wait(job.ID)
+	if done.State != StateCancelled {
+		t.Fatalf("job=%+v", done)
+	}
+	if _, err := m.Cancel(job.ID); !errors.Is(err, ErrFinished) {
+		t.Fatalf("err=%v", err)
+	}
+
+	err := m.Run(Spec{Kind: "test"}, func(ctx context.Context, r *Reporter) error { panic("oops") })
+	if err == nil || !strings.Contains(err.Error(), "oops") {
+		t.Fatalf("err=%v", err)
+	}
+	if _, err := m.Get("missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err=%v", err)
+	}
+}
+
+func TestManager_KeepsRecentJobsAndOutput(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := &Manager{MaxJobs: 2, MaxOutput: 2, Now: func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}}
+	for i := 0; i < 3; i++ {
+		_ = m.Run(Spec{Kind: "test"}, func(ctx context.Context, r *Reporter) error {
+			r.Log("a")
+			r.Log("b")
+			r.Log("c")
+			return nil
+		})
+	}
+	list := m.List("test")
+	if len(list) != 2 || !list[0].StartedAt.After(list[1].StartedAt) {
+		t.Fatalf("list=%+v", list)
+	}
+	if !reflect.DeepEqual(list[0].Output, []string{"b", "c"}) {
+		t.Fatalf("output=%v", list[0].Output)
+	}
+	if len(m.List("other")) != 0 {
+		t.Fatalf("kind filter ignored")
+	}
+}
@@ -0,0 +1,39 @@
+package jobs
+
+import "fmt"
+
+// Reporter 供任务主体记录输出、阶段与进度，并推送给订阅者。
+type Reporter struct {
+	m *Manager
+	e *entry
+}
+
+// ID 当前任务 ID。
+func (r *Reporter) ID() string {
+	return r.e.job.ID
+}
+
+// Log 追加一行输出。
+func (r *Reporter) Log(line string) {
+	limit := r.m.maxOutput()
+	r.m.publish(r.e, Event{Type: "log", Line: line}, func(j *Job) {
+		j.Output = append(j.Output, line)
+		if len(j.Output) > limit {
+			j.Output = j.Output[len(j.Output)-limit:]
+		}
+	})
+}
+
+func (r *Reporter) Logf(format string, args ...any) {
+	r.Log(fmt.Sprintf(format, args...))
+}
+
+// Stage 切换任务阶段（由任务自行定义，例如 "stopping"、"countdown"）。
+func (r *Reporter) Stage(stage string) {
+	r.m.publish(r.e, Event{Type: "stage", Stage: stage}, func(j *Job) { j.Stage = stage })
+}
+
+// Progress 更新任务进度；v 需可被 JSON 编码。
+func (r *Reporter) Progress(v any) {
+	r.m.publish(r.e, Event{Type: "progress", Progress: v}, func(j *Job) { j.Progress = v })
+}
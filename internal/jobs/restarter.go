@@ -0,0 +1,22 @@
+package jobs
+
+import (
+	"context"
+
+	"pz-web-backend/internal/infra/supervisor"
+)
+
+// RestartKind 直接重启服务器的任务类型。
+const RestartKind = "server.restart"
+
+// Restarter 把重启作为独占任务同步执行，避免与更新、计划重启等操作重叠。
+type Restarter struct {
+	Jobs      *Manager
+	Restarter supervisor.Restarter
+}
+
+func (r Restarter) RestartPZServer() error {
+	return r.Jobs.Run(Spec{Kind: RestartKind, Exclusive: true}, func(ctx context.Context, rep *Reporter) error {
+		return r.Restarter.RestartPZServer()
+	})
+}
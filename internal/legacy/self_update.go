@@ -1,6 +1,7 @@
 package legacy
 
 import (
+	"context"
 	"net/http"
 	"sync"
 	"time"
@@ -23,7 +24,7 @@ func CheckUpdateLegacy(githubRepo string, currentVersion string, devMode bool) (
 // Deprecated: PerformUpdateLegacy 仅用于兼容旧调用路径。
 // 新代码优先通过 internal/application/updateapp。
 func PerformUpdateLegacy(githubRepo string, currentVersion string, devMode bool, downloadUrl string) error {
-	return defaultUpdate(githubRepo, currentVersion, devMode).PerformUpdate(downloadUrl)
+	return defaultUpdate(githubRepo, currentVersion, devMode).PerformUpdate(context.Background(), downloadUrl)
 }
 
 func defaultUpdate(githubRepo string, currentVersion string, devMode bool) updateapp.Service {
@@ -10,10 +10,17 @@ import (
 )
 
 // ScanLocalMods 扫描 Steam Workshop 本地已安装模组（通过 mod.info）。
+// 游戏版本未知，同一模组有多个变体时优先使用根目录的 mod.info，见 ScanLocalModsFor。
 //
 // installDir:
 // - 为空时由调用方决定默认值（避免在此层依赖环境变量与运行目录）。
 func ScanLocalMods(installDir string) ([]ModInfo, error) {
+	return ScanLocalModsFor(installDir, GameVersion{})
+}
+
+// ScanLocalModsFor 按 ModID 与 WorkshopID 合并同一模组的各个变体（根目录、42、42.13 等），
+// 顶层字段取自 game 版本会加载的变体（见 SelectVariant），Variants 保留全部变体。
+func ScanLocalModsFor(installDir string, game GameVersion) ([]ModInfo, error) {
 	var mods []ModInfo
 
 	workshopBase := filepath.Join(installDir, "steamapps", "workshop", "content", "108600")
@@ -21,6 +28,9 @@ func ScanLocalMods(installDir string) ([]ModInfo, error) {
 		return mods, fmt.Errorf("workshop base not found: %s", workshopBase)
 	}
 
+	type key struct{ modID, workshopID string }
+	var order []key
+	variants := make(map[key][]ModInfo)
 	err := filepath.WalkDir(workshopBase, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return nil
@@ -34,22 +44,23 @@ func ScanLocalMods(installDir string) ([]ModInfo, error) {
 		if parseErr != nil {
 			return nil
 		}
+		info.Variant = extractVariant(path, workshopBase)
 
-		found := false
-		for i, existing := range mods {
-			if existing.ModID == info.ModID && existing.WorkshopID == info.WorkshopID {
-				mods[i] = info
-				found = true
-				break
-			}
-		}
-		if !found {
-			mods = append(mods, info)
+		k := key{info.ModID, info.WorkshopID}
+		if _, ok := variants[k]; !ok {
+			order = append(order, k)
 		}
-
+		variants[k] = append(variants[k], info)
 		return nil
 	})
 
+	for _, k := range order {
+		all := sortVariants(variants[k])
+		selected, ok := SelectVariant(all, game)
+		selected.Variants = all
+		selected.Unsupported = !ok
+		mods = append(mods, selected)
+	}
 	return mods, err
 }
 
@@ -65,6 +76,19 @@ func extractWorkshopID(fullPath string, basePath string) string {
 	return "?"
 }
 
+// extractVariant 路径形如 <workshopID>/mods/<模组目录>/[<变体目录>/]mod.info。
+func extractVariant(fullPath string, basePath string) string {
+	rel, err := filepath.Rel(basePath, filepath.Dir(fullPath))
+	if err != nil {
+		return VariantCommon
+	}
+	parts := strings.Split(rel, string(os.PathSeparator))
+	if len(parts) >= 3 && parts[1] == "mods" {
+		return variantOf(filepath.Join(parts[3:]...))
+	}
+	return variantOf(parts[len(parts)-1])
+}
+
 func parseModInfo(path string, wsID string) (ModInfo, error) {
 	file, err := os.Open(path)
 	if err != nil {
@@ -72,7 +96,7 @@ func parseModInfo(path string, wsID string) (ModInfo, error) {
 	}
 	defer file.Close()
 
-	info := ModInfo{WorkshopID: wsID}
+	info := ModInfo{WorkshopID: wsID, Dir: filepath.Dir(path)}
 	scanner := bufio.NewScanner(file)
 
 	for scanner.Scan() {
@@ -97,6 +121,32 @@ func parseModInfo(path string, wsID string) (ModInfo, error) {
 			info.ModID = val
 		case "description":
 			info.Description = val
+		case "author":
+			info.Author = val
+		case "modversion":
+			info.ModVersion = val
+		case "url":
+			info.URL = val
+		case "icon":
+			info.Icon = val
+		case "poster":
+			info.Posters = appendNonEmpty(info.Posters, val)
+		case "require":
+			info.Require = append(info.Require, splitModIDs(val)...)
+		case "incompatible":
+			info.Incompatible = append(info.Incompatible, splitModIDs(val)...)
+		case "loadmodafter":
+			info.LoadModAfter = append(info.LoadModAfter, splitModIDs(val)...)
+		case "loadmodbefore":
+			info.LoadModBefore = append(info.LoadModBefore, splitModIDs(val)...)
+		case "versionmin":
+			info.VersionMin = val
+		case "versionmax":
+			info.VersionMax = val
+		case "pack":
+			info.Packs = appendNonEmpty(info.Packs, val)
+		case "tiledef":
+			info.TileDefs = appendNonEmpty(info.TileDefs, val)
 		}
 	}
 
@@ -109,3 +159,22 @@ func parseModInfo(path string, wsID string) (ModInfo, error) {
 
 	return info, nil
 }
+
+// splitModIDs 解析 "A,B" 与 B42 的 "\A,\B" 写法，分号也作为分隔符。
+func splitModIDs(val string) []string {
+	var out []string
+	for _, part := range strings.FieldsFunc(val, func(r rune) bool { return r == ',' || r == ';' }) {
+		id := strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(part), "\\"))
+		if id != "" {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+func appendNonEmpty(list []string, val string) []string {
+	if val == "" {
+		return list
+	}
+	return append(list, val)
+}
@@ -3,6 +3,7 @@ package mods
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -39,3 +40,104 @@ func TestScanLocalMods_FindsAndDedupesByModIDAndWorkshopID(t *testing.T) {
 		t.Fatalf("unexpected mod: %+v", mods[0])
 	}
 }
+
+func TestParseModInfo_AllFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mod.info")
+	content := "\uFEFFname=Full\nid=full\nauthor=Someone\nmodversion=1.2\nurl=https://example.com\nicon=icon.png\n" +
+		"poster=a.png\nposter=b.png\nrequire=\\modA,\\modB\nrequire=modC\nincompatible=\\Bad\n" +
+		"loadModAfter=\\modA\nloadModBefore=\\Late; \\Later\nversionMin=42.0.0\nversionMax=42.99\n" +
+		"pack=tiles\npack=more,-flag\ntiledef=tiles 123\n// comment=ignored\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := parseModInfo(path, "1")
+	if err != nil {
+		t.Fatalf("parseModInfo: %v", err)
+	}
+	if info.Author != "Someone" || info.ModVersion != "1.2" || info.URL != "https://example.com" || info.Icon != "icon.png" ||
+		strings.Join(info.Posters, ",") != "a.png,b.png" ||
+		strings.Join(info.Require, ",") != "modA,modB,modC" ||
+		strings.Join(info.Incompatible, ",") != "Bad" ||
+		strings.Join(info.LoadModAfter, ",") != "modA" ||
+		strings.Join(info.LoadModBefore, ",") != "Late,Later" ||
+		info.VersionMin != "42.0.0" || info.VersionMax != "42.99" ||
+		strings.Join(info.Packs, "|") != "tiles|more,-flag" ||
+		strings.Join(info.TileDefs, ",") != "tiles 123" || info.Dir != filepath.Dir(path) {
+		t.Fatalf("info=%+v", info)
+	}
+}
+
+func TestScanLocalModsFor_SelectsVariantForGameVersion(t *testing.T) {
+	installDir := filepath.Join("..", "..", "testdata", "pzserver")
+	find := func(mods []ModInfo, id string) ModInfo {
+		for _, m := range mods {
+			if m.ModID == id {
+				return m
+			}
+		}
+		t.Fatalf("mod %s not found", id)
+		return ModInfo{}
+	}
+
+	b42, err := ScanLocalModsFor(installDir, GameVersion{42, 13, 1})
+	if err != nil {
+		t.Fatalf("ScanLocalModsFor: %v", err)
+	}
+	read := find(b42, "P4HasBeenRead")
+	var variants []string
+	for _, v := range read.Variants {
+		variants = append(variants, v.Variant)
+	}
+	if read.Variant != "42.13" || read.ModVersion != "3.0.1" || read.Unsupported ||
+		strings.Join(variants, ",") != "common,42,42.13" {
+		t.Fatalf("read=%+v variants=%v", read, variants)
+	}
+	spears := find(b42, "MetalSpears")
+	if spears.Variant != "42.13" || spears.ModVersion != "1.5" {
+		t.Fatalf("spears=%+v", spears)
+	}
+
+	// 42.12 加载 42 目录；MetalSpears 的 42.13 目录不适用，42.12 目录可用。
+	old42, _ := ScanLocalModsFor(installDir, GameVersion{42, 12, 0})
+	if m := find(old42, "P4HasBeenRead"); m.Variant != "42" || m.Unsupported {
+		t.Fatalf("read@42.12=%+v", m)
+	}
+	if m := find(old42, "MetalSpears"); m.Variant != "42.12" || m.Unsupported {
+		t.Fatalf("spears@42.12=%+v", m)
+	}
+
+	// B41 只读根目录。
+	b41, _ := ScanLocalModsFor(installDir, GameVersion{41, 78, 16})
+	if m := find(b41, "P4HasBeenRead"); m.Variant != VariantCommon || m.Unsupported || m.ModVersion != "" {
+		t.Fatalf("read@41=%+v", m)
+	}
+}
+
+func TestSelectVariant_VersionRange(t *testing.T) {
+	variants := []ModInfo{
+		{ModID: "a", Variant: "42", VersionMin: "42.5.0"},
+		{ModID: "a", Variant: VariantCommon},
+	}
+	if m, ok := SelectVariant(variants, GameVersion{42, 3, 0}); ok || m.Variant != "42" {
+		t.Fatalf("below min: m=%+v ok=%v", m, ok)
+	}
+	if m, ok := SelectVariant(variants, GameVersion{42, 5, 0}); !ok || m.Variant != "42" {
+		t.Fatalf("m=%+v ok=%v", m, ok)
+	}
+	if m, ok := SelectVariant(variants, GameVersion{}); !ok || m.Variant != VariantCommon {
+		t.Fatalf("unknown: m=%+v ok=%v", m, ok)
+	}
+	// 只有版本目录的模组 B41 不会加载；只有根目录的模组 B42 不会加载。
+	if _, ok := SelectVariant(variants[:1], GameVersion{41, 78, 16}); ok {
+		t.Fatalf("b42-only mod loaded on b41")
+	}
+	if _, ok := SelectVariant(variants[1:], GameVersion{42, 13, 0}); ok {
+		t.Fatalf("b41-only mod loaded on b42")
+	}
+	if v, ok := ParseGameVersion("v42.13.1 rev:1234"); !ok || v != (GameVersion{42, 13, 1}) || v.String() != "42.13.1" {
+		t.Fatalf("v=%v ok=%v", v, ok)
+	}
+	if _, ok := ParseGameVersion("forty-two"); ok {
+		t.Fatalf("expected parse failure")
+	}
+}
@@ -5,4 +5,36 @@ type ModInfo struct {
 	ModID       string `json:"mod_id"`
 	WorkshopID  string `json:"workshop_id"`
 	Description string `json:"description"`
+
+	Author     string `json:"author,omitempty"`
+	ModVersion string `json:"mod_version,omitempty"`
+	URL        string `json:"url,omitempty"`
+	Icon       string `json:"icon,omitempty"`
+	// Posters mod.info 中可以出现多个 poster=。
+	Posters []string `json:"posters,omitempty"`
+	// Require 等列表中的 ModID 已去掉 B42 写法的前导反斜杠。
+	Require       []string `json:"require,omitempty"`
+	Incompatible  []string `json:"incompatible,omitempty"`
+	LoadModAfter  []string `json:"load_mod_after,omitempty"`
+	LoadModBefore []string `json:"load_mod_before,omitempty"`
+	// VersionMin / VersionMax 支持的游戏版本范围，为空表示不限。
+	VersionMin string   `json:"version_min,omitempty"`
+	VersionMax string   `json:"version_max,omitempty"`
+	Packs      []string `json:"packs,omitempty"`
+	TileDefs   []string `json:"tile_defs,omitempty"`
+
+	// Variant mod.info 所在的变体目录：模组根目录为 "common"，B42 版本目录为 "42"、"42.13" 等。
+	Variant string `json:"variant,omitempty"`
+	// Variants 扫描结果中同一模组的全部变体；顶层字段取自会被加载的那个变体。
+	Variants []ModInfo `json:"variants,omitempty"`
+	// Unsupported 当前游戏版本不会加载任何变体（缺少对应版本目录或超出 versionMin/versionMax）。
+	Unsupported bool `json:"unsupported,omitempty"`
+
+	// TimeUpdated（Unix 秒）/ FileSize / Banned 来自 Steam，本地扫描结果中为零值。
+	TimeUpdated int64 `json:"time_updated,omitempty"`
+	FileSize    int64 `json:"file_size,omitempty"`
+	Banned      bool  `json:"banned,omitempty"`
+
+	// Dir mod.info 所在目录。
+	Dir string `json:"-"`
 }
@@ -0,0 +1,279 @@
+package mods
+
+import (
+	"sort"
+	"strings"
+)
+
+// MissingDependency 被依赖但不在结果中的模组；WorkshopID 为本地能找到的提供方，未知时为空。
+type MissingDependency struct {
+	ModID      string   `json:"mod_id"`
+	RequiredBy []string `json:"required_by"`
+	WorkshopID string   `json:"workshop_id,omitempty"`
+}
+
+// Conflict 两个同时启用、其中一方声明了 incompatible 的模组。
+type Conflict struct {
+	ModID string `json:"mod_id"`
+	With  string `json:"with"`
+}
+
+// Resolution 依赖解析结果。Mods 已按依赖与 loadModAfter / loadModBefore 排序，
+// 存在循环时循环内的模组按原顺序排在最后。
+type Resolution struct {
+	Mods              []string `json:"mods"`
+	WorkshopItems     []string `json:"workshop_items"`
+	ModsLine          string   `json:"mods_line"`
+	WorkshopItemsLine string   `json:"workshop_items_line"`
+	// Added AddMissing 时自动补上的依赖。
+	Added        []string            `json:"added"`
+	Missing      []MissingDependency `json:"missing"`
+	Incompatible []Conflict          `json:"incompatible"`
+	Cycles       [][]string          `json:"cycles"`
+	// Unknown 本地找不到 mod.info 的模组，保留在 Mods 中但无法检查其依赖。
+	Unknown []string `json:"unknown"`
+}
+
+// ResolveOptions AddMissing 为 true 时，本地已下载的依赖会（递归地）加入结果。
+type ResolveOptions struct {
+	AddMissing bool
+}
+
+// Resolve 根据本地 mod.info（catalog，通常是 ScanLocalModsFor 的结果）解析选中模组的依赖与加载顺序。
+// 同一 ModID 出现在多个创意工坊条目中时使用 catalog 中的第一个。
+func Resolve(selected []string, catalog []ModInfo, opts ResolveOptions) Resolution {
+	byID := make(map[string]ModInfo, len(catalog))
+	for _, m := range catalog {
+		if _, ok := byID[m.ModID]; !ok {
+			byID[m.ModID] = m
+		}
+	}
+
+	res := Resolution{
+		Added:        []string{},
+		Missing:      []MissingDependency{},
+		Incompatible: []Conflict{},
+		Cycles:       [][]string{},
+		Unknown:      []string{},
+	}
+
+	var order []string
+	in := make(map[string]bool)
+	add := func(id string) {
+		if id != "" && !in[id] {
+			in[id] = true
+			order = append(order, id)
+		}
+	}
+	for _, id := range selected {
+		add(normalizeModID(id))
+	}
+	if opts.AddMissing {
+		// 广度优先补全依赖的依赖。
+		for i := 0; i < len(order); i++ {
+			for _, dep := range byID[order[i]].Require {
+				if _, ok := byID[dep]; ok && !in[dep] {
+					add(dep)
+					res.Added = append(res.Added, dep)
+				}
+			}
+		}
+	}
+
+	missing := make(map[string]*MissingDependency)
+	var missingOrder []string
+	for _, id := range order {
+		info, ok := byID[id]
+		if !ok {
+			res.Unknown = append(res.Unknown, id)
+			continue
+		}
+		for _, dep := range info.Require {
+			if in[dep] {
+				continue
+			}
+			md, ok := missing[dep]
+			if !ok {
+				md = &MissingDependency{ModID: dep, WorkshopID: byID[dep].WorkshopID}
+				missing[dep] = md
+				missingOrder = append(missingOrder, dep)
+			}
+			md.RequiredBy = append(md.RequiredBy, id)
+		}
+	}
+	for _, id := range missingOrder {
+		res.Missing = append(res.Missing, *missing[id])
+	}
+
+	seen := make(map[[2]string]bool)
+	for _, id := range order {
+		for _, other := range byID[id].Incompatible {
+			if !in[other] || other == id {
+				continue
+			}
+			pair := [2]string{id, other}
+			if other < id {
+				pair = [2]string{other, id}
+			}
+			if !seen[pair] {
+				seen[pair] = true
+				res.Incompatible = append(res.Incompatible, Conflict{ModID: id, With: other})
+			}
+		}
+	}
+
+	res.Mods, res.Cycles = sortLoadOrder(order, byID)
+	res.WorkshopItems = []string{}
+	wsSeen := make(map[string]bool)
+	for _, id := range res.Mods {
+		if ws := byID[id].WorkshopID; ws != "" && !wsSeen[ws] {
+			wsSeen[ws] = true
+			res.WorkshopItems = append(res.WorkshopItems, ws)
+		}
+	}
+	res.ModsLine = strings.Join(res.Mods, ";")
+	res.WorkshopItemsLine = strings.Join(res.WorkshopItems, ";")
+	return res
+}
+
+// sortLoadOrder 拓扑排序：依赖与 loadModAfter 的目标排在前面，loadModBefore 的目标排在后面；
+// 没有约束的模组保持原顺序。
+func sortLoadOrder(order []string, byID map[string]ModInfo) ([]string, [][]string) {
+	index := make(map[string]int, len(order))
+	for i, id := range order {
+		index[id] = i
+	}
+	// after[a] 中的模组必须排在 a 之后。
+	after := make(map[string]map[string]bool)
+	indegree := make(map[string]int, len(order))
+	edge := func(first, then string) {
+		if _, ok := index[first]; !ok {
+			return
+		}
+		if _, ok := index[then]; !ok || first == then {
+			return
+		}
+		if after[first] == nil {
+			after[first] = make(map[string]bool)
+		}
+		if !after[first][then] {
+			after[first][then] = true
+			indegree[then]++
+		}
+	}
+	for _, id := range order {
+		info := byID[id]
+		for _, dep := range info.Require {
+			edge(dep, id)
+		}
+		for _, dep := range info.LoadModAfter {
+			edge(dep, id)
+		}
+		for _, next := range info.LoadModBefore {
+			edge(id, next)
+		}
+	}
+
+	sorted := make([]string, 0, len(order))
+	done := make(map[string]bool, len(order))
+	for len(sorted) < len(order) {
+		next := ""
+		for _, id := range order {
+			if !done[id] && indegree[id] == 0 {
+				next = id
+				break
+			}
+		}
+		if next == "" {
+			break
+		}
+		done[next] = true
+		sorted = append(sorted, next)
+		for then := range after[next] {
+			indegree[then]--
+		}
+	}
+	if len(sorted) == len(order) {
+		return sorted, [][]string{}
+	}
+
+	var rest []string
+	for _, id := range order {
+		if !done[id] {
+			rest = append(rest, id)
+		}
+	}
+	cycles := findCycles(rest, after, index)
+	return append(sorted, rest...), cycles
+}
+
+// findCycles 在剩余节点中找出强连通分量（Tarjan），只返回真正成环的分量。
+func findCycles(nodes []string, after map[string]map[string]bool, index map[string]int) [][]string {
+	in := make(map[string]bool, len(nodes))
+	for _, n := range nodes {
+		in[n] = true
+	}
+	var (
+		counter int
+		stack   []string
+		onStack = make(map[string]bool)
+		low     = make(map[string]int)
+		num     = make(map[string]int)
+		cycles  = [][]string{}
+	)
+	var visit func(v string)
+	visit = func(v string) {
+		counter++
+		num[v], low[v] = counter, counter
+		stack = append(stack, v)
+		onStack[v] = true
+		for _, w := range sortedKeys(after[v], index) {
+			if !in[w] {
+				continue
+			}
+			if num[w] == 0 {
+				visit(w)
+				low[v] = min(low[v], low[w])
+			} else if onStack[w] {
+				low[v] = min(low[v], num[w])
+			}
+		}
+		if low[v] != num[v] {
+			return
+		}
+		var comp []string
+		for {
+			w := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[w] = false
+			comp = append(comp, w)
+			if w == v {
+				break
+			}
+		}
+		if len(comp) > 1 {
+			sort.Slice(comp, func(i, j int) bool { return index[comp[i]] < index[comp[j]] })
+			cycles = append(cycles, comp)
+		}
+	}
+	for _, n := range nodes {
+		if num[n] == 0 {
+			visit(n)
+		}
+	}
+	return cycles
+}
+
+func sortedKeys(set map[string]bool, index map[string]int) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return index[keys[i]] < index[keys[j]] })
+	return keys
+}
+
+// normalizeModID 去掉空白与 B42 写法的前导反斜杠。
+func normalizeModID(id string) string {
+	return strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(id), `\`))
+}
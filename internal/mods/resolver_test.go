@@ -0,0 +1,64 @@
+package mods
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func resolverCatalog() []ModInfo {
+	return []ModInfo{
+		{ModID: "tsarslib", WorkshopID: "100"},
+		{ModID: "modoptions", WorkshopID: "200"},
+		{ModID: "vehicles", WorkshopID: "300", Require: []string{"tsarslib", "modoptions"}},
+		{ModID: "vehicles_extra", WorkshopID: "300", Require: []string{"vehicles"}, LoadModAfter: []string{"ui"}},
+		{ModID: "ui", WorkshopID: "400", LoadModBefore: []string{"hud"}},
+		{ModID: "hud", WorkshopID: "500", Incompatible: []string{"ui_old"}},
+		{ModID: "ui_old", WorkshopID: "600"},
+		{ModID: "loopA", WorkshopID: "700", Require: []string{"loopB"}},
+		{ModID: "loopB", WorkshopID: "700", LoadModAfter: []string{"loopA"}},
+	}
+}
+
+func TestResolve_SortsAndReportsMissing(t *testing.T) {
+	res := Resolve([]string{"hud", "vehicles_extra", `\ui`, "vehicles", "ghost", "hud"}, resolverCatalog(), ResolveOptions{})
+
+	// hud 需要在 ui 之后；vehicles 在 vehicles_extra 之前；ghost 未知但保留。
+	if res.ModsLine != "ui;hud;vehicles;vehicles_extra;ghost" {
+		t.Fatalf("mods=%s", res.ModsLine)
+	}
+	if res.WorkshopItemsLine != "400;500;300" {
+		t.Fatalf("workshop=%s", res.WorkshopItemsLine)
+	}
+	want := []MissingDependency{
+		{ModID: "tsarslib", RequiredBy: []string{"vehicles"}, WorkshopID: "100"},
+		{ModID: "modoptions", RequiredBy: []string{"vehicles"}, WorkshopID: "200"},
+	}
+	if !reflect.DeepEqual(res.Missing, want) {
+		t.Fatalf("missing=%+v", res.Missing)
+	}
+	if !reflect.DeepEqual(res.Unknown, []string{"ghost"}) || len(res.Cycles) != 0 || len(res.Added) != 0 {
+		t.Fatalf("res=%+v", res)
+	}
+}
+
+func TestResolve_AddMissingConflictsAndCycles(t *testing.T) {
+	res := Resolve([]string{"vehicles_extra", "hud", "ui_old", "loopA"}, resolverCatalog(), ResolveOptions{AddMissing: true})
+
+	if strings.Join(res.Added, ",") != "vehicles,loopB,tsarslib,modoptions" || len(res.Missing) != 0 {
+		t.Fatalf("added=%v missing=%+v", res.Added, res.Missing)
+	}
+	if !reflect.DeepEqual(res.Incompatible, []Conflict{{ModID: "hud", With: "ui_old"}}) {
+		t.Fatalf("incompatible=%+v", res.Incompatible)
+	}
+	if !reflect.DeepEqual(res.Cycles, [][]string{{"loopA", "loopB"}}) {
+		t.Fatalf("cycles=%v", res.Cycles)
+	}
+	// 循环中的模组按原顺序排在最后，结果仍包含全部模组。
+	if res.ModsLine != "hud;ui_old;tsarslib;modoptions;vehicles;vehicles_extra;loopA;loopB" {
+		t.Fatalf("mods=%s", res.ModsLine)
+	}
+	if res.WorkshopItemsLine != "500;600;100;200;300;700" {
+		t.Fatalf("workshop=%s", res.WorkshopItemsLine)
+	}
+}
@@ -0,0 +1,148 @@
+package mods
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// VariantCommon 模组根目录下的 mod.info（B41 读取的位置）。
+const VariantCommon = "common"
+
+var variantDir = regexp.MustCompile(`^\d+(\.\d+){0,2}$`)
+
+// GameVersion 游戏版本，如 41.78.16、42.13.1；零值表示未知。
+type GameVersion struct {
+	Major, Minor, Patch int
+}
+
+// ParseGameVersion 接受 1 到 3 段数字，允许前缀 "v" 与 "42.13.1 rev..." 这类后缀。
+func ParseGameVersion(s string) (GameVersion, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexAny(s, " -_"); i >= 0 {
+		s = s[:i]
+	}
+	parts := strings.Split(s, ".")
+	if s == "" || len(parts) > 3 {
+		return GameVersion{}, false
+	}
+	var nums [3]int
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return GameVersion{}, false
+		}
+		nums[i] = n
+	}
+	return GameVersion{Major: nums[0], Minor: nums[1], Patch: nums[2]}, true
+}
+
+func (v GameVersion) IsZero() bool {
+	return v == GameVersion{}
+}
+
+func (v GameVersion) String() string {
+	if v.IsZero() {
+		return ""
+	}
+	return strconv.Itoa(v.Major) + "." + strconv.Itoa(v.Minor) + "." + strconv.Itoa(v.Patch)
+}
+
+// Compare 返回 -1、0、1。
+func (v GameVersion) Compare(o GameVersion) int {
+	for _, d := range [][2]int{{v.Major, o.Major}, {v.Minor, o.Minor}, {v.Patch, o.Patch}} {
+		if d[0] != d[1] {
+			if d[0] < d[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// SelectVariant 返回游戏会加载的变体。
+//   - 版本未知：优先根目录，没有时取最高版本目录。
+//   - B41：只读取根目录。
+//   - B42 及以后：读取不高于游戏版本的最高版本目录，不读取根目录。
+//
+// ok=false 表示该版本不会加载这个模组；此时返回的仍是最接近的变体，便于展示。
+// 选中的变体声明了 versionMin / versionMax 且游戏版本不在范围内时同样返回 false。
+func SelectVariant(variants []ModInfo, game GameVersion) (ModInfo, bool) {
+	if len(variants) == 0 {
+		return ModInfo{}, false
+	}
+	sorted := sortVariants(variants)
+	var common *ModInfo
+	var versioned []ModInfo
+	for i := range sorted {
+		if sorted[i].Variant == VariantCommon {
+			common = &sorted[i]
+		} else {
+			versioned = append(versioned, sorted[i])
+		}
+	}
+
+	switch {
+	case game.IsZero():
+		if common != nil {
+			return *common, true
+		}
+		return versioned[len(versioned)-1], true
+	case game.Major < 42:
+		if common != nil {
+			return *common, inRange(*common, game)
+		}
+		return versioned[0], false
+	}
+	for i := len(versioned) - 1; i >= 0; i-- {
+		v, _ := ParseGameVersion(versioned[i].Variant)
+		// 目录名未写出的段按 0 比较，"42" 因此适用于所有 42.x。
+		if v.Compare(game) <= 0 {
+			return versioned[i], inRange(versioned[i], game)
+		}
+	}
+	if common != nil {
+		return *common, false
+	}
+	return versioned[0], false
+}
+
+func inRange(m ModInfo, game GameVersion) bool {
+	if min, ok := ParseGameVersion(m.VersionMin); ok && game.Compare(min) < 0 {
+		return false
+	}
+	if max, ok := ParseGameVersion(m.VersionMax); ok && game.Compare(max) > 0 {
+		return false
+	}
+	return true
+}
+
+// sortVariants 根目录在前，版本目录按版本升序。
+func sortVariants(variants []ModInfo) []ModInfo {
+	out := append([]ModInfo(nil), variants...)
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Variant == VariantCommon || out[j].Variant == VariantCommon {
+			return out[i].Variant == VariantCommon && out[j].Variant != VariantCommon
+		}
+		vi, _ := ParseGameVersion(out[i].Variant)
+		vj, _ := ParseGameVersion(out[j].Variant)
+		if c := vi.Compare(vj); c != 0 {
+			return c < 0
+		}
+		return out[i].Variant < out[j].Variant
+	})
+	return out
+}
+
+// variantOf 由 mod.info 相对模组根目录的路径得出变体名。
+func variantOf(relDir string) string {
+	if relDir == "" || relDir == "." {
+		return VariantCommon
+	}
+	if variantDir.MatchString(relDir) {
+		return relDir
+	}
+	return VariantCommon
+}
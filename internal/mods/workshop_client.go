@@ -2,11 +2,13 @@ package mods
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 )
@@ -14,11 +16,19 @@ import (
 type WorkshopClient struct {
 	httpClient *http.Client
 	apiURL     string
-	cache      CacheStore
+	// collectionURL GetCollectionDetails 地址，由 apiURL 推出；apiURL 不是 Steam 的默认地址时两者相同。
+	collectionURL string
+	cache         CacheStore
 
 	mu    sync.RWMutex
 	mem   map[string]ModInfo
 	dirty bool
+	// saveErr 最近一次写盘的结果。
+	saveErr error
+	// saveMu 保证同一时间只有一次写盘，避免并发写坏缓存文件。
+	saveMu sync.Mutex
+	// saving 后台写盘，测试中等待其完成。
+	saving sync.WaitGroup
 }
 
 func NewWorkshopClient(httpClient *http.Client, apiURL string, cache CacheStore) (*WorkshopClient, error) {
@@ -30,10 +40,11 @@ func NewWorkshopClient(httpClient *http.Client, apiURL string, cache CacheStore)
 	}
 
 	c := &WorkshopClient{
-		httpClient: httpClient,
-		apiURL:     apiURL,
-		cache:      cache,
-		mem:        make(map[string]ModInfo),
+		httpClient:    httpClient,
+		apiURL:        apiURL,
+		collectionURL: strings.Replace(apiURL, "GetPublishedFileDetails", "GetCollectionDetails", 1),
+		cache:         cache,
+		mem:           make(map[string]ModInfo),
 	}
 
 	if cache != nil {
@@ -44,91 +55,237 @@ func NewWorkshopClient(httpClient *http.Client, apiURL string, cache CacheStore)
 	return c, nil
 }
 
+const (
+	// workshopBatchSize GetPublishedFileDetails 单次请求的条目上限。
+	workshopBatchSize = 100
+	// workshopConcurrency 同时进行的批量请求数。
+	workshopConcurrency = 4
+)
+
+// ErrWorkshopItemNotFound 条目不存在、已删除或不公开。
+var ErrWorkshopItemNotFound = errors.New("mod not found")
+
 type steamResponse struct {
 	Response struct {
 		ResultCount          int `json:"resultcount"`
 		PublishedFileDetails []struct {
-			PublishedFileID string `json:"publishedfileid"`
-			Title           string `json:"title"`
-			Description     string `json:"description"`
+			PublishedFileID string    `json:"publishedfileid"`
+			Result          int       `json:"result"`
+			Title           string    `json:"title"`
+			Description     string    `json:"description"`
+			TimeUpdated     steamInt  `json:"time_updated"`
+			FileSize        steamInt  `json:"file_size"`
+			Banned          steamBool `json:"banned"`
 		} `json:"publishedfiledetails"`
 	} `json:"response"`
 }
 
-func (c *WorkshopClient) FetchWorkshopInfo(workshopID string) (ModInfo, error) {
-	c.mu.RLock()
-	if info, ok := c.mem[workshopID]; ok {
-		c.mu.RUnlock()
-		return info, nil
+// steamInt Steam 的数值字段有时以字符串返回（如 file_size）。
+type steamInt int64
+
+func (n *steamInt) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "" || s == "null" {
+		*n = 0
+		return nil
 	}
-	c.mu.RUnlock()
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return err
+	}
+	*n = steamInt(v)
+	return nil
+}
 
-	data := url.Values{}
-	data.Set("itemcount", "1")
-	data.Set("publishedfileids[0]", workshopID)
+// steamBool banned 等字段为 0/1 或 true/false。
+type steamBool bool
 
-	resp, err := c.httpClient.Post(c.apiURL, "application/x-www-form-urlencoded", strings.NewReader(data.Encode()))
-	if err != nil {
-		return ModInfo{}, err
+func (b *steamBool) UnmarshalJSON(data []byte) error {
+	switch strings.Trim(string(data), `"`) {
+	case "1", "true":
+		*b = true
+	default:
+		*b = false
 	}
-	defer resp.Body.Close()
+	return nil
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
+func (c *WorkshopClient) FetchWorkshopInfo(workshopID string) (ModInfo, error) {
+	infos, errs := c.FetchWorkshopInfos([]string{workshopID})
+	if err := errs[workshopID]; err != nil {
 		return ModInfo{}, err
 	}
+	return infos[workshopID], nil
+}
 
-	var steamResp steamResponse
-	if err := json.Unmarshal(body, &steamResp); err != nil {
-		return ModInfo{}, err
+// FetchWorkshopInfos 批量查询；未命中缓存的 ID 每 workshopBatchSize 个一批，最多 workshopConcurrency 批并发。
+// 每个 ID 要么出现在 infos 中，要么出现在 errs 中。
+func (c *WorkshopClient) FetchWorkshopInfos(workshopIDs []string) (map[string]ModInfo, map[string]error) {
+	return c.fetchInfos(workshopIDs, true)
+}
+
+// RefreshWorkshopInfos 与 FetchWorkshopInfos 相同，但忽略缓存、总是向 Steam 查询最新的 time_updated 等信息。
+func (c *WorkshopClient) RefreshWorkshopInfos(workshopIDs []string) (map[string]ModInfo, map[string]error) {
+	return c.fetchInfos(workshopIDs, false)
+}
+
+func (c *WorkshopClient) fetchInfos(workshopIDs []string, useCache bool) (map[string]ModInfo, map[string]error) {
+	infos := make(map[string]ModInfo, len(workshopIDs))
+	errs := make(map[string]error)
+
+	var pending []string
+	seen := make(map[string]bool, len(workshopIDs))
+	c.mu.RLock()
+	for _, id := range workshopIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if info, ok := c.mem[id]; ok && useCache {
+			infos[id] = info
+		} else {
+			pending = append(pending, id)
+		}
 	}
+	c.mu.RUnlock()
+	if len(pending) == 0 {
+		return infos, errs
+	}
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, workshopConcurrency)
+	)
+	for start := 0; start < len(pending); start += workshopBatchSize {
+		batch := pending[start:min(start+workshopBatchSize, len(pending))]
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			found, err := c.fetchBatch(batch)
+			mu.Lock()
+			defer mu.Unlock()
+			for _, id := range batch {
+				switch info, ok := found[id]; {
+				case err != nil:
+					errs[id] = err
+				case ok:
+					infos[id] = info
+				default:
+					errs[id] = ErrWorkshopItemNotFound
+				}
+			}
+		}()
+	}
+	wg.Wait()
 
-	if steamResp.Response.ResultCount <= 0 || len(steamResp.Response.PublishedFileDetails) == 0 {
-		return ModInfo{}, fmt.Errorf("mod not found")
+	if c.cache != nil && len(errs) < len(pending) {
+		// 写盘失败不影响本次查询结果，记录在 SaveError 中，下次查到新条目时重试。
+		c.saving.Add(1)
+		go func() {
+			defer c.saving.Done()
+			err := c.flush()
+			c.mu.Lock()
+			c.saveErr = err
+			c.mu.Unlock()
+		}()
+	}
+	return infos, errs
+}
+
+// fetchBatch 一次请求查询一批条目，返回查到的条目并写入内存缓存。
+func (c *WorkshopClient) fetchBatch(ids []string) (map[string]ModInfo, error) {
+	data := url.Values{}
+	data.Set("itemcount", strconv.Itoa(len(ids)))
+	for i, id := range ids {
+		data.Set(fmt.Sprintf("publishedfileids[%d]", i), id)
 	}
 
-	details := steamResp.Response.PublishedFileDetails[0]
-	modID := extractModID(details.Description)
-	if modID == "" {
-		modID = "?"
+	var steamResp steamResponse
+	if err := c.post(c.apiURL, data, &steamResp); err != nil {
+		return nil, err
 	}
 
-	info := ModInfo{
-		Name:        details.Title,
-		WorkshopID:  workshopID,
-		ModID:       modID,
-		Description: details.Description,
+	found := make(map[string]ModInfo, len(ids))
+	for _, details := range steamResp.Response.PublishedFileDetails {
+		// result 缺省时按成功处理（旧的响应格式只有 resultcount）。
+		if details.Result != 0 && details.Result != 1 {
+			continue
+		}
+		modID := extractModID(details.Description)
+		if modID == "" {
+			modID = "?"
+		}
+		found[details.PublishedFileID] = ModInfo{
+			Name:        details.Title,
+			WorkshopID:  details.PublishedFileID,
+			ModID:       modID,
+			Description: details.Description,
+			TimeUpdated: int64(details.TimeUpdated),
+			FileSize:    int64(details.FileSize),
+			Banned:      bool(details.Banned),
+		}
 	}
 
 	c.mu.Lock()
-	c.mem[workshopID] = info
-	c.dirty = true
+	for id, info := range found {
+		c.mem[id] = info
+		c.dirty = true
+	}
 	c.mu.Unlock()
+	return found, nil
+}
 
-	if c.cache != nil {
-		go c.flush()
+// post 以表单提交 Steam Web API 并解析 JSON 响应。
+func (c *WorkshopClient) post(apiURL string, data url.Values, out any) error {
+	resp, err := c.httpClient.Post(apiURL, "application/x-www-form-urlencoded", strings.NewReader(data.Encode()))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("steam api: %s", resp.Status)
 	}
 
-	return info, nil
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, out)
 }
 
-func (c *WorkshopClient) flush() {
+// SaveError 返回最近一次缓存写盘的错误，成功或尚未写盘时为 nil。
+func (c *WorkshopClient) SaveError() error {
 	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.saveErr
+}
+
+// flush 把内存缓存写入 CacheStore；失败时保留 dirty，下次再写。
+func (c *WorkshopClient) flush() error {
+	c.saveMu.Lock()
+	defer c.saveMu.Unlock()
+
+	c.mu.Lock()
 	if !c.dirty {
-		c.mu.RUnlock()
-		return
+		c.mu.Unlock()
+		return nil
 	}
 	snapshot := make(map[string]ModInfo, len(c.mem))
 	for k, v := range c.mem {
 		snapshot[k] = v
 	}
-	c.mu.RUnlock()
-
-	_ = c.cache.Save(snapshot)
-
-	c.mu.Lock()
 	c.dirty = false
 	c.mu.Unlock()
+
+	if err := c.cache.Save(snapshot); err != nil {
+		c.mu.Lock()
+		c.dirty = true
+		c.mu.Unlock()
+		return fmt.Errorf("save workshop cache: %w", err)
+	}
+	return nil
 }
 
 func extractModID(desc string) string {
@@ -1,9 +1,14 @@
 package mods
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -52,19 +57,50 @@ func TestWorkshopClient_FetchAndCache(t *testing.T) {
 	if err != nil {
 		t.Fatalf("FetchWorkshopInfo2: %v", err)
 	}
-	if info2 != info {
+	if !reflect.DeepEqual(info2, info) {
 		t.Fatalf("cache mismatch: %+v vs %+v", info2, info)
 	}
 
-	// flush 为异步；这里不强依赖，但至少在内存 store 中最终可见（best-effort）
-	deadline := time.Now().Add(300 * time.Millisecond)
-	for time.Now().Before(deadline) {
-		if v, ok := store.m["999"]; ok && v.ModID == "X" {
-			return
-		}
-		time.Sleep(10 * time.Millisecond)
+	c.saving.Wait()
+	if v, ok := store.m["999"]; !ok || v.ModID != "X" {
+		t.Fatalf("expected store saved, got=%v", store.m)
+	}
+}
+
+type failingStore struct{ saves int }
+
+func (s *failingStore) Load() (map[string]ModInfo, error) { return nil, nil }
+func (s *failingStore) Save(map[string]ModInfo) error {
+	s.saves++
+	return errors.New("disk full")
+}
+
+func TestWorkshopClient_SaveErrorKeptOnClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		id := r.Form.Get("publishedfileids[0]")
+		w.Write([]byte(`{"response":{"publishedfiledetails":[{"publishedfileid":"` + id + `","result":1,"title":"T"}]}}`))
+	}))
+	defer srv.Close()
+
+	store := &failingStore{}
+	c, _ := NewWorkshopClient(srv.Client(), srv.URL, store)
+
+	if _, err := c.FetchWorkshopInfo("1"); err != nil {
+		t.Fatalf("FetchWorkshopInfo: %v", err)
+	}
+	c.saving.Wait()
+	if err := c.SaveError(); err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Fatalf("SaveError=%v", err)
+	}
+	// 写盘失败后保留 dirty，下一次查到新条目时连同旧条目一起重试。
+	if _, err := c.FetchWorkshopInfo("2"); err != nil {
+		t.Fatalf("FetchWorkshopInfo: %v", err)
+	}
+	c.saving.Wait()
+	if store.saves != 2 {
+		t.Fatalf("saves=%d", store.saves)
 	}
-	t.Fatalf("expected store saved, got=%v", store.m)
 }
 
 func TestWorkshopClient_ModNotFound(t *testing.T) {
@@ -83,3 +119,54 @@ func TestWorkshopClient_ModNotFound(t *testing.T) {
 		t.Fatalf("err=%v", err)
 	}
 }
+
+func TestWorkshopClient_FetchWorkshopInfos_Batches(t *testing.T) {
+	var requests, maxCount atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		requests.Add(1)
+		n, _ := strconv.Atoi(r.Form.Get("itemcount"))
+		if int32(n) > maxCount.Load() {
+			maxCount.Store(int32(n))
+		}
+		var items []string
+		for i := 0; i < n; i++ {
+			id := r.Form.Get(fmt.Sprintf("publishedfileids[%d]", i))
+			if id == "7" {
+				items = append(items, `{"publishedfileid":"7","result":9}`)
+				continue
+			}
+			items = append(items, `{"publishedfileid":"`+id+`","result":1,"title":"T`+id+`","description":"Mod ID: m`+id+`","time_updated":1700000000,"file_size":"2048","banned":0}`)
+		}
+		w.Write([]byte(`{"response":{"resultcount":` + strconv.Itoa(n) + `,"publishedfiledetails":[` + strings.Join(items, ",") + `]}}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewWorkshopClient(http.DefaultClient, srv.URL, nil)
+	if err != nil {
+		t.Fatalf("NewWorkshopClient: %v", err)
+	}
+	ids := make([]string, 0, 150)
+	for i := 1; i <= 150; i++ {
+		ids = append(ids, strconv.Itoa(i))
+	}
+	infos, errs := c.FetchWorkshopInfos(ids)
+	if len(infos) != 149 || len(errs) != 1 || errs["7"] != ErrWorkshopItemNotFound {
+		t.Fatalf("infos=%d errs=%v", len(infos), errs)
+	}
+	if got := infos["150"]; got.ModID != "m150" || got.Name != "T150" || got.TimeUpdated != 1700000000 || got.FileSize != 2048 || got.Banned {
+		t.Fatalf("info=%+v", infos["150"])
+	}
+	if requests.Load() != 2 || maxCount.Load() != workshopBatchSize {
+		t.Fatalf("requests=%d max=%d", requests.Load(), maxCount.Load())
+	}
+
+	// 已缓存的条目不再请求。
+	if _, errs := c.FetchWorkshopInfos([]string{"1", "150"}); len(errs) != 0 || requests.Load() != 2 {
+		t.Fatalf("errs=%v requests=%d", errs, requests.Load())
+	}
+	// Refresh 忽略缓存。
+	if _, errs := c.RefreshWorkshopInfos([]string{"1", "150"}); len(errs) != 0 || requests.Load() != 3 {
+		t.Fatalf("errs=%v requests=%d", errs, requests.Load())
+	}
+}
@@ -0,0 +1,144 @@
+package mods
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+// ErrCollectionNotFound 合集不存在、不公开，或该 ID 不是合集。
+var ErrCollectionNotFound = errors.New("collection not found")
+
+// Steam 合集子项的 filetype。
+const (
+	collectionChildItem       = 0
+	collectionChildCollection = 2
+)
+
+// CollectionContents 展开后的合集。Items 按合集内的顺序去重，子合集的条目展开在其所在位置。
+type CollectionContents struct {
+	ID    string   `json:"id"`
+	Items []string `json:"items"`
+	// Collections 展开过的子合集（不含自身）。
+	Collections []string `json:"collections"`
+	// Unavailable 查询失败的子合集，其中的条目没有包含在 Items 中。
+	Unavailable []string `json:"unavailable"`
+}
+
+type collectionResponse struct {
+	Response struct {
+		CollectionDetails []struct {
+			PublishedFileID string `json:"publishedfileid"`
+			Result          int    `json:"result"`
+			Children        []struct {
+				PublishedFileID string `json:"publishedfileid"`
+				SortOrder       int    `json:"sortorder"`
+				FileType        int    `json:"filetype"`
+			} `json:"children"`
+		} `json:"collectiondetails"`
+	} `json:"response"`
+}
+
+type collectionChild struct {
+	id         string
+	collection bool
+}
+
+// FetchCollection 通过 GetCollectionDetails 展开合集（含子合集），同一层的子合集合并为一次请求。
+func (c *WorkshopClient) FetchCollection(collectionID string) (CollectionContents, error) {
+	children := make(map[string][]collectionChild)
+	visited := map[string]bool{collectionID: true}
+	failed := make(map[string]bool)
+	level := []string{collectionID}
+	for len(level) > 0 {
+		got, err := c.fetchCollections(level)
+		if err != nil {
+			return CollectionContents{}, err
+		}
+		var next []string
+		for _, id := range level {
+			kids, ok := got[id]
+			if !ok {
+				failed[id] = true
+				continue
+			}
+			children[id] = kids
+			for _, k := range kids {
+				if k.collection && !visited[k.id] {
+					visited[k.id] = true
+					next = append(next, k.id)
+				}
+			}
+		}
+		level = next
+	}
+	if failed[collectionID] {
+		return CollectionContents{}, ErrCollectionNotFound
+	}
+
+	out := CollectionContents{ID: collectionID, Items: []string{}, Collections: []string{}, Unavailable: []string{}}
+	seenItem := make(map[string]bool)
+	expanded := map[string]bool{collectionID: true}
+	var walk func(id string)
+	walk = func(id string) {
+		for _, k := range children[id] {
+			switch {
+			case !k.collection:
+				if !seenItem[k.id] {
+					seenItem[k.id] = true
+					out.Items = append(out.Items, k.id)
+				}
+			case expanded[k.id]:
+			case failed[k.id]:
+				expanded[k.id] = true
+				out.Unavailable = append(out.Unavailable, k.id)
+			default:
+				expanded[k.id] = true
+				out.Collections = append(out.Collections, k.id)
+				walk(k.id)
+			}
+		}
+	}
+	walk(collectionID)
+	if len(out.Items) == 0 && len(out.Collections) == 0 && len(out.Unavailable) == 0 {
+		// 普通条目查询合集详情时同样返回成功但没有子项。
+		return CollectionContents{}, ErrCollectionNotFound
+	}
+	return out, nil
+}
+
+// fetchCollections 一次请求查询多个合集，返回成功的合集按 sortorder 排好的子项。
+func (c *WorkshopClient) fetchCollections(ids []string) (map[string][]collectionChild, error) {
+	data := url.Values{}
+	data.Set("collectioncount", strconv.Itoa(len(ids)))
+	for i, id := range ids {
+		data.Set(fmt.Sprintf("publishedfileids[%d]", i), id)
+	}
+
+	var resp collectionResponse
+	if err := c.post(c.collectionURL, data, &resp); err != nil {
+		return nil, err
+	}
+
+	out := make(map[string][]collectionChild, len(ids))
+	for _, d := range resp.Response.CollectionDetails {
+		if d.Result != 1 {
+			continue
+		}
+		kids := d.Children
+		sort.SliceStable(kids, func(i, j int) bool { return kids[i].SortOrder < kids[j].SortOrder })
+		list := make([]collectionChild, 0, len(kids))
+		for _, k := range kids {
+			switch k.FileType {
+			case collectionChildItem:
+				list = append(list, collectionChild{id: k.PublishedFileID})
+			case collectionChildCollection:
+				list = append(list, collectionChild{id: k.PublishedFileID, collection: true})
+			}
+		}
+		out[d.PublishedFileID] = list
+	}
+	return out, nil
+}
@@ -0,0 +1,58 @@
+package mods
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestWorkshopClient_FetchCollection(t *testing.T) {
+	details := map[string]string{
+		"100": `{"publishedfileid":"100","result":1,"children":[` +
+			`{"publishedfileid":"3","sortorder":2,"filetype":0},` +
+			`{"publishedfileid":"200","sortorder":1,"filetype":2},` +
+			`{"publishedfileid":"1","sortorder":0,"filetype":0},` +
+			`{"publishedfileid":"300","sortorder":3,"filetype":2}]}`,
+		"200": `{"publishedfileid":"200","result":1,"children":[` +
+			`{"publishedfileid":"2","sortorder":0,"filetype":0},` +
+			`{"publishedfileid":"1","sortorder":1,"filetype":0},` +
+			`{"publishedfileid":"100","sortorder":2,"filetype":2}]}`,
+		"300": `{"publishedfileid":"300","result":9}`,
+		"5":   `{"publishedfileid":"5","result":1}`,
+	}
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		requests++
+		n, _ := strconv.Atoi(r.Form.Get("collectioncount"))
+		var out []string
+		for i := 0; i < n; i++ {
+			out = append(out, details[r.Form.Get("publishedfileids["+strconv.Itoa(i)+"]")])
+		}
+		w.Write([]byte(`{"response":{"result":1,"resultcount":` + strconv.Itoa(n) + `,"collectiondetails":[` + strings.Join(out, ",") + `]}}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewWorkshopClient(http.DefaultClient, srv.URL, nil)
+	if err != nil {
+		t.Fatalf("NewWorkshopClient: %v", err)
+	}
+	got, err := c.FetchCollection("100")
+	if err != nil {
+		t.Fatalf("FetchCollection: %v", err)
+	}
+	if strings.Join(got.Items, ",") != "1,2,3" || strings.Join(got.Collections, ",") != "200" ||
+		strings.Join(got.Unavailable, ",") != "300" {
+		t.Fatalf("got=%+v", got)
+	}
+	// 子合集 200 与 300 在同一层，合并为一次请求。
+	if requests != 2 {
+		t.Fatalf("requests=%d", requests)
+	}
+
+	if _, err := c.FetchCollection("5"); err != ErrCollectionNotFound {
+		t.Fatalf("err=%v", err)
+	}
+}
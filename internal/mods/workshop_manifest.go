@@ -0,0 +1,172 @@
+package mods
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// InstalledWorkshopItem appworkshop_108600.acf 中记录的已下载条目。
+type InstalledWorkshopItem struct {
+	Size int64
+	// TimeUpdated 下载时 Steam 上该条目的 time_updated（Unix 秒）。
+	TimeUpdated int64
+	Manifest    string
+}
+
+// WorkshopManifest appworkshop_108600.acf 的 WorkshopItemsInstalled 部分，按创意工坊 ID 索引。
+type WorkshopManifest map[string]InstalledWorkshopItem
+
+// WorkshopManifestPath 服务器下载创意工坊条目后由 Steam 维护的清单文件。
+func WorkshopManifestPath(installDir string) string {
+	return filepath.Join(installDir, "steamapps", "workshop", "appworkshop_108600.acf")
+}
+
+// ReadWorkshopManifest 文件不存在时返回空清单。
+func ReadWorkshopManifest(installDir string) (WorkshopManifest, error) {
+	f, err := os.Open(WorkshopManifestPath(installDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return WorkshopManifest{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseWorkshopManifest(f)
+}
+
+// ParseWorkshopManifest 解析 Valve KeyValues（.acf）文本，只读取 WorkshopItemsInstalled。
+func ParseWorkshopManifest(r io.Reader) (WorkshopManifest, error) {
+	root, err := parseKeyValues(bufio.NewReader(r))
+	if err != nil {
+		return nil, err
+	}
+	out := WorkshopManifest{}
+	var installed kvNode
+	for _, top := range root.children {
+		// 根节点为 "AppWorkshop"。
+		if n, ok := top.child("WorkshopItemsInstalled"); ok {
+			installed = n
+		}
+	}
+	for _, item := range installed.children {
+		size, _ := strconv.ParseInt(item.value("size"), 10, 64)
+		updated, _ := strconv.ParseInt(item.value("timeupdated"), 10, 64)
+		out[item.key] = InstalledWorkshopItem{Size: size, TimeUpdated: updated, Manifest: item.value("manifest")}
+	}
+	return out, nil
+}
+
+// kvNode KeyValues 中的一项：要么有 val，要么有 children。
+type kvNode struct {
+	key      string
+	val      string
+	children []kvNode
+}
+
+func (n kvNode) child(key string) (kvNode, bool) {
+	for _, c := range n.children {
+		if strings.EqualFold(c.key, key) {
+			return c, true
+		}
+	}
+	return kvNode{}, false
+}
+
+func (n kvNode) value(key string) string {
+	c, _ := n.child(key)
+	return c.val
+}
+
+func parseKeyValues(r *bufio.Reader) (kvNode, error) {
+	root := kvNode{}
+	stack := []*kvNode{&root}
+	var key *string
+	for {
+		tok, quoted, err := nextKVToken(r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return kvNode{}, err
+		}
+		cur := stack[len(stack)-1]
+		switch {
+		case !quoted && tok == "{":
+			if key == nil {
+				return kvNode{}, fmt.Errorf("acf: unexpected '{'")
+			}
+			cur.children = append(cur.children, kvNode{key: *key})
+			stack = append(stack, &cur.children[len(cur.children)-1])
+			key = nil
+		case !quoted && tok == "}":
+			if len(stack) == 1 || key != nil {
+				return kvNode{}, fmt.Errorf("acf: unexpected '}'")
+			}
+			stack = stack[:len(stack)-1]
+		case key == nil:
+			k := tok
+			key = &k
+		default:
+			cur.children = append(cur.children, kvNode{key: *key, val: tok})
+			key = nil
+		}
+	}
+	if len(stack) != 1 || key != nil {
+		return kvNode{}, fmt.Errorf("acf: unexpected end of file")
+	}
+	return root, nil
+}
+
+// nextKVToken 返回下一个带引号的字符串或 { }，跳过空白与 // 注释。
+func nextKVToken(r *bufio.Reader) (string, bool, error) {
+	for {
+		ch, _, err := r.ReadRune()
+		if err != nil {
+			return "", false, err
+		}
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\r' || ch == '\n':
+		case ch == '/':
+			if next, _ := r.Peek(1); len(next) == 1 && next[0] == '/' {
+				if _, err := r.ReadString('\n'); err != nil && err != io.EOF {
+					return "", false, err
+				}
+				continue
+			}
+			return "", false, fmt.Errorf("acf: unexpected '/'")
+		case ch == '{' || ch == '}':
+			return string(ch), false, nil
+		case ch == '"':
+			var b strings.Builder
+			for {
+				c, _, err := r.ReadRune()
+				if err != nil {
+					return "", false, fmt.Errorf("acf: unterminated string")
+				}
+				if c == '"' {
+					return b.String(), true, nil
+				}
+				if c == '\\' {
+					if c, _, err = r.ReadRune(); err != nil {
+						return "", false, fmt.Errorf("acf: unterminated string")
+					}
+					switch c {
+					case 'n':
+						c = '\n'
+					case 't':
+						c = '\t'
+					}
+				}
+				b.WriteRune(c)
+			}
+		default:
+			return "", false, fmt.Errorf("acf: unexpected %q", ch)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package mods
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sampleACF = `"AppWorkshop"
+{
+	"appid"		"108600"
+	"NeedsUpdate"		"0"
+	// 注释行
+	"WorkshopItemsInstalled"
+	{
+		"2392709985"
+		{
+			"size"		"1048576"
+			"timeupdated"		"1700000000"
+			"manifest"		"123456789"
+		}
+		"2544353492"
+		{
+			"size"		"2048"
+			"timeupdated"		"1690000000"
+			"manifest"		"987"
+		}
+	}
+	"WorkshopItemDetails"
+	{
+		"2392709985"
+		{
+			"manifest"		"123456789"
+			"timeupdated"		"1700000000"
+			"latest_timeupdated"		"1710000000"
+		}
+	}
+}
+`
+
+func TestParseWorkshopManifest(t *testing.T) {
+	m, err := ParseWorkshopManifest(strings.NewReader(sampleACF))
+	if err != nil {
+		t.Fatalf("ParseWorkshopManifest: %v", err)
+	}
+	if len(m) != 2 {
+		t.Fatalf("m=%+v", m)
+	}
+	if got := m["2392709985"]; got.Size != 1048576 || got.TimeUpdated != 1700000000 || got.Manifest != "123456789" {
+		t.Fatalf("item=%+v", got)
+	}
+
+	if _, err := ParseWorkshopManifest(strings.NewReader(`"AppWorkshop" { "a" "b"`)); err == nil {
+		t.Fatalf("expected error for truncated file")
+	}
+}
+
+func TestReadWorkshopManifest(t *testing.T) {
+	root := t.TempDir()
+	m, err := ReadWorkshopManifest(root)
+	if err != nil || len(m) != 0 {
+		t.Fatalf("m=%+v err=%v", m, err)
+	}
+
+	path := WorkshopManifestPath(root)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(sampleACF), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if m, err = ReadWorkshopManifest(root); err != nil || m["2544353492"].TimeUpdated != 1690000000 {
+		t.Fatalf("m=%+v err=%v", m, err)
+	}
+}
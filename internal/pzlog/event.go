@@ -0,0 +1,237 @@
+package pzlog
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventPlayerConnected    EventType = "player_connected"
+	EventPlayerDisconnected EventType = "player_disconnected"
+	EventChat               EventType = "chat"
+	EventServerStarted      EventType = "server_started"
+	EventWorldSaved         EventType = "world_saved"
+	EventModError           EventType = "mod_error"
+	EventException          EventType = "exception"
+	EventShutdown           EventType = "shutdown"
+)
+
+// EventTypes 全部事件类型，供参数校验使用。
+var EventTypes = []EventType{
+	EventPlayerConnected, EventPlayerDisconnected, EventChat, EventServerStarted,
+	EventWorldSaved, EventModError, EventException, EventShutdown,
+}
+
+// Event 从一行或多行日志中识别出的事件，未用到的字段为空。
+type Event struct {
+	Type EventType `json:"type"`
+	// LineID 事件最后一行的 ID（由调用方提供），断线重连时从这里继续即可不重复。
+	LineID    uint64    `json:"line_id,omitempty"`
+	Time      time.Time `json:"time"`
+	Level     Level     `json:"level,omitempty"`
+	Subsystem string    `json:"subsystem,omitempty"`
+	Message   string    `json:"message"`
+	SteamID   string    `json:"steam_id,omitempty"`
+	Username  string    `json:"username,omitempty"`
+	// Channel 聊天频道（General、Faction、Safehouse 等）。
+	Channel string `json:"channel,omitempty"`
+	Mod     string `json:"mod,omitempty"`
+	// Exception Java 异常类名，Stack 为其后的堆栈行（含 Caused by）。
+	Exception string   `json:"exception,omitempty"`
+	Stack     []string `json:"stack,omitempty"`
+}
+
+// WorldSavedPattern 服务器日志中表示世界保存完成的行。
+var WorldSavedPattern = regexp.MustCompile(`(?i)world saved|saving finished|save(d)? complete`)
+
+var (
+	connManager = regexp.MustCompile(`ConnectionManager: \[(fully-connected|disconnect)\]`)
+	steamIDKV   = regexp.MustCompile(`steam-id=(\d+)`)
+	usernameKV  = regexp.MustCompile(`username="([^"]*)"`)
+	// user.txt："76561198000000000 "Bob" fully connected (10,10,0)"。
+	userLogPlayer = regexp.MustCompile(`^(\d{17}) "([^"]*)" (fully connected|disconnected player)`)
+	// 旧版本的断开提示："Disconnected player "Bob" 76561198000000000"。
+	disconnectedPlayer = regexp.MustCompile(`Disconnected player "([^"]*)"(?: (\d{17}))?`)
+	chatMessage        = regexp.MustCompile(`ChatMessage\{chat=([^,]*), author='(.*?)', text='(.*)'\}`)
+	serverStarted      = regexp.MustCompile(`(?i)server started`)
+	shutdown           = regexp.MustCompile(`(?i)server is shutting down|shutting down server|SERVER SHUTDOWN|command entered via server console \(System\.in\): "quit"`)
+	modMissing         = regexp.MustCompile(`(?i)(?:required )?mod "([^"]+)" not found|mod not found: ?(\S+)`)
+	modLoad            = regexp.MustCompile(`(?i)(?:failed|error) (?:to )?load(?:ing)? mod[: ]+"?([^"\s]+)"?`)
+	// Java 异常首行，如 "java.lang.NullPointerException: msg" 或 "Exception in thread "main" ..."。
+	exceptionHead = regexp.MustCompile(`^(?:Exception in thread "[^"]*" )?((?:[A-Za-z_$][\w$]*\.)+[A-Z][\w$]*(?:Exception|Error|Throwable))(?::\s*(.*))?$`)
+	stackLine     = regexp.MustCompile(`^\s+at |^\s*\.\.\. \d+ (?:more|common frames omitted)|^\s*Caused by: |^\s*Suppressed: `)
+	// PZ 打印异常前的提示行，之后紧跟异常首行与堆栈。
+	exceptionIntro = regexp.MustCompile(`ExceptionLogger\.logException|printException|Stack trace:|Exception thrown`)
+)
+
+// Parser 逐行识别事件；Java 堆栈跨多行，需等到下一条非堆栈行（或 Flush）才能确定结束。
+// 零值可用，不能并发使用。
+type Parser struct {
+	// Location 方括号格式时间的时区，nil 为面板所在时区。
+	Location *time.Location
+
+	pending *Event
+}
+
+// Feed 处理一行，返回因此确定的事件（可能包含之前挂起的异常）。
+func (p *Parser) Feed(id uint64, text string) []Event {
+	text = strings.TrimSuffix(text, "\r")
+	line := ParseLine(text, p.Location)
+
+	if p.pending != nil && len(p.pending.Stack) == 0 && line.HasHeader() && exceptionIntro.MatchString(line.Message) {
+		// "Exception thrown ..." 之后的 "Stack trace:" 属于同一个异常。
+		p.pending.LineID = id
+		return nil
+	}
+	if p.pending != nil && !line.HasHeader() {
+		if stackLine.MatchString(text) {
+			p.pending.Stack = append(p.pending.Stack, strings.TrimRight(text, " \t"))
+			p.pending.LineID = id
+			return nil
+		}
+		// 首行可能在提示行之后单独出现，此时用它的类名与消息。
+		if len(p.pending.Stack) == 0 {
+			if m := exceptionHead.FindStringSubmatch(strings.TrimSpace(text)); m != nil {
+				p.pending.Exception, p.pending.Message = m[1], m[2]
+				p.pending.LineID = id
+				return nil
+			}
+		}
+	}
+
+	out := p.Flush()
+	if ev, ok := p.exceptionStart(id, line); ok {
+		p.pending = &ev
+		return out
+	}
+	if ev, ok := match(line); ok {
+		ev.LineID = id
+		out = append(out, ev)
+	}
+	return out
+}
+
+// Flush 返回挂起的异常（没有堆栈行的提示行不单独成为事件）。
+func (p *Parser) Flush() []Event {
+	ev := p.pending
+	p.pending = nil
+	if ev == nil || ev.Exception == "" {
+		return nil
+	}
+	return []Event{*ev}
+}
+
+// Pending 是否有尚未结束的异常。
+func (p *Parser) Pending() bool {
+	return p.pending != nil
+}
+
+func (p *Parser) exceptionStart(id uint64, line Line) (Event, bool) {
+	ev := Event{Type: EventException, LineID: id, Time: line.Time, Level: line.Level, Subsystem: line.Subsystem}
+	msg := strings.TrimSpace(line.Message)
+	if m := exceptionHead.FindStringSubmatch(msg); m != nil {
+		ev.Exception, ev.Message = m[1], m[2]
+		return ev, true
+	}
+	if exceptionIntro.MatchString(msg) {
+		// "Exception thrown java.lang.X at ..." 提示行里已有类名，后面仍可能跟着完整堆栈。
+		for _, f := range strings.Fields(msg) {
+			if m := exceptionHead.FindStringSubmatch(strings.TrimSuffix(f, ".")); m != nil {
+				ev.Exception = m[1]
+				break
+			}
+		}
+		ev.Message = msg
+		return ev, true
+	}
+	return Event{}, false
+}
+
+// match 识别单行事件。
+func match(line Line) (Event, bool) {
+	msg := line.Message
+	ev := Event{Time: line.Time, Level: line.Level, Subsystem: line.Subsystem, Message: msg}
+
+	if m := connManager.FindStringSubmatch(msg); m != nil {
+		ev.Type = EventPlayerConnected
+		if m[1] == "disconnect" {
+			ev.Type = EventPlayerDisconnected
+		}
+		if id := steamIDKV.FindStringSubmatch(msg); id != nil {
+			ev.SteamID = id[1]
+		}
+		if u := usernameKV.FindStringSubmatch(msg); u != nil {
+			ev.Username = u[1]
+		}
+		return ev, true
+	}
+	if m := userLogPlayer.FindStringSubmatch(msg); m != nil {
+		ev.Type = EventPlayerConnected
+		if m[3] == "disconnected player" {
+			ev.Type = EventPlayerDisconnected
+		}
+		ev.SteamID, ev.Username = m[1], m[2]
+		return ev, true
+	}
+	if m := disconnectedPlayer.FindStringSubmatch(msg); m != nil {
+		ev.Type = EventPlayerDisconnected
+		ev.Username, ev.SteamID = m[1], m[2]
+		return ev, true
+	}
+	if m := chatMessage.FindStringSubmatch(msg); m != nil {
+		ev.Type = EventChat
+		ev.Channel, ev.Username, ev.Message = m[1], m[2], m[3]
+		return ev, true
+	}
+	if m := modMissing.FindStringSubmatch(msg); m != nil {
+		ev.Type = EventModError
+		ev.Mod = m[1] + m[2]
+		return ev, true
+	}
+	if m := modLoad.FindStringSubmatch(msg); m != nil {
+		ev.Type = EventModError
+		ev.Mod = m[1]
+		return ev, true
+	}
+	if line.Subsystem == "Mod" && line.Level.AtLeast(LevelWarn) {
+		ev.Type = EventModError
+		return ev, true
+	}
+	switch {
+	case serverStarted.MatchString(msg):
+		ev.Type = EventServerStarted
+	case WorldSavedPattern.MatchString(msg):
+		ev.Type = EventWorldSaved
+	case shutdown.MatchString(msg):
+		ev.Type = EventShutdown
+	default:
+		return Event{}, false
+	}
+	return ev, true
+}
+
+// ParseEventTypes 解析逗号分隔的类型列表；空字符串返回 nil（不过滤），未知类型返回 ok=false。
+func ParseEventTypes(s string) (map[EventType]bool, bool) {
+	if strings.TrimSpace(s) == "" {
+		return nil, true
+	}
+	out := make(map[EventType]bool)
+	for _, part := range strings.Split(s, ",") {
+		t := EventType(strings.TrimSpace(part))
+		known := false
+		for _, k := range EventTypes {
+			if k == t {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, false
+		}
+		out[t] = true
+	}
+	return out, true
+}
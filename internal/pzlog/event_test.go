@@ -0,0 +1,109 @@
+package pzlog
+
+import (
+	"strings"
+	"testing"
+)
+
+func feedAll(p *Parser, lines ...string) []Event {
+	var out []Event
+	for i, l := range lines {
+		out = append(out, p.Feed(uint64(i+1), l)...)
+	}
+	return append(out, p.Flush()...)
+}
+
+func TestParser_SingleLineEvents(t *testing.T) {
+	var p Parser
+	events := feedAll(&p,
+		`LOG  : Network     , 1697038843806> 35,716,287> ConnectionManager: [fully-connected] "" connection: guid=1 ip=10.0.0.2 steam-id=76561198000000001 access=admin username="Bob" connection-type="UDPRakNet"`,
+		`LOG  : General     , 1697038843807> 35,716,288> Got message:ChatMessage{chat=General, author='Bob', text='hello, it's me'}.`,
+		`LOG  : Network     , 1697038843808> 35,716,289> ConnectionManager: [disconnect] "receive-disconnect" connection: guid=1 ip=10.0.0.2 steam-id=76561198000000001 access=admin username="Bob" connection-type="UDPRakNet"`,
+		`[12-10-23 10:00:00.000] 76561198000000002 "Alice" fully connected (10,10,0).`,
+		`LOG  : General     , 1697038843809> 35,716,290> *** SERVER STARTED ****`,
+		`LOG  : General     , 1697038843810> 35,716,291> World saved`,
+		`ERROR: Mod         , 1697038843811> 35,716,292> required mod "Hydrocraft" not found`,
+		`LOG  : General     , 1697038843812> 35,716,293> loading world`,
+		`LOG  : General     , 1697038843813> 35,716,294> command entered via server console (System.in): "quit"`,
+	)
+	var got []string
+	for _, e := range events {
+		got = append(got, string(e.Type))
+	}
+	want := "player_connected,chat,player_disconnected,player_connected,server_started,world_saved,mod_error,shutdown"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("got=%v", got)
+	}
+	if e := events[0]; e.SteamID != "76561198000000001" || e.Username != "Bob" || e.LineID != 1 {
+		t.Fatalf("connect=%+v", e)
+	}
+	if e := events[1]; e.Channel != "General" || e.Username != "Bob" || e.Message != "hello, it's me" {
+		t.Fatalf("chat=%+v", e)
+	}
+	if e := events[3]; e.SteamID != "76561198000000002" || e.Username != "Alice" || e.Time.IsZero() {
+		t.Fatalf("user log=%+v", e)
+	}
+	if e := events[6]; e.Mod != "Hydrocraft" || e.Level != LevelError {
+		t.Fatalf("mod=%+v", e)
+	}
+	if e := events[7]; e.LineID != 9 {
+		t.Fatalf("shutdown=%+v", e)
+	}
+}
+
+func TestParser_GroupsStackTraces(t *testing.T) {
+	var p Parser
+	lines := []string{
+		`ERROR: General     , 1697038843806> 35,716,287> ExceptionLogger.logException> Exception thrown java.lang.NullPointerException at IsoPlayer.update. Message: null`,
+		`ERROR: General     , 1697038843806> 35,716,287> DebugLogStream.printException> Stack trace:`,
+		`java.lang.NullPointerException: Cannot invoke "Object.toString()"`,
+		"\tat zombie.characters.IsoPlayer.update(IsoPlayer.java:100)",
+		"\tat zombie.GameServer.main(GameServer.java:10)",
+		"Caused by: java.lang.IllegalStateException: boom",
+		"\t... 2 more",
+		`LOG  : General     , 1697038843900> 35,716,300> World saved`,
+	}
+	var events []Event
+	for i, l := range lines {
+		got := p.Feed(uint64(i+1), l)
+		if i < 7 && len(got) != 0 {
+			t.Fatalf("line %d emitted %+v", i, got)
+		}
+		events = append(events, got...)
+	}
+	if p.Pending() {
+		t.Fatalf("still pending")
+	}
+	if len(events) != 2 || events[0].Type != EventException || events[1].Type != EventWorldSaved {
+		t.Fatalf("events=%+v", events)
+	}
+	ex := events[0]
+	if ex.Exception != "java.lang.NullPointerException" || ex.Message != `Cannot invoke "Object.toString()"` ||
+		len(ex.Stack) != 4 || ex.LineID != 7 || ex.Level != LevelError {
+		t.Fatalf("exception=%+v", ex)
+	}
+
+	// 流结束时由 Flush 交出挂起的异常；只有提示行没有类名的不算事件。
+	p = Parser{}
+	p.Feed(1, "java.lang.OutOfMemoryError: Java heap space")
+	p.Feed(2, "\tat java.util.Arrays.copyOf(Arrays.java:3332)")
+	if ev := p.Flush(); len(ev) != 1 || ev[0].Exception != "java.lang.OutOfMemoryError" || ev[0].LineID != 2 {
+		t.Fatalf("flush=%+v", ev)
+	}
+	p.Feed(3, "ERROR: General     , 1697038843806> Stack trace:")
+	if ev := p.Flush(); len(ev) != 0 {
+		t.Fatalf("flush=%+v", ev)
+	}
+}
+
+func TestParseEventTypes(t *testing.T) {
+	if m, ok := ParseEventTypes(" chat,exception "); !ok || !m[EventChat] || !m[EventException] || len(m) != 2 {
+		t.Fatalf("m=%v ok=%v", m, ok)
+	}
+	if _, ok := ParseEventTypes("chat,nope"); ok {
+		t.Fatalf("expected unknown type")
+	}
+	if m, ok := ParseEventTypes(""); !ok || m != nil {
+		t.Fatalf("m=%v", m)
+	}
+}
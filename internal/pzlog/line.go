@@ -0,0 +1,119 @@
+// Package pzlog 解析 Project Zomboid 服务器的控制台输出。
+package pzlog
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Level string
+
+const (
+	LevelTrace Level = "TRACE"
+	LevelDebug Level = "DEBUG"
+	LevelLog   Level = "LOG"
+	LevelWarn  Level = "WARN"
+	LevelError Level = "ERROR"
+)
+
+// ParseLevel 不区分大小写，另接受 INFO（视为 LOG）与 WARNING；无法识别时返回空。
+func ParseLevel(s string) Level {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "TRACE":
+		return LevelTrace
+	case "DEBUG":
+		return LevelDebug
+	case "LOG", "INFO":
+		return LevelLog
+	case "WARN", "WARNING":
+		return LevelWarn
+	case "ERROR", "SEVERE":
+		return LevelError
+	}
+	return ""
+}
+
+// Line 一行日志拆出的字段；不是标准格式的行（堆栈、原样输出）只有 Message。
+type Line struct {
+	Level     Level     `json:"level,omitempty"`
+	Time      time.Time `json:"time"`
+	Subsystem string    `json:"subsystem,omitempty"`
+	Message   string    `json:"message"`
+}
+
+// HasHeader 行首带有级别或时间戳。
+func (l Line) HasHeader() bool {
+	return l.Level != "" || !l.Time.IsZero()
+}
+
+var (
+	// 控制台格式：
+	//   B41  "LOG  : General     , 1697038843806> 35,716,287> message"
+	//   B42  "LOG  : General      f:0, t:1733000000000, st:12,345> message"
+	consoleHeader = regexp.MustCompile(`^(TRACE|DEBUG|LOG|WARN|ERROR)\s*:\s*([A-Za-z][\w.]*)\s*([^>]*)>\s?(.*)$`)
+	tickPrefix    = regexp.MustCompile(`^\d[\d,]*>\s?`)
+	tsField       = regexp.MustCompile(`\bt:(\d{10,})`)
+	tsBare        = regexp.MustCompile(`\b(\d{12,})\b`)
+	// Logs 目录下的文件（user.txt、chat.txt 等）："[12-10-23 10:00:00.123] message" 或带 "[info]"。
+	bracketHeader = regexp.MustCompile(`^\[(\d{2}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}(?:\.\d{1,3})?)\](?:\s?\[(\w+)\])?\s?(.*)$`)
+)
+
+// ParseLine 解析一行。控制台的毫秒时间戳是绝对时间；
+// 方括号格式的时间没有时区，按 loc 解释（nil 为面板所在时区）。
+func ParseLine(s string, loc *time.Location) Line {
+	s = strings.TrimSuffix(s, "\r")
+	if m := consoleHeader.FindStringSubmatch(s); m != nil {
+		l := Line{Level: Level(m[1]), Subsystem: m[2], Message: tickPrefix.ReplaceAllString(m[4], "")}
+		l.Time = headerTime(m[3])
+		return l
+	}
+	if m := bracketHeader.FindStringSubmatch(s); m != nil {
+		if loc == nil {
+			loc = time.Local
+		}
+		l := Line{Level: ParseLevel(m[2]), Message: m[3]}
+		if t, err := time.ParseInLocation("02-01-06 15:04:05.999", m[1], loc); err == nil {
+			l.Time = t
+		}
+		return l
+	}
+	return Line{Message: s}
+}
+
+func headerTime(header string) time.Time {
+	m := tsField.FindStringSubmatch(header)
+	if m == nil {
+		m = tsBare.FindStringSubmatch(header)
+	}
+	if m == nil {
+		return time.Time{}
+	}
+	ms, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
+
+// AtLeast 按 TRACE < DEBUG < LOG < WARN < ERROR 比较；空级别不满足任何下限。
+func (l Level) AtLeast(min Level) bool {
+	return l.rank() >= min.rank() && l.rank() > 0
+}
+
+func (l Level) rank() int {
+	switch l {
+	case LevelTrace:
+		return 1
+	case LevelDebug:
+		return 2
+	case LevelLog:
+		return 3
+	case LevelWarn:
+		return 4
+	case LevelError:
+		return 5
+	}
+	return 0
+}
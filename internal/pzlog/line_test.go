@@ -0,0 +1,47 @@
+package pzlog
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseLine_ConsoleFormats(t *testing.T) {
+	b41 := ParseLine("LOG  : General     , 1697038843806> 35,716,287> Server started.", nil)
+	if b41.Level != LevelLog || b41.Subsystem != "General" || b41.Message != "Server started." ||
+		!b41.Time.Equal(time.UnixMilli(1697038843806)) {
+		t.Fatalf("b41=%+v", b41)
+	}
+
+	b42 := ParseLine("WARN : Mod          f:0, t:1733000000000, st:12,345> required mod \"Foo\" not found\r", nil)
+	if b42.Level != LevelWarn || b42.Subsystem != "Mod" || b42.Message != `required mod "Foo" not found` ||
+		!b42.Time.Equal(time.UnixMilli(1733000000000)) {
+		t.Fatalf("b42=%+v", b42)
+	}
+
+	bare := ParseLine("LOG  : General > World saved", nil)
+	if bare.Level != LevelLog || bare.Message != "World saved" || !bare.Time.IsZero() {
+		t.Fatalf("bare=%+v", bare)
+	}
+
+	raw := ParseLine("\tat zombie.Lua.call(Lua.java:12)", nil)
+	if raw.HasHeader() || raw.Message != "\tat zombie.Lua.call(Lua.java:12)" {
+		t.Fatalf("raw=%+v", raw)
+	}
+}
+
+func TestParseLine_BracketFormat(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	l := ParseLine(`[12-10-23 10:00:01.250][info] Got message`, loc)
+	if l.Level != LevelLog || l.Message != "Got message" || !l.Time.Equal(time.Date(2023, 10, 12, 10, 0, 1, 250e6, loc)) {
+		t.Fatalf("l=%+v", l)
+	}
+}
+
+func TestLevel_AtLeast(t *testing.T) {
+	if !LevelError.AtLeast(LevelWarn) || LevelLog.AtLeast(LevelWarn) || Level("").AtLeast("") {
+		t.Fatalf("level ordering wrong")
+	}
+	if ParseLevel("warning") != LevelWarn || ParseLevel("info") != LevelLog || ParseLevel("x") != "" {
+		t.Fatalf("ParseLevel wrong")
+	}
+}
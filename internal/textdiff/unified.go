@@ -0,0 +1,191 @@
+// Package textdiff 生成按行比较的 unified diff，用于配置文件的变更展示。
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultContext unified diff 默认的上下文行数。
+const DefaultContext = 3
+
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+type op struct {
+	kind opKind
+	a, b int // 在 a / b 中的行号（0 起）
+}
+
+// Unified 返回 a 到 b 的 unified diff；内容相同时返回空串。
+func Unified(aName, bName, a, b string, context int) string {
+	if a == b {
+		return ""
+	}
+	if context < 0 {
+		context = DefaultContext
+	}
+
+	al, bl := splitLines(a), splitLines(b)
+	ops := diffLines(al, bl)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("--- %s\n+++ %s\n", aName, bName))
+
+	for _, h := range hunks(ops, context) {
+		aStart, aLen, bStart, bLen := h.ranges()
+		sb.WriteString(fmt.Sprintf("@@ -%s +%s @@\n", formatRange(aStart, aLen), formatRange(bStart, bLen)))
+		for _, o := range h {
+			switch o.kind {
+			case opEqual:
+				sb.WriteString(" " + al[o.a] + "\n")
+			case opDelete:
+				sb.WriteString("-" + al[o.a] + "\n")
+			case opInsert:
+				sb.WriteString("+" + bl[o.b] + "\n")
+			}
+		}
+	}
+	return sb.String()
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines 基于最长公共子序列计算行级编辑脚本。
+// 配置文件通常只有几百行，O(n*m) 的表足够。
+func diffLines(a, b []string) []op {
+	// 去掉公共前后缀以缩小表规模。
+	pre := 0
+	for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+		suf++
+	}
+
+	var ops []op
+	for i := 0; i < pre; i++ {
+		ops = append(ops, op{kind: opEqual, a: i, b: i})
+	}
+
+	ma, mb := a[pre:len(a)-suf], b[pre:len(b)-suf]
+	n, m := len(ma), len(mb)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if ma[i] == mb[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && ma[i] == mb[j]:
+			ops = append(ops, op{kind: opEqual, a: pre + i, b: pre + j})
+			i++
+			j++
+		case j < m && (i == n || lcs[i][j+1] > lcs[i+1][j]):
+			ops = append(ops, op{kind: opInsert, a: pre + i, b: pre + j})
+			j++
+		default:
+			ops = append(ops, op{kind: opDelete, a: pre + i, b: pre + j})
+			i++
+		}
+	}
+
+	for k := 0; k < suf; k++ {
+		ops = append(ops, op{kind: opEqual, a: len(a) - suf + k, b: len(b) - suf + k})
+	}
+	return ops
+}
+
+type hunk []op
+
+// ranges 返回 hunk 在 a / b 中的起始行号（1 起）与行数。
+func (h hunk) ranges() (aStart, aLen, bStart, bLen int) {
+	aStart, bStart = h[0].a+1, h[0].b+1
+	for _, o := range h {
+		switch o.kind {
+		case opEqual:
+			aLen++
+			bLen++
+		case opDelete:
+			aLen++
+		case opInsert:
+			bLen++
+		}
+	}
+	if aLen == 0 {
+		aStart--
+	}
+	if bLen == 0 {
+		bStart--
+	}
+	return aStart, aLen, bStart, bLen
+}
+
+// hunks 将编辑脚本按改动聚合，每段前后保留 context 行上下文。
+func hunks(ops []op, context int) []hunk {
+	var out []hunk
+	var cur hunk
+	lastChange := -1
+
+	for i, o := range ops {
+		if o.kind == opEqual {
+			continue
+		}
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		if cur != nil && start <= lastChange+context+1 {
+			cur = append(cur, ops[lastChange+1:i+1]...)
+		} else {
+			if cur != nil {
+				out = append(out, closeHunk(cur, ops, lastChange, context))
+			}
+			cur = append(hunk(nil), ops[start:i+1]...)
+		}
+		lastChange = i
+	}
+	if cur != nil {
+		out = append(out, closeHunk(cur, ops, lastChange, context))
+	}
+	return out
+}
+
+func closeHunk(h hunk, ops []op, lastChange, context int) hunk {
+	end := lastChange + 1 + context
+	if end > len(ops) {
+		end = len(ops)
+	}
+	return append(h, ops[lastChange+1:end]...)
+}
+
+func formatRange(start, length int) string {
+	if length == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, length)
+}
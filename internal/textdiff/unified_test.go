@@ -0,0 +1,31 @@
+package textdiff
+
+import "testing"
+
+func TestUnified_SingleChangeWithContext(t *testing.T) {
+	a := "a\nb\nc\nd\ne\nf\ng\nh\n"
+	b := "a\nb\nc\nd\nE\nf\ng\nh\n"
+
+	got := Unified("old", "new", a, b, 2)
+	want := "--- old\n+++ new\n@@ -3,5 +3,5 @@\n c\n d\n-e\n+E\n f\n g\n"
+	if got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestUnified_SplitsDistantHunksAndHandlesInsert(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+	b := "0\n1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n"
+
+	got := Unified("a", "b", a, b, 1)
+	want := "--- a\n+++ b\n@@ -1 +1,2 @@\n+0\n 1\n@@ -10 +11,2 @@\n 10\n+11\n"
+	if got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestUnified_EqualIsEmpty(t *testing.T) {
+	if got := Unified("a", "b", "x\n", "x\n", 3); got != "" {
+		t.Fatalf("got=%q", got)
+	}
+}
@@ -2,19 +2,31 @@ package httpserver
 
 import (
 	"net/http"
+	"path/filepath"
 	"time"
 
+	"pz-web-backend/internal/application/backupapp"
 	"pz-web-backend/internal/application/configapp"
 	"pz-web-backend/internal/application/i18napp"
+	"pz-web-backend/internal/application/logapp"
 	"pz-web-backend/internal/application/modsapp"
+	"pz-web-backend/internal/application/rconapp"
+	"pz-web-backend/internal/application/restartapp"
+	"pz-web-backend/internal/application/scheduleapp"
+	"pz-web-backend/internal/application/steamupdateapp"
 	"pz-web-backend/internal/application/updateapp"
+	"pz-web-backend/internal/application/watchdogapp"
+	"pz-web-backend/internal/audit"
 	"pz-web-backend/internal/config"
+	"pz-web-backend/internal/history"
 	"pz-web-backend/internal/i18n"
 	"pz-web-backend/internal/infra/executil"
 	"pz-web-backend/internal/infra/fs"
 	"pz-web-backend/internal/infra/logtail"
+	"pz-web-backend/internal/infra/procmgr"
 	"pz-web-backend/internal/infra/pzpaths"
-	"pz-web-backend/internal/infra/supervisor"
+	"pz-web-backend/internal/infra/steamcmd"
+	"pz-web-backend/internal/jobs"
 	"pz-web-backend/internal/mods"
 	sysupdate "pz-web-backend/internal/system/update"
 )
@@ -35,18 +47,26 @@ type App struct {
 	Config config.Service
 	I18n   *i18n.Loader
 
-	ConfigApp configapp.Service
-	I18nApp   i18napp.Service
-	ModsApp   modsapp.Service
-	UpdateApp updateapp.Service
-	LogTailer logtail.Tailer
+	ConfigApp   configapp.Service
+	I18nApp     i18napp.Service
+	ModsApp     modsapp.Service
+	UpdateApp   updateapp.Service
+	RCONApp     rconapp.Service
+	Restart     *restartapp.Orchestrator
+	SteamUpdate steamupdateapp.Service
+	Jobs        *jobs.Manager
+	Process     procmgr.ProcessManager
+	Watchdog    *watchdogapp.Watchdog
+	Scheduler   *scheduleapp.Scheduler
+	LogHub      *logtail.Hub
+	LogSearch   logapp.Service
 }
 
-func NewApp(baseDataDir string, baseGameDir string, serverName string, logPath string, build BuildInfo, devMode bool) App {
+func NewApp(baseDataDir string, baseGameDir string, serverName string, logPath string, build BuildInfo, devMode bool, steam steamcmd.Options, process procmgr.Config, gameVersion string) App {
 	osfs := fs.OSFS{}
 	runner := executil.OSRunner{}
-	restarter := supervisor.SupervisorctlRestarter{Runner: runner}
-	tailer := logtail.OSTailer{}
+	tailer := logtail.FileTailer{}
+	jobManager := &jobs.Manager{}
 
 	resolvedServerName := configapp.ResolveServerName(osfs, baseDataDir, serverName)
 
@@ -69,6 +89,10 @@ func NewApp(baseDataDir string, baseGameDir string, serverName string, logPath s
 	}
 
 	installDir := pzpaths.DefaultInstallDir(devMode)
+	if steam.InstallDir == "" {
+		steam.InstallDir = installDir
+	}
+	server := mustProcessManager(process, runner, installDir, resolvedServerName, resolveLogPath(logPath), baseDataDir)
 	workshopClient := mustDefaultWorkshopClient(devMode)
 
 	updateChecker := sysupdate.Service{
@@ -78,6 +102,40 @@ func NewApp(baseDataDir string, baseGameDir string, serverName string, logPath s
 	}
 	updateSvc := updateapp.NewService(devMode, updateChecker)
 
+	configApp := configapp.Service{
+		BaseDataDir: baseDataDir,
+		ServerName:  resolvedServerName,
+		DevMode:     devMode,
+		Config:      configSvc,
+		FS:          osfs,
+		Runner:      runner,
+		Restarter:   jobs.Restarter{Jobs: jobManager, Restarter: server},
+		History:     &history.FileStore{Dir: filepath.Join(baseDataDir, "pz-web", "history", resolvedServerName)},
+		Audit:       &audit.FileLogger{Path: filepath.Join(baseDataDir, "pz-web", "audit.log")},
+	}
+
+	rconApp := rconapp.NewService("127.0.0.1", configApp)
+	game, _ := mods.ParseGameVersion(gameVersion)
+	modsApp := modsapp.Service{
+		InstallDir:  installDir,
+		Workshop:    workshopClient,
+		Items:       configApp,
+		Config:      configApp,
+		GameVersion: game,
+	}
+	restart := &restartapp.Orchestrator{
+		RCON:      rconApp,
+		Restarter: server,
+		Saves:     restartapp.LogSaveWatcher{Tailer: tailer, Path: resolveLogPath(logPath)},
+		Jobs:      jobManager,
+	}
+	steamUpdate := steamupdateapp.Service{
+		Runner:   runner,
+		Server:   server,
+		SteamCMD: steam,
+		Jobs:     jobManager,
+	}
+
 	return App{
 		BaseDataDir: baseDataDir,
 		BaseGameDir: baseGameDir,
@@ -86,26 +144,69 @@ func NewApp(baseDataDir string, baseGameDir string, serverName string, logPath s
 		I18n:        loader,
 		Config:      configSvc,
 
-		ConfigApp: configapp.Service{
-			BaseDataDir: baseDataDir,
-			ServerName:  resolvedServerName,
-			DevMode:     devMode,
-			Config:      configSvc,
-			FS:          osfs,
-			Runner:      runner,
-			Restarter:   restarter,
-		},
+		ConfigApp: configApp,
 		I18nApp: i18napp.Service{
 			BaseGameDir: baseGameDir,
 			FS:          osfs,
 		},
-		ModsApp: modsapp.Service{
-			InstallDir: installDir,
-			Workshop:   workshopClient,
+		ModsApp:     modsApp,
+		UpdateApp:   updateSvc,
+		RCONApp:     rconApp,
+		Restart:     restart,
+		SteamUpdate: steamUpdate,
+		Jobs:        jobManager,
+		Process:     server,
+		Watchdog: &watchdogapp.Watchdog{
+			Process:   server,
+			RCON:      rconApp,
+			Logs:      watchdogapp.FileLog{Path: resolveLogPath(logPath)},
+			Jobs:      jobManager,
+			Incidents: &watchdogapp.FileIncidentStore{Path: filepath.Join(baseDataDir, "pz-web", "incidents.log")},
+			Policies:  &watchdogapp.FilePolicyStore{Path: filepath.Join(baseDataDir, "pz-web", "watchdog.json")},
 		},
-		UpdateApp: updateSvc,
-		LogTailer: tailer,
+		Scheduler: &scheduleapp.Scheduler{
+			Tasks:   &scheduleapp.FileTaskStore{Path: filepath.Join(baseDataDir, "pz-web", "schedules.json")},
+			Runs:    &scheduleapp.FileRunStore{Path: filepath.Join(baseDataDir, "pz-web", "schedule-runs.log")},
+			Restart: restart,
+			RCON:    rconApp,
+			Backup: backupapp.Service{
+				BaseDataDir: baseDataDir,
+				ServerName:  resolvedServerName,
+				OutDir:      filepath.Join(baseDataDir, "pz-web", "backups"),
+				RCON:        rconApp,
+				Saves:       restartapp.LogSaveWatcher{Tailer: tailer, Path: resolveLogPath(logPath)},
+			},
+			SteamUpdate: steamUpdate,
+			Workshop:    modsApp,
+		},
+		LogHub: &logtail.Hub{Tailer: tailer, Path: resolveLogPath(logPath)},
+		LogSearch: logapp.Service{
+			LogPath: resolveLogPath(logPath),
+			LogsDir: filepath.Join(baseDataDir, "Logs"),
+		},
+	}
+}
+
+// mustProcessManager 补全 direct 模式的默认参数并构造进程管理器，配置错误时直接退出。
+func mustProcessManager(cfg procmgr.Config, runner executil.Runner, installDir, serverName, logPath, baseDataDir string) procmgr.ProcessManager {
+	cfg.Runner = runner
+	if cfg.Command == "" {
+		cfg.Command = filepath.Join(installDir, "start-server.sh")
+	}
+	if cfg.Args == nil {
+		cfg.Args = []string{"-servername", serverName}
+	}
+	if cfg.LogPath == "" {
+		cfg.LogPath = logPath
+	}
+	if cfg.PIDFile == "" {
+		cfg.PIDFile = filepath.Join(baseDataDir, "pz-web", "server.pid")
+	}
+	pm, err := procmgr.New(cfg)
+	if err != nil {
+		panic(err)
 	}
+	return pm
 }
 
 func mustDefaultWorkshopClient(devMode bool) modsapp.WorkshopFetcher {
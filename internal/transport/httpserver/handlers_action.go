@@ -6,12 +6,12 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// handleUpdateAndRestart 启动 SteamCMD 更新任务；进度通过 /api/jobs/:id/stream 获取。
 func (a App) handleUpdateAndRestart(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"status": "ok", "message": "Updating and Restarting in background..."})
-	go func() {
-		if a.ConfigApp.Restarter == nil {
-			return
-		}
-		_ = a.ConfigApp.Restarter.RestartPZServer()
-	}()
+	job, err := a.SteamUpdate.Start()
+	if err != nil {
+		writeJobStartError(c, job, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"status": "ok", "job_id": job.ID, "job": job})
 }
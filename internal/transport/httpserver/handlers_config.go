@@ -1,41 +1,67 @@
 package httpserver
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"pz-web-backend/internal/application/configapp"
+	"pz-web-backend/internal/config"
 )
 
 func (a App) handleGetServerConfig(c *gin.Context) {
 	lang := strings.ToUpper(c.DefaultQuery("lang", "CN"))
 	lang = a.I18nApp.ResolveLang(lang)
-	items, err := a.ConfigApp.GetServerConfig(lang)
+	items, etag, err := a.ConfigApp.GetServerConfig(lang)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 	filename := fmt.Sprintf("%s.ini", a.ConfigApp.ServerName)
-	c.JSON(http.StatusOK, gin.H{"filename": filename, "lang": lang, "items": items})
+	c.Header("ETag", quoteETag(etag))
+	c.JSON(http.StatusOK, gin.H{"filename": filename, "lang": lang, "items": items, "etag": etag})
 }
 
 func (a App) handleGetSandboxConfig(c *gin.Context) {
 	lang := strings.ToUpper(c.DefaultQuery("lang", "CN"))
 	lang = a.I18nApp.ResolveLang(lang)
-	items, err := a.ConfigApp.GetSandboxConfig(lang)
+	items, etag, err := a.ConfigApp.GetSandboxConfig(lang)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 	filename := fmt.Sprintf("%s_SandboxVars.lua", a.ConfigApp.ServerName)
-	c.JSON(http.StatusOK, gin.H{"filename": filename, "lang": lang, "items": items})
+	c.Header("ETag", quoteETag(etag))
+	c.JSON(http.StatusOK, gin.H{"filename": filename, "lang": lang, "items": items, "etag": etag})
+}
+
+func (a App) handleGetSpawnRegions(c *gin.Context) {
+	regions, etag, err := a.ConfigApp.GetSpawnRegions()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	filename := fmt.Sprintf("%s_spawnregions.lua", a.ConfigApp.ServerName)
+	c.Header("ETag", quoteETag(etag))
+	c.JSON(http.StatusOK, gin.H{"filename": filename, "regions": regions, "etag": etag})
+}
+
+func (a App) handleGetSpawnPoints(c *gin.Context) {
+	professions, etag, err := a.ConfigApp.GetSpawnPoints()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	filename := fmt.Sprintf("%s_spawnpoints.lua", a.ConfigApp.ServerName)
+	c.Header("ETag", quoteETag(etag))
+	c.JSON(http.StatusOK, gin.H{"filename": filename, "professions": professions, "etag": etag})
 }
 
 func (a App) handleSaveConfig(c *gin.Context) {
 	name := configapp.SaveKind(c.Param("name"))
-	if name != configapp.KindServer && name != configapp.KindSandbox {
+	if !name.Valid() {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid config type"})
 		return
 	}
@@ -46,14 +72,99 @@ func (a App) handleSaveConfig(c *gin.Context) {
 		return
 	}
 
-	if err := a.ConfigApp.Save(name, req.Items, req.Restart); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	in := configapp.SaveInput{
+		Kind:        name,
+		Items:       req.Items,
+		Regions:     req.Regions,
+		Professions: req.Professions,
+		Restart:     req.Restart,
+		Author:      requestAuthor(c, req.Author),
+		Note:        req.Note,
+		ETag:        requestETag(c, req.ETag),
+	}
+	newETag, err := a.ConfigApp.Save(in)
+	if err != nil {
+		writeSaveError(c, err)
 		return
 	}
 
+	c.Header("ETag", quoteETag(newETag))
 	if req.Restart {
-		c.JSON(http.StatusOK, gin.H{"status": "saved_and_restarting", "message": "Save completed! Restarting server..."})
+		c.JSON(http.StatusOK, gin.H{"status": "saved_and_restarting", "message": "Save completed! Restarting server...", "etag": newETag})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "saved", "message": "Successfully saved!", "etag": newETag})
+}
+
+// requestAuthor 返回请求体中的作者；为空时使用客户端 IP。
+func requestAuthor(c *gin.Context, author string) string {
+	if author = strings.TrimSpace(author); author != "" {
+		return author
+	}
+	return c.ClientIP()
+}
+
+func (a App) handlePreviewConfig(c *gin.Context) {
+	name := configapp.SaveKind(c.Param("name"))
+	if !name.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid config type"})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"status": "saved", "message": "Successfully saved!"})
+
+	var req SaveRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	lang := a.I18nApp.ResolveLang(strings.ToUpper(c.DefaultQuery("lang", "CN")))
+	in := configapp.SaveInput{
+		Kind:        name,
+		Items:       req.Items,
+		Regions:     req.Regions,
+		Professions: req.Professions,
+		ETag:        requestETag(c, req.ETag),
+	}
+	preview, err := a.ConfigApp.Preview(in, lang)
+	if err != nil {
+		writeSaveError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"filename": preview.Filename,
+		"diff":     preview.Diff,
+		"changes":  preview.Changes,
+		"count":    len(preview.Changes),
+	})
+}
+
+// writeSaveError 校验错误返回 422 与逐项错误信息，文件已被他人修改返回 409，其余返回 500。
+func writeSaveError(c *gin.Context, err error) {
+	if errors.Is(err, configapp.ErrETagRequired) {
+		c.JSON(http.StatusPreconditionRequired, gin.H{"error": err.Error()})
+		return
+	}
+	var invalid config.ValidationErrors
+	if errors.As(err, &invalid) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "fields": invalid})
+		return
+	}
+	var conflict *configapp.ConflictError
+	if errors.As(err, &conflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "etag": conflict.CurrentETag, "diff": conflict.Diff})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+// requestETag 优先取请求体中的 etag，其次取 If-Match 请求头。
+func requestETag(c *gin.Context, etag string) string {
+	if etag != "" {
+		return etag
+	}
+	return strings.Trim(strings.TrimPrefix(c.GetHeader("If-Match"), "W/"), `"`)
+}
+
+func quoteETag(etag string) string {
+	return `"` + etag + `"`
 }
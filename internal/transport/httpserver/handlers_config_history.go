@@ -0,0 +1,88 @@
+package httpserver
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"pz-web-backend/internal/application/configapp"
+	"pz-web-backend/internal/history"
+)
+
+func (a App) handleListConfigHistory(c *gin.Context) {
+	kind, ok := historyKind(c)
+	if !ok {
+		return
+	}
+	snaps, err := a.ConfigApp.ListHistory(kind)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if snaps == nil {
+		snaps = []history.Snapshot{}
+	}
+	c.JSON(http.StatusOK, snaps)
+}
+
+func (a App) handleGetConfigSnapshot(c *gin.Context) {
+	kind, ok := historyKind(c)
+	if !ok {
+		return
+	}
+	snap, content, err := a.ConfigApp.GetSnapshot(kind, c.Param("id"))
+	if err != nil {
+		writeHistoryError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"snapshot": snap, "content": configapp.MaskSecrets(kind, string(content))})
+}
+
+func (a App) handleDiffConfigSnapshot(c *gin.Context) {
+	kind, ok := historyKind(c)
+	if !ok {
+		return
+	}
+	against := c.DefaultQuery("against", configapp.CurrentRevision)
+	diff, err := a.ConfigApp.DiffSnapshots(kind, c.Param("id"), against)
+	if err != nil {
+		writeHistoryError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"from": c.Param("id"), "to": against, "diff": diff})
+}
+
+func (a App) handleRestoreConfigSnapshot(c *gin.Context) {
+	kind, ok := historyKind(c)
+	if !ok {
+		return
+	}
+	var req RestoreRequest
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	etag, err := a.ConfigApp.Restore(kind, c.Param("id"), requestETag(c, req.ETag), requestAuthor(c, req.Author), req.Restart)
+	if err != nil {
+		writeHistoryError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "restored", "id": c.Param("id"), "etag": etag})
+}
+
+func historyKind(c *gin.Context) (configapp.SaveKind, bool) {
+	kind := configapp.SaveKind(c.Param("name"))
+	if !kind.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid config type"})
+		return "", false
+	}
+	return kind, true
+}
+
+func writeHistoryError(c *gin.Context, err error) {
+	if errors.Is(err, history.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	writeSaveError(c, err)
+}
@@ -0,0 +1,272 @@
+package httpserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"pz-web-backend/internal/application/configapp"
+	"pz-web-backend/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newConfigTestEngine 在临时数据目录中写入 files（相对 Server/）后创建引擎。
+func newConfigTestEngine(t *testing.T, files map[string]string) (*gin.Engine, string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	root := repoRoot(t)
+
+	dataDir := t.TempDir()
+	serverDir := filepath.Join(dataDir, "Server")
+	if err := os.MkdirAll(serverDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(serverDir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	r := NewEngine(Config{
+		BaseDataDir: dataDir,
+		BaseGameDir: filepath.Join(root, "testdata", "mock_media"),
+		ServerName:  "servertest",
+		DevMode:     true,
+		Build:       BuildInfo{Version: "test", GithubRepo: "test/repo"},
+		ContentFS:   os.DirFS(root),
+	})
+	return r, serverDir
+}
+
+func TestHandleSaveConfig_InvalidValueReturnsFieldErrors(t *testing.T) {
+	original := "SandboxVars = {\n    VERSION = 6,\n    StarterKit = false,\n}\n"
+	r, serverDir := newConfigTestEngine(t, map[string]string{"servertest_SandboxVars.lua": original})
+	luaPath := filepath.Join(serverDir, "servertest_SandboxVars.lua")
+
+	body, _ := json.Marshal(SaveRequest{
+		Items: []ConfigItem{{Key: "StarterKit", Value: "tru"}},
+		ETag:  configapp.ContentETag([]byte(original)),
+	})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/config/sandbox", bytes.NewReader(body))
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Fields []struct {
+			Key     string `json:"key"`
+			Message string `json:"message"`
+		} `json:"fields"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(resp.Fields) != 1 || resp.Fields[0].Key != "StarterKit" {
+		t.Fatalf("fields=%+v", resp.Fields)
+	}
+
+	got, _ := os.ReadFile(luaPath)
+	if string(got) != original {
+		t.Fatalf("file should be untouched, got=%q", got)
+	}
+}
+
+func TestHandlePreviewConfig_ReturnsChangesWithoutWriting(t *testing.T) {
+	original := "# pvp\nPVP=false\nMaxPlayers=32\n"
+	r, serverDir := newConfigTestEngine(t, map[string]string{"servertest.ini": original})
+
+	body, _ := json.Marshal(SaveRequest{Items: []ConfigItem{
+		{Key: "PVP", Value: "true"},
+		{Key: "MaxPlayers", Value: "32"},
+		{Key: "Public", Value: "true", Label: "Public"},
+	}})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/config/server/preview?lang=EN", bytes.NewReader(body))
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Diff    string `json:"diff"`
+		Count   int    `json:"count"`
+		Changes []struct {
+			Key   string `json:"key"`
+			Old   string `json:"old"`
+			New   string `json:"new"`
+			Added bool   `json:"added"`
+		} `json:"changes"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if resp.Count != 2 || resp.Changes[0].Key != "PVP" || resp.Changes[0].Old != "false" || !resp.Changes[1].Added {
+		t.Fatalf("resp=%+v", resp)
+	}
+	if !strings.Contains(resp.Diff, "+PVP=true") {
+		t.Fatalf("diff=%s", resp.Diff)
+	}
+
+	got, _ := os.ReadFile(filepath.Join(serverDir, "servertest.ini"))
+	if string(got) != original {
+		t.Fatalf("preview must not write, got=%q", got)
+	}
+}
+
+func TestHandleSaveConfig_RequiresETag(t *testing.T) {
+	r, _ := newConfigTestEngine(t, map[string]string{"servertest.ini": "PVP=false\n"})
+
+	body, _ := json.Marshal(SaveRequest{Items: []ConfigItem{{Key: "PVP", Value: "true"}}})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/config/server", bytes.NewReader(body))
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusPreconditionRequired {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+}
+
+func TestHandleRestoreConfigSnapshot_RequiresETag(t *testing.T) {
+	r, _ := newConfigTestEngine(t, map[string]string{"servertest.ini": "PVP=false\n"})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/config/server/history/any/restore", strings.NewReader(`{"author":"bob"}`))
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusPreconditionRequired {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+}
+
+func TestHandleSaveConfig_StaleETagReturnsConflict(t *testing.T) {
+	original := "PVP=false\nMaxPlayers=32\n"
+	r, serverDir := newConfigTestEngine(t, map[string]string{"servertest.ini": original})
+	iniPath := filepath.Join(serverDir, "servertest.ini")
+
+	// 模拟其他人在加载之后修改了文件。
+	if err := os.WriteFile(iniPath, []byte("PVP=false\nMaxPlayers=16\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	body, _ := json.Marshal(SaveRequest{Items: []ConfigItem{{Key: "PVP", Value: "true"}}})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/config/server", bytes.NewReader(body))
+	req.Header.Set("If-Match", `"`+configapp.ContentETag([]byte(original))+`"`)
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		ETag string `json:"etag"`
+		Diff string `json:"diff"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if resp.ETag != configapp.ContentETag([]byte("PVP=false\nMaxPlayers=16\n")) || !strings.Contains(resp.Diff, "MaxPlayers=16") {
+		t.Fatalf("resp=%+v", resp)
+	}
+
+	got, _ := os.ReadFile(iniPath)
+	if string(got) != "PVP=false\nMaxPlayers=16\n" {
+		t.Fatalf("file should be untouched, got=%q", got)
+	}
+}
+
+func TestHandleSpawnRegions_GetAndSave(t *testing.T) {
+	original := "function SpawnRegions()\n\treturn {\n\t\t{ name = \"Muldraugh, KY\", file = \"media/maps/Muldraugh, KY/spawnpoints.lua\" },\n--\t\t{ name = \"Bar\", serverfile = \"servertest_spawnpoints.lua\" },\n\t}\nend\n"
+	r, serverDir := newConfigTestEngine(t, map[string]string{"servertest_spawnregions.lua": original})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/config/spawnregions", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+	var got struct {
+		Regions []SpawnRegion `json:"regions"`
+		ETag    string        `json:"etag"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(got.Regions) != 2 || !got.Regions[1].Disabled {
+		t.Fatalf("regions=%+v", got.Regions)
+	}
+
+	got.Regions[1].Disabled = false
+	body, _ := json.Marshal(SaveRequest{Regions: got.Regions, ETag: got.ETag})
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/config/spawnregions", bytes.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+
+	saved, _ := os.ReadFile(filepath.Join(serverDir, "servertest_spawnregions.lua"))
+	want := strings.Replace(original, "--\t\t{ name = \"Bar\"", "\t\t{ name = \"Bar\"", 1)
+	if string(saved) != want {
+		t.Fatalf("saved=%q", saved)
+	}
+}
+
+func TestHandleSpawnPoints_CreateMissingFile(t *testing.T) {
+	r, serverDir := newConfigTestEngine(t, map[string]string{"servertest.ini": "PVP=false\n"})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/config/spawnpoints", nil))
+	var got struct {
+		Professions []ProfessionSpawns `json:"professions"`
+		ETag        string             `json:"etag"`
+	}
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &got) != nil {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+	if len(got.Professions) != 0 || got.ETag != configapp.AbsentETag {
+		t.Fatalf("got=%+v", got)
+	}
+
+	professions := []ProfessionSpawns{{Profession: "unemployed", Points: []config.SpawnPoint{{WorldX: 1, WorldY: 2, PosX: 3, PosY: 4}}}}
+	body, _ := json.Marshal(SaveRequest{Professions: professions, ETag: got.ETag})
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/config/spawnpoints", bytes.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+	if _, err := os.Stat(filepath.Join(serverDir, "servertest_spawnpoints.lua")); err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+
+	// 文件已存在后，再用 AbsentETag 保存会冲突。
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/config/spawnpoints", bytes.NewReader(body)))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+}
+
+func TestHandleGetServerConfig_MasksSecrets(t *testing.T) {
+	r, _ := newConfigTestEngine(t, map[string]string{"servertest.ini": "PVP=false\nRCONPassword=hunter2\nPassword=\n"})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/config/server?lang=EN", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), "hunter2") {
+		t.Fatalf("response leaks secret: %s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/secrets/RCONPassword/reveal", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "hunter2") {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,96 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"pz-web-backend/internal/jobs"
+)
+
+func (a App) handleListJobs(c *gin.Context) {
+	c.JSON(http.StatusOK, a.Jobs.List(c.Query("kind")))
+}
+
+func (a App) handleGetJob(c *gin.Context) {
+	job, err := a.Jobs.Get(c.Param("id"))
+	if err != nil {
+		writeJobError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+func (a App) handleCancelJob(c *gin.Context) {
+	job, err := a.Jobs.Cancel(c.Param("id"))
+	if errors.Is(err, jobs.ErrFinished) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "job": job})
+		return
+	}
+	if err != nil {
+		writeJobError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, job)
+}
+
+// handleStreamJob 先推送当前快照（event: job），再推送增量事件，任务结束后关闭。
+func (a App) handleStreamJob(c *gin.Context) {
+	job, events, unsubscribe, err := a.Jobs.Subscribe(c.Param("id"))
+	if err != nil {
+		writeJobError(c, err)
+		return
+	}
+	defer unsubscribe()
+
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.Header().Set("Connection", "keep-alive")
+	c.Writer.Header().Set("X-Accel-Buffering", "no")
+
+	writeSSE(c, "job", job)
+	if job.State != jobs.StateRunning {
+		writeSSE(c, "done", jobs.Event{Type: "done", Job: &job})
+		return
+	}
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			writeSSE(c, ev.Type, ev)
+		}
+	}
+}
+
+func writeSSE(c *gin.Context, event string, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", event, data)
+	c.Writer.Flush()
+}
+
+// writeJobStartError 独占任务冲突时返回 409 和正在运行的任务。
+func writeJobStartError(c *gin.Context, running jobs.Job, err error) {
+	if errors.Is(err, jobs.ErrBusy) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "job": running})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+func writeJobError(c *gin.Context, err error) {
+	if errors.Is(err, jobs.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
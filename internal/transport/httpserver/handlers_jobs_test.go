@@ -0,0 +1,93 @@
+package httpserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"pz-web-backend/internal/application/steamupdateapp"
+	"pz-web-backend/internal/jobs"
+)
+
+type fakeController struct{}
+
+func (fakeController) RestartPZServer() error { return nil }
+func (fakeController) StopPZServer() error    { return nil }
+func (fakeController) StartPZServer() error   { return nil }
+
+type fakeSteamCMD struct{ release chan struct{} }
+
+func (f fakeSteamCMD) Stream(ctx context.Context, onLine func(string), name string, args ...string) error {
+	<-f.release
+	onLine(" Update state (0x61) downloading, progress: 42.00 (42 / 100)")
+	onLine("Success! App '380870' fully installed.")
+	return nil
+}
+
+func TestHandleJobs_UpdateStartAndStream(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	release := make(chan struct{})
+	m := &jobs.Manager{}
+	a := App{Jobs: m, SteamUpdate: steamupdateapp.Service{Runner: fakeSteamCMD{release: release}, Server: fakeController{}, Jobs: m}}
+	r := gin.New()
+	a.registerActionRoutes(r)
+	a.registerJobRoutes(r)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/server/update", nil))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		JobID string `json:"job_id"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.JobID == "" {
+		t.Fatalf("body=%s err=%v", w.Body.String(), err)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/server/update", nil))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("second start: status=%d", w.Code)
+	}
+
+	close(release)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/jobs/"+resp.JobID+"/stream", nil))
+	body := w.Body.String()
+	if !strings.HasPrefix(body, "event: job\n") || !strings.Contains(body, "event: done\n") {
+		t.Fatalf("stream=%s", body)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/jobs?kind="+steamupdateapp.JobKind, nil))
+	var list []struct {
+		ID       string `json:"id"`
+		State    string `json:"state"`
+		Progress struct {
+			Percent float64 `json:"percent"`
+		} `json:"progress"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(list) != 1 || list[0].ID != resp.JobID || list[0].State != "succeeded" || list[0].Progress.Percent != 42 {
+		t.Fatalf("list=%+v", list)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/jobs/"+resp.JobID+"/cancel", nil))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("cancel finished: status=%d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/jobs/missing", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("missing: status=%d", w.Code)
+	}
+}
@@ -0,0 +1,129 @@
+package httpserver
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"pz-web-backend/internal/application/logapp"
+)
+
+func (a App) handleListLogFiles(c *gin.Context) {
+	files, err := a.LogSearch.Files()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"files": files})
+}
+
+// handleSearchLogs ?from=&to=（RFC3339）&level=&q=（正则）&player=&file=&cursor=&limit=。
+func (a App) handleSearchLogs(c *gin.Context) {
+	q, ok := parseLogQuery(c)
+	if !ok {
+		return
+	}
+	res, err := a.LogSearch.Search(q)
+	if err != nil {
+		writeLogSearchError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// handleDownloadLogs 以 gzip 下载：只给 file 时为整个文件，否则为按条件筛选出的行；
+// file 与 from/to 至少指定一个，避免一次导出全部历史日志。
+func (a App) handleDownloadLogs(c *gin.Context) {
+	q, ok := parseLogQuery(c)
+	if !ok {
+		return
+	}
+	if q.File == "" && q.From.IsZero() && q.To.IsZero() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file or from/to is required"})
+		return
+	}
+	// 开始写响应后无法再返回错误状态，先确认文件存在。
+	name := "pz-logs"
+	if q.File != "" {
+		if _, err := a.LogSearch.File(q.File); err != nil {
+			writeLogSearchError(c, err)
+			return
+		}
+		name = strings.TrimSuffix(path.Base(q.File), ".gz")
+	}
+	if !q.From.IsZero() || !q.To.IsZero() {
+		name += "-" + formatWindow(q.From) + "-" + formatWindow(q.To)
+	}
+
+	c.Header("Content-Type", "application/gzip")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.gz"`, name))
+	c.Status(http.StatusOK)
+	zw := gzip.NewWriter(c.Writer)
+	if err := a.LogSearch.Export(zw, q); err != nil {
+		// 已写出的内容保留，末尾注明导出中断。
+		fmt.Fprintf(zw, "\n==> export failed: %v <==\n", err)
+	}
+	zw.Close()
+}
+
+func parseLogQuery(c *gin.Context) (logapp.Query, bool) {
+	q := logapp.Query{
+		Player: strings.TrimSpace(c.Query("player")),
+		File:   c.Query("file"),
+		Cursor: c.Query("cursor"),
+	}
+	var err error
+	var ok bool
+	for _, f := range []struct {
+		name string
+		dst  *time.Time
+	}{{"from", &q.From}, {"to", &q.To}} {
+		if raw := c.Query(f.name); raw != "" {
+			if *f.dst, err = time.Parse(time.RFC3339, raw); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + f.name + ": expected RFC3339 time"})
+				return logapp.Query{}, false
+			}
+		}
+	}
+	if q.Level, ok = parseLevelQuery(c); !ok {
+		return logapp.Query{}, false
+	}
+	if raw := c.Query("q"); raw != "" {
+		if q.Pattern, err = regexp.Compile(raw); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid pattern: " + err.Error()})
+			return logapp.Query{}, false
+		}
+	}
+	if raw := c.Query("limit"); raw != "" {
+		if q.Limit, err = strconv.Atoi(raw); err != nil || q.Limit < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return logapp.Query{}, false
+		}
+	}
+	return q, true
+}
+
+func formatWindow(t time.Time) string {
+	if t.IsZero() {
+		return "any"
+	}
+	return t.UTC().Format("20060102T150405Z")
+}
+
+func writeLogSearchError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, logapp.ErrFileNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, logapp.ErrInvalidCursor):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
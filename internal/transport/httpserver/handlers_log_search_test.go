@@ -0,0 +1,98 @@
+package httpserver
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"pz-web-backend/internal/application/logapp"
+)
+
+func newLogSearchRouter(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "pz-stdout.log")
+	content := "LOG  : General     , 1714521600000> 1> Server started\n" +
+		"LOG  : Network     , 1714525200000> 2> ConnectionManager: [fully-connected] username=\"Griefer\"\n" +
+		"WARN : General     , 1714528800000> 3> griefer burned the base\n"
+	if err := os.WriteFile(logPath, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	app := App{LogSearch: logapp.Service{LogPath: logPath, LogsDir: filepath.Join(dir, "Logs")}}
+	r := gin.New()
+	app.registerLogRoutes(r)
+	return r
+}
+
+func TestHandleSearchLogs(t *testing.T) {
+	r := newLogSearchRouter(t)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/logs/search?player=griefer&limit=1", nil))
+	var res logapp.Result
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &res) != nil {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+	if len(res.Matches) != 1 || res.Matches[0].Line != 2 || res.NextCursor == "" {
+		t.Fatalf("res=%+v", res)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/logs/search?player=griefer&limit=1&cursor="+res.NextCursor, nil))
+	res = logapp.Result{}
+	json.Unmarshal(w.Body.Bytes(), &res)
+	if len(res.Matches) != 1 || res.Matches[0].Level != "WARN" || res.NextCursor != "" {
+		t.Fatalf("page2=%+v", res)
+	}
+
+	for _, q := range []string{"?q=(", "?from=yesterday", "?level=loud", "?cursor=bad"} {
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/logs/search"+q, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s status=%d", q, w.Code)
+		}
+	}
+}
+
+func TestHandleDownloadLogs(t *testing.T) {
+	r := newLogSearchRouter(t)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/logs/download?from=2024-05-01T01:00:00Z&to=2024-05-01T02:00:00Z", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Header().Get("Content-Disposition"), "pz-logs-20240501T010000Z-20240501T020000Z.gz") {
+		t.Fatalf("status=%d headers=%v", w.Code, w.Header())
+	}
+	zr, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("gzip: %v", err)
+	}
+	data, _ := io.ReadAll(zr)
+	if string(data) != "==> console/pz-stdout.log <==\n"+
+		"LOG  : Network     , 1714525200000> 2> ConnectionManager: [fully-connected] username=\"Griefer\"\n"+
+		"WARN : General     , 1714528800000> 3> griefer burned the base\n" {
+		t.Fatalf("data=%q", data)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/logs/download", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status=%d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/logs/download?file=console/missing.log", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status=%d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/logs/files", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"id":"console/pz-stdout.log"`) {
+		t.Fatalf("files=%s", w.Body.String())
+	}
+}
@@ -1,9 +1,15 @@
 package httpserver
 
 import (
+	"errors"
 	"net/http"
+	"net/url"
+	"regexp"
 	"strings"
 
+	"pz-web-backend/internal/application/modsapp"
+	"pz-web-backend/internal/mods"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -14,7 +20,12 @@ func (a App) handleModsLookup(c *gin.Context) {
 		return
 	}
 
-	targetIds := strings.Split(idsStr, ",")
+	var targetIds []string
+	for _, id := range strings.Split(idsStr, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			targetIds = append(targetIds, id)
+		}
+	}
 	var results []ModInfo
 
 	lookup, _ := a.ModsApp.Lookup(targetIds)
@@ -35,6 +46,39 @@ func (a App) handleModsLookup(c *gin.Context) {
 	c.JSON(http.StatusOK, results)
 }
 
+var workshopIDPattern = regexp.MustCompile(`^\d+$`)
+
+// handleImportCollection id 可以是合集 ID，也可以是粘贴的合集页面链接（?id=...）。
+func (a App) handleImportCollection(c *gin.Context) {
+	id := strings.TrimSpace(c.Query("id"))
+	if u, err := url.Parse(id); err == nil && u.Query().Get("id") != "" {
+		id = u.Query().Get("id")
+	}
+	if !workshopIDPattern.MatchString(id) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid collection id"})
+		return
+	}
+	res, err := a.ModsApp.ImportCollection(id)
+	if errors.Is(err, mods.ErrCollectionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (a App) handleModUpdates(c *gin.Context) {
+	report, err := a.ModsApp.CheckUpdates()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
 func (a App) handleListLocalMods(c *gin.Context) {
 	localMods, _ := a.ModsApp.ListLocalMods()
 	if localMods == nil {
@@ -43,3 +87,64 @@ func (a App) handleListLocalMods(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, localMods)
 }
+
+func (a App) handleResolveMods(c *gin.Context) {
+	var req ModsResolveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var selected []string
+	for _, m := range req.Mods {
+		for _, id := range strings.Split(m, ";") {
+			if id = strings.TrimSpace(id); id != "" {
+				selected = append(selected, id)
+			}
+		}
+	}
+	if len(selected) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mods required"})
+		return
+	}
+	res, err := a.ModsApp.Resolve(selected, req.AddMissing)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (a App) handleModsHealth(c *gin.Context) {
+	report, err := a.ModsApp.Health()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("ETag", quoteETag(report.ETag))
+	c.JSON(http.StatusOK, report)
+}
+
+func (a App) handleFixModsHealth(c *gin.Context) {
+	var req ModsHealthFixRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	etag := requestETag(c, req.ETag)
+	if etag == "" {
+		c.JSON(http.StatusPreconditionRequired, gin.H{"error": modsapp.ErrETagRequired.Error()})
+		return
+	}
+	res, err := a.ModsApp.FixHealth(modsapp.FixInput{
+		IssueIDs: req.Issues,
+		ETag:     etag,
+		Author:   requestAuthor(c, req.Author),
+		Restart:  req.Restart,
+	})
+	if err != nil {
+		writeSaveError(c, err)
+		return
+	}
+	c.Header("ETag", quoteETag(res.ETag))
+	c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,183 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"pz-web-backend/internal/application/configapp"
+	"pz-web-backend/internal/application/modsapp"
+	"pz-web-backend/internal/infra/fs"
+	"pz-web-backend/internal/mods"
+)
+
+func TestHandleResolveMods(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	root := repoRoot(t)
+	app := App{ModsApp: modsapp.Service{InstallDir: filepath.Join(root, "testdata", "pzserver")}}
+	r := gin.New()
+	app.registerModsRoutes(r)
+
+	w := httptest.NewRecorder()
+	body := `{"mods":["MetalSpears;P4HasBeenRead", "Unknown"]}`
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/mods/resolve", strings.NewReader(body)))
+	var res mods.Resolution
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &res) != nil {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+	if res.ModsLine != "MetalSpears;P4HasBeenRead;Unknown" || res.WorkshopItemsLine != "2927603127;2544353492" ||
+		len(res.Unknown) != 1 {
+		t.Fatalf("res=%+v", res)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/mods/resolve", strings.NewReader(`{"mods":[";"]}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status=%d", w.Code)
+	}
+}
+
+func TestHandleFixModsHealth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	root := t.TempDir()
+	iniPath := filepath.Join(root, "Server", "servertest.ini")
+	if err := os.MkdirAll(filepath.Dir(iniPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(iniPath, []byte("Mods=ghost\nWorkshopItems=\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	cfg := configapp.Service{BaseDataDir: root, ServerName: "servertest", DevMode: true, FS: fs.OSFS{}}
+	app := App{ModsApp: modsapp.Service{InstallDir: root, Config: cfg}}
+	r := gin.New()
+	app.registerModsRoutes(r)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/mods/health", nil))
+	var report modsapp.HealthReport
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &report) != nil {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+	if len(report.Issues) != 1 || report.Issues[0].ID != "mod_not_found:ghost" || report.Fixable != 1 {
+		t.Fatalf("report=%+v", report)
+	}
+
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/mods/health/fix", strings.NewReader(`{"issues":["mod_not_found:ghost"]}`))
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusPreconditionRequired {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/api/mods/health/fix", strings.NewReader(`{"issues":["mod_not_found:ghost"]}`))
+	req.Header.Set("If-Match", `"stale"`)
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	body := `{"issues":["mod_not_found:ghost"],"etag":"` + report.ETag + `"}`
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/mods/health/fix", strings.NewReader(body)))
+	var res modsapp.FixResult
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &res) != nil {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+	if len(res.Applied) != 1 || len(res.Report.Issues) != 0 || res.ETag == report.ETag {
+		t.Fatalf("res=%+v", res)
+	}
+	data, _ := os.ReadFile(iniPath)
+	if !strings.Contains(string(data), "Mods=\n") {
+		t.Fatalf("ini=%q", data)
+	}
+}
+
+func TestHandleImportCollection(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	steam := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.Form.Get("collectioncount") != "" {
+			if r.Form.Get("publishedfileids[0]") != "100" {
+				w.Write([]byte(`{"response":{"collectiondetails":[{"publishedfileid":"5","result":9}]}}`))
+				return
+			}
+			w.Write([]byte(`{"response":{"collectiondetails":[{"publishedfileid":"100","result":1,"children":[{"publishedfileid":"7","sortorder":0,"filetype":0}]}]}}`))
+			return
+		}
+		w.Write([]byte(`{"response":{"resultcount":1,"publishedfiledetails":[{"publishedfileid":"7","result":1,"title":"T","description":"Mod ID: x"}]}}`))
+	}))
+	defer steam.Close()
+	client, err := mods.NewWorkshopClient(http.DefaultClient, steam.URL, nil)
+	if err != nil {
+		t.Fatalf("NewWorkshopClient: %v", err)
+	}
+	app := App{ModsApp: modsapp.Service{InstallDir: t.TempDir(), Workshop: client}}
+	r := gin.New()
+	app.registerModsRoutes(r)
+
+	w := httptest.NewRecorder()
+	target := "/api/mods/collection?id=" + url.QueryEscape("https://steamcommunity.com/sharedfiles/filedetails/?id=100")
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	var res modsapp.CollectionImport
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &res) != nil {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+	if res.WorkshopItemsLine != "7" || res.ModsLine != "x" {
+		t.Fatalf("res=%+v", res)
+	}
+
+	for target, want := range map[string]int{
+		"/api/mods/collection?id=5":   http.StatusNotFound,
+		"/api/mods/collection?id=abc": http.StatusBadRequest,
+	} {
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		if w.Code != want {
+			t.Fatalf("%s status=%d", target, w.Code)
+		}
+	}
+}
+
+func TestHandleModUpdates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	steam := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"response":{"resultcount":2,"publishedfiledetails":[` +
+			`{"publishedfileid":"7","result":1,"title":"T","time_updated":1700000000,"file_size":"10"},` +
+			`{"publishedfileid":"8","result":9}]}}`))
+	}))
+	defer steam.Close()
+	client, err := mods.NewWorkshopClient(http.DefaultClient, steam.URL, nil)
+	if err != nil {
+		t.Fatalf("NewWorkshopClient: %v", err)
+	}
+	root := t.TempDir()
+	iniPath := filepath.Join(root, "Server", "servertest.ini")
+	if err := os.MkdirAll(filepath.Dir(iniPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(iniPath, []byte("WorkshopItems=7;8\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	cfg := configapp.Service{BaseDataDir: root, ServerName: "servertest", DevMode: true, FS: fs.OSFS{}}
+	app := App{ModsApp: modsapp.Service{InstallDir: root, Workshop: client, Items: cfg}}
+	r := gin.New()
+	app.registerModsRoutes(r)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/mods/updates", nil))
+	var report modsapp.UpdateReport
+	if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &report) != nil {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+	if strings.Join(report.Missing, ",") != "7" || strings.Join(report.Removed, ",") != "8" ||
+		report.Items[0].RemoteSize != 10 || report.Items[0].RemoteUpdated == nil {
+		t.Fatalf("report=%+v", report)
+	}
+}
@@ -1,11 +1,13 @@
 package httpserver
 
 import (
+	"context"
 	"net/http"
 	"os"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"pz-web-backend/internal/jobs"
 )
 
 var (
@@ -14,11 +16,16 @@ var (
 	panelRestartExit  = os.Exit
 )
 
-func handleRestartPanel(c *gin.Context) {
-	go func() {
+// handleRestartPanel 以独占任务退出面板进程（由 supervisor 拉起），避免打断正在进行的更新或重启。
+func (a App) handleRestartPanel(c *gin.Context) {
+	job, err := a.Jobs.Start(jobs.Spec{Kind: "panel.restart", Exclusive: true}, func(ctx context.Context, r *jobs.Reporter) error {
 		panelRestartSleep(panelRestartDelay)
 		panelRestartExit(0)
-	}()
-
-	c.JSON(http.StatusOK, gin.H{"status": "ok", "message": "Restarting..."})
+		return nil
+	})
+	if err != nil {
+		writeJobStartError(c, job, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok", "message": "Restarting...", "job_id": job.ID})
 }
@@ -1,6 +1,7 @@
 package httpserver
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -8,6 +9,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"pz-web-backend/internal/jobs"
 )
 
 func TestHandleRestartPanel_ReturnsOKAndTriggersExit(t *testing.T) {
@@ -27,8 +29,9 @@ func TestHandleRestartPanel_ReturnsOKAndTriggersExit(t *testing.T) {
 	panelRestartSleep = func(time.Duration) {}
 	panelRestartExit = func(code int) { exitCh <- code }
 
+	a := App{Jobs: &jobs.Manager{}}
 	r := gin.New()
-	r.POST("/api/service/restart", handleRestartPanel)
+	r.POST("/api/service/restart", a.handleRestartPanel)
 
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodPost, "/api/service/restart", nil)
@@ -50,3 +53,27 @@ func TestHandleRestartPanel_ReturnsOKAndTriggersExit(t *testing.T) {
 		t.Fatalf("expected exit to be called")
 	}
 }
+
+func TestHandleRestartPanel_RejectsWhileServerJobRuns(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	m := &jobs.Manager{}
+	release := make(chan struct{})
+	defer close(release)
+	if _, err := m.Start(jobs.Spec{Kind: "server.update", Exclusive: true}, func(ctx context.Context, r *jobs.Reporter) error {
+		<-release
+		return nil
+	}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	a := App{Jobs: m}
+	r := gin.New()
+	r.POST("/api/service/restart", a.handleRestartPanel)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/service/restart", nil))
+	if w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), "server.update") {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,148 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"pz-web-backend/internal/application/rconapp"
+	"pz-web-backend/internal/infra/procmgr"
+	"pz-web-backend/internal/infra/rcon"
+	"pz-web-backend/internal/jobs"
+)
+
+// rconQuitJobKind 通过 RCON quit 停服在 jobs.Manager 中的类型。
+const rconQuitJobKind = "server.quit"
+
+// quit 之后等待进程退出的超时与轮询间隔；等待期间独占任务不结束，看门狗不会把这次停服当作崩溃。
+var (
+	rconQuitTimeout = 2 * time.Minute
+	rconQuitPoll    = time.Second
+)
+
+func (a App) handleRCONExec(c *gin.Context) {
+	var req RCONExecRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if fields := strings.Fields(req.Command); len(fields) > 0 && strings.EqualFold(fields[0], "quit") {
+		a.runRCONQuit(c, func() (string, error) { return a.RCONApp.Exec(req.Command) })
+		return
+	}
+	writeRCONResult(c)(a.RCONApp.Exec(req.Command))
+}
+
+func (a App) handleRCONPlayers(c *gin.Context) {
+	players, err := a.RCONApp.Players()
+	if err != nil {
+		writeRCONError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"count": len(players), "players": players})
+}
+
+func (a App) handleRCONServerMessage(c *gin.Context) {
+	var req RCONMessageRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	writeRCONResult(c)(a.RCONApp.ServerMessage(req.Message))
+}
+
+func (a App) handleRCONSave(c *gin.Context) {
+	writeRCONResult(c)(a.RCONApp.Save())
+}
+
+func (a App) handleRCONKickUser(c *gin.Context) {
+	var req RCONUserRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	writeRCONResult(c)(a.RCONApp.KickUser(req.User, req.Reason))
+}
+
+func (a App) handleRCONBanUser(c *gin.Context) {
+	var req RCONUserRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	writeRCONResult(c)(a.RCONApp.BanUser(req.User, req.Reason, req.IP))
+}
+
+func (a App) handleRCONQuit(c *gin.Context) {
+	a.runRCONQuit(c, a.RCONApp.Quit)
+}
+
+// runRCONQuit 以独占任务执行 quit，并等到进程退出后才结束任务。
+func (a App) runRCONQuit(c *gin.Context, quit func() (string, error)) {
+	var out string
+	var quitErr error
+	err := a.Jobs.Run(jobs.Spec{Kind: rconQuitJobKind, Exclusive: true}, func(ctx context.Context, r *jobs.Reporter) error {
+		if out, quitErr = quit(); quitErr != nil {
+			return quitErr
+		}
+		return a.waitStopped(ctx)
+	})
+	switch {
+	case errors.Is(err, jobs.ErrBusy):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case quitErr != nil:
+		writeRCONError(c, quitErr)
+	case err != nil:
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": err.Error(), "output": out})
+	default:
+		c.JSON(http.StatusOK, gin.H{"output": out})
+	}
+}
+
+func (a App) waitStopped(ctx context.Context) error {
+	if a.Process == nil {
+		return nil
+	}
+	deadline := time.Now().Add(rconQuitTimeout)
+	for {
+		st, err := a.Process.Status()
+		if err == nil && !st.Running() && st.State != procmgr.StateStopping {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("server did not stop within %s", rconQuitTimeout)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(rconQuitPoll):
+		}
+	}
+}
+
+// writeRCONResult 返回一个接收 (output, err) 的函数，便于直接传入命令的返回值。
+func writeRCONResult(c *gin.Context) func(string, error) {
+	return func(out string, err error) {
+		if err != nil {
+			writeRCONError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"output": out})
+	}
+}
+
+// writeRCONError 参数错误返回 400，RCON 未启用返回 409，连接或认证失败返回 502。
+func writeRCONError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, rconapp.ErrInvalidArgument):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, rcon.ErrDisabled):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	}
+}
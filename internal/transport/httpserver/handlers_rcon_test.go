@@ -0,0 +1,99 @@
+package httpserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"pz-web-backend/internal/application/rconapp"
+	"pz-web-backend/internal/application/watchdogapp"
+	"pz-web-backend/internal/infra/procmgr"
+	"pz-web-backend/internal/jobs"
+)
+
+func TestHandleRCON_DisabledWithoutPassword(t *testing.T) {
+	r, _ := newConfigTestEngine(t, map[string]string{"servertest.ini": "RCONPort=27015\nRCONPassword=\n"})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/rcon/players", nil))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/rcon/kickuser", strings.NewReader(`{"user":"a\"b"}`))
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+}
+
+type fakeRCONCreds struct{}
+
+func (fakeRCONCreds) RCONCredentials() (int, string, error) { return 27015, "secret", nil }
+
+// quitProcess 收到 quit 后过一会儿才退出。
+type quitProcess struct {
+	fakeController
+	mu      sync.Mutex
+	stopped bool
+}
+
+func (p *quitProcess) Status() (procmgr.Status, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stopped {
+		return procmgr.Status{State: procmgr.StateStopped}, nil
+	}
+	return procmgr.Status{State: procmgr.StateRunning}, nil
+}
+
+func (p *quitProcess) Exec(command string) (string, error) {
+	if command == "quit" {
+		time.AfterFunc(30*time.Millisecond, func() {
+			p.mu.Lock()
+			p.stopped = true
+			p.mu.Unlock()
+		})
+	}
+	return "Quit", nil
+}
+
+func TestHandleRCONQuit_RunsAsExclusiveJob(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	defer func(poll time.Duration) { rconQuitPoll = poll }(rconQuitPoll)
+	rconQuitPoll = 5 * time.Millisecond
+
+	for _, tc := range []struct{ path, body string }{
+		{"/api/rcon/quit", ""},
+		{"/api/rcon/exec", `{"command":"quit"}`},
+	} {
+		proc := &quitProcess{}
+		m := &jobs.Manager{}
+		a := App{
+			Jobs:    m,
+			Process: proc,
+			RCONApp: rconapp.Service{Credentials: fakeRCONCreds{}, Dial: func(string, string) rconapp.Executor { return proc }},
+		}
+		r := gin.New()
+		a.registerRCONRoutes(r)
+
+		before := time.Now()
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body)))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status=%d body=%s", tc.path, w.Code, w.Body.String())
+		}
+		if st, _ := proc.Status(); st.Running() {
+			t.Fatalf("%s: returned before the server stopped", tc.path)
+		}
+		// 看门狗据此把这次停服视为人为操作。
+		if !m.ExclusiveSince(before, watchdogapp.RestartJobKind) {
+			t.Fatalf("%s: jobs=%+v", tc.path, m.List(""))
+		}
+	}
+}
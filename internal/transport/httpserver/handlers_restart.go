@@ -0,0 +1,69 @@
+package httpserver
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"pz-web-backend/internal/application/restartapp"
+)
+
+func (a App) handleRestartStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, a.Restart.Status())
+}
+
+func (a App) handleStartRestart(c *gin.Context) {
+	var req RestartRequest
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Message != "" && strings.Count(req.Message, "%s") != 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "message must contain exactly one %s placeholder"})
+		return
+	}
+	warnings := restartapp.DefaultWarnings
+	if req.Warnings != nil {
+		warnings = make([]time.Duration, 0, len(req.Warnings))
+		for _, w := range req.Warnings {
+			d, err := time.ParseDuration(w)
+			if err != nil || d <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid warning duration: " + w})
+				return
+			}
+			warnings = append(warnings, d)
+		}
+	}
+
+	st, err := a.Restart.Start(restartapp.Options{
+		Warnings:    warnings,
+		Message:     req.Message,
+		Reason:      req.Reason,
+		RequestedBy: requestAuthor(c, req.Author),
+	})
+	if errors.Is(err, restartapp.ErrInProgress) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "status": a.Restart.Status()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, st)
+}
+
+func (a App) handleCancelRestart(c *gin.Context) {
+	st, err := a.Restart.Cancel()
+	if errors.Is(err, restartapp.ErrNotCancellable) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "status": a.Restart.Status()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, st)
+}
@@ -0,0 +1,54 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandleRestart_StartAndCancel(t *testing.T) {
+	r, _ := newConfigTestEngine(t, map[string]string{"servertest.ini": "RCONPassword=\n"})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/server/restart", strings.NewReader(`{"warnings":["1h"],"reason":"mod update"}`)))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/server/restart", strings.NewReader(`{}`)))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("second start: status=%d body=%s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/server/restart/cancel", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("cancel: status=%d body=%s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/server/restart", nil))
+	var st struct {
+		Phase  string `json:"phase"`
+		Reason string `json:"reason"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &st); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if st.Phase != "cancelled" || st.Reason != "mod update" {
+		t.Fatalf("status=%+v", st)
+	}
+}
+
+func TestHandleRestart_RejectsBadWarning(t *testing.T) {
+	r, _ := newConfigTestEngine(t, map[string]string{"servertest.ini": "RCONPassword=\n"})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/server/restart", strings.NewReader(`{"warnings":["soon"]}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+}
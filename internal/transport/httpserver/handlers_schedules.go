@@ -0,0 +1,129 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"pz-web-backend/internal/application/scheduleapp"
+)
+
+func (a App) handleListSchedules(c *gin.Context) {
+	tasks, err := a.Scheduler.List()
+	if err != nil {
+		writeScheduleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, tasks)
+}
+
+func (a App) handleGetSchedule(c *gin.Context) {
+	task, err := a.Scheduler.Get(c.Param("id"))
+	if err != nil {
+		writeScheduleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, task)
+}
+
+func (a App) handleCreateSchedule(c *gin.Context) {
+	var req ScheduleTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	task, err := a.Scheduler.Create(req.task())
+	if err != nil {
+		writeScheduleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, task)
+}
+
+func (a App) handleUpdateSchedule(c *gin.Context) {
+	var req ScheduleTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	task, err := a.Scheduler.Update(c.Param("id"), req.task())
+	if err != nil {
+		writeScheduleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, task)
+}
+
+func (a App) handleDeleteSchedule(c *gin.Context) {
+	if err := a.Scheduler.Delete(c.Param("id")); err != nil {
+		writeScheduleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// handleRunSchedule 立即在后台执行一次，结果见执行记录。
+func (a App) handleRunSchedule(c *gin.Context) {
+	// 执行不随请求结束而取消。
+	if err := a.Scheduler.RunNow(context.Background(), c.Param("id")); err != nil {
+		writeScheduleError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"ok": true})
+}
+
+func (a App) handleScheduleRuns(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	runs, err := a.Scheduler.History(c.Param("id"), limit)
+	if err != nil {
+		writeScheduleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, runs)
+}
+
+// handleScheduleState 调度循环最近一次检查的时间与错误。
+func (a App) handleScheduleState(c *gin.Context) {
+	c.JSON(http.StatusOK, a.Scheduler.State())
+}
+
+// handlePreviewSchedule 预览任意表达式之后的触发时间：?cron=0 */6 * * *&timezone=Asia/Shanghai&count=5
+func (a App) handlePreviewSchedule(c *gin.Context) {
+	count, _ := strconv.Atoi(c.DefaultQuery("count", strconv.Itoa(scheduleapp.PreviewCount)))
+	if count <= 0 || count > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "count must be between 1 and 100"})
+		return
+	}
+	times, err := a.Scheduler.Preview(c.Query("cron"), c.Query("timezone"), count)
+	if err != nil {
+		writeScheduleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"next_runs": times})
+}
+
+func (r ScheduleTaskRequest) task() scheduleapp.Task {
+	return scheduleapp.Task{
+		Name:     r.Name,
+		Cron:     r.Cron,
+		Timezone: r.Timezone,
+		Action:   r.Action,
+		Params:   r.Params,
+		Enabled:  r.Enabled,
+	}
+}
+
+func writeScheduleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, scheduleapp.ErrInvalidTask):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, scheduleapp.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, scheduleapp.ErrRunning):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,71 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"pz-web-backend/internal/application/scheduleapp"
+)
+
+func TestHandleSchedules_CRUDAndPreview(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dir := t.TempDir()
+	a := App{Scheduler: &scheduleapp.Scheduler{
+		Tasks: &scheduleapp.FileTaskStore{Path: filepath.Join(dir, "schedules.json")},
+		Runs:  &scheduleapp.FileRunStore{Path: filepath.Join(dir, "schedule-runs.log")},
+	}}
+	r := gin.New()
+	a.registerScheduleRoutes(r)
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+
+	w := do(http.MethodPost, "/api/schedules", `{"name":"restart","cron":"0 */6 * * *","timezone":"Asia/Shanghai","action":"restart","enabled":true}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create: status=%d body=%s", w.Code, w.Body.String())
+	}
+	var created scheduleapp.TaskStatus
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil || created.ID == "" || len(created.NextRuns) != scheduleapp.PreviewCount {
+		t.Fatalf("created=%+v err=%v", created, err)
+	}
+
+	if w := do(http.MethodPost, "/api/schedules", `{"name":"bad","cron":"61 * * * *","action":"backup"}`); w.Code != http.StatusBadRequest {
+		t.Fatalf("invalid: status=%d body=%s", w.Code, w.Body.String())
+	}
+
+	w = do(http.MethodPut, "/api/schedules/"+created.ID, `{"name":"restart","cron":"@daily","action":"restart","enabled":false}`)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"next_runs":[]`) {
+		t.Fatalf("update: status=%d body=%s", w.Code, w.Body.String())
+	}
+
+	w = do(http.MethodGet, "/api/schedules", "")
+	var list []scheduleapp.TaskStatus
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || len(list) != 1 || list[0].Cron != "@daily" {
+		t.Fatalf("list=%+v err=%v", list, err)
+	}
+
+	w = do(http.MethodGet, "/api/schedules/preview?cron=@hourly&count=3", "")
+	var preview struct {
+		NextRuns []string `json:"next_runs"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &preview); err != nil || len(preview.NextRuns) != 3 {
+		t.Fatalf("preview=%s err=%v", w.Body.String(), err)
+	}
+
+	if w := do(http.MethodDelete, "/api/schedules/"+created.ID, ""); w.Code != http.StatusNoContent {
+		t.Fatalf("delete: status=%d", w.Code)
+	}
+	if w := do(http.MethodPost, "/api/schedules/"+created.ID+"/run", ""); w.Code != http.StatusNotFound {
+		t.Fatalf("run missing: status=%d", w.Code)
+	}
+	if w := do(http.MethodGet, "/api/schedules/runs", ""); w.Code != http.StatusOK || w.Body.String() != "[]" {
+		t.Fatalf("runs: status=%d body=%s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,80 @@
+package httpserver
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"pz-web-backend/internal/application/configapp"
+	"pz-web-backend/internal/audit"
+)
+
+func (a App) handleListSecrets(c *gin.Context) {
+	secrets, err := a.ConfigApp.ListSecrets()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, secrets)
+}
+
+func (a App) handleRevealSecret(c *gin.Context) {
+	key := c.Param("key")
+	var req struct {
+		Author string `json:"author"`
+	}
+	_ = c.ShouldBindJSON(&req)
+
+	value, err := a.ConfigApp.RevealSecret(key, auditActor(c, req.Author))
+	if err != nil {
+		writeSecretError(c, err)
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{"key": key, "value": value})
+}
+
+func (a App) handleRotateSecret(c *gin.Context) {
+	key := c.Param("key")
+	var req RotateSecretRequest
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	value, etag, err := a.ConfigApp.RotateSecret(key, req.Value, auditActor(c, req.Author), req.Restart)
+	if err != nil {
+		writeSecretError(c, err)
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Header("ETag", quoteETag(etag))
+	c.JSON(http.StatusOK, gin.H{"key": key, "value": value, "etag": etag})
+}
+
+func (a App) handleSecretAudit(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	entries, err := a.ConfigApp.RecentAudit(limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if entries == nil {
+		entries = []audit.Entry{}
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+// auditActor 审计记录中的操作者：请求体中的作者（缺省为客户端 IP）及客户端 IP。
+func auditActor(c *gin.Context, author string) audit.Entry {
+	return audit.Entry{Actor: requestAuthor(c, author), IP: c.ClientIP()}
+}
+
+func writeSecretError(c *gin.Context, err error) {
+	if errors.Is(err, configapp.ErrUnknownSecret) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	writeSaveError(c, err)
+}
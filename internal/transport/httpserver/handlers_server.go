@@ -0,0 +1,43 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"pz-web-backend/internal/jobs"
+)
+
+func (a App) handleServerStatus(c *gin.Context) {
+	info, err := a.Process.Status()
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, info)
+}
+
+func (a App) handleStartServer(c *gin.Context) {
+	a.runServerAction(c, "server.start", a.Process.StartPZServer)
+}
+
+func (a App) handleStopServer(c *gin.Context) {
+	a.runServerAction(c, "server.stop", a.Process.StopPZServer)
+}
+
+// runServerAction 以独占任务同步执行启动 / 停止，完成后返回最新状态。
+func (a App) runServerAction(c *gin.Context, kind string, action func() error) {
+	err := a.Jobs.Run(jobs.Spec{Kind: kind, Exclusive: true}, func(ctx context.Context, r *jobs.Reporter) error {
+		return action()
+	})
+	if errors.Is(err, jobs.ErrBusy) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+	a.handleServerStatus(c)
+}
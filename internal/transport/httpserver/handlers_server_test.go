@@ -0,0 +1,98 @@
+package httpserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"pz-web-backend/internal/infra/procmgr"
+	"pz-web-backend/internal/infra/supervisor"
+	"pz-web-backend/internal/jobs"
+)
+
+// newFakeSupervisord 只处理 start / stop / getProcessInfo，状态随 start / stop 变化。
+func newFakeSupervisord(t *testing.T) *httptest.Server {
+	state := "STOPPED"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		result := "<boolean>1</boolean>"
+		switch {
+		case strings.Contains(string(body), "supervisor.startProcess"):
+			state = "RUNNING"
+		case strings.Contains(string(body), "supervisor.stopProcess"):
+			state = "STOPPED"
+		case strings.Contains(string(body), "supervisor.getProcessInfo"):
+			result = fmt.Sprintf(`<struct><member><name>name</name><value><string>pzserver</string></value></member>`+
+				`<member><name>statename</name><value><string>%s</string></value></member></struct>`, state)
+		}
+		fmt.Fprintf(w, `<?xml version="1.0"?><methodResponse><params><param><value>%s</value></param></params></methodResponse>`, result)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestHandleServer_StartStopAndStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	srv := newFakeSupervisord(t)
+	a := App{Jobs: &jobs.Manager{}, Process: procmgr.Supervisord{Client: supervisor.NewClient(srv.URL, "", "")}}
+	r := gin.New()
+	a.registerServerRoutes(r)
+
+	state := func(w *httptest.ResponseRecorder) string {
+		var info procmgr.Status
+		if err := json.Unmarshal(w.Body.Bytes(), &info); err != nil {
+			t.Fatalf("unmarshal: %v body=%s", err, w.Body.String())
+		}
+		return info.State
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/server/start", nil))
+	if w.Code != http.StatusOK || state(w) != procmgr.StateRunning {
+		t.Fatalf("start: status=%d body=%s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/server/stop", nil))
+	if w.Code != http.StatusOK || state(w) != procmgr.StateStopped {
+		t.Fatalf("stop: status=%d body=%s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/server/status", nil))
+	if w.Code != http.StatusOK || state(w) != procmgr.StateStopped {
+		t.Fatalf("status: status=%d body=%s", w.Code, w.Body.String())
+	}
+}
+
+func TestHandleServer_BusyAndUnreachable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := &jobs.Manager{}
+	release := make(chan struct{})
+	defer close(release)
+	_, _ = m.Start(jobs.Spec{Kind: "server.update", Exclusive: true}, func(ctx context.Context, r *jobs.Reporter) error {
+		<-release
+		return nil
+	})
+	a := App{Jobs: m, Process: procmgr.Supervisord{Client: supervisor.NewClient("unix://"+t.TempDir()+"/missing.sock", "", "")}}
+	r := gin.New()
+	a.registerServerRoutes(r)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/server/stop", nil))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("stop: status=%d body=%s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/server/status", nil))
+	if w.Code != http.StatusBadGateway {
+		t.Fatalf("status: status=%d body=%s", w.Code, w.Body.String())
+	}
+}
@@ -1,9 +1,11 @@
 package httpserver
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"pz-web-backend/internal/jobs"
 )
 
 func (a App) handleCheckUpdate(c *gin.Context) {
@@ -29,9 +31,17 @@ func (a App) handlePerformUpdate(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	if err := a.UpdateApp.PerformUpdate(req.Url); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	job, err := a.Jobs.Start(jobs.Spec{Kind: "panel.update", Exclusive: true}, func(ctx context.Context, r *jobs.Reporter) error {
+		r.Logf("downloading %s", req.Url)
+		if err := a.UpdateApp.PerformUpdate(ctx, req.Url); err != nil {
+			return err
+		}
+		r.Log("installed, restarting panel")
+		return nil
+	})
+	if err != nil {
+		writeJobStartError(c, job, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"status": "updating"})
+	c.JSON(http.StatusAccepted, gin.H{"status": "updating", "job_id": job.ID})
 }
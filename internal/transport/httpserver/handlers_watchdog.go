@@ -0,0 +1,51 @@
+package httpserver
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"pz-web-backend/internal/application/watchdogapp"
+)
+
+func (a App) handleWatchdogStatus(c *gin.Context) {
+	policy, err := a.Watchdog.Policies.Load()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"policy": policy, "state": a.Watchdog.State()})
+}
+
+// handleSetWatchdogPolicy 请求体中未出现的字段保持原值。
+func (a App) handleSetWatchdogPolicy(c *gin.Context) {
+	policy, err := a.Watchdog.Policies.Load()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err := c.ShouldBindJSON(&policy); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := a.Watchdog.SetPolicy(policy); err != nil {
+		if errors.Is(err, watchdogapp.ErrInvalidPolicy) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+func (a App) handleWatchdogIncidents(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	incidents, err := a.Watchdog.Incidents.Recent(limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, incidents)
+}
@@ -0,0 +1,59 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"pz-web-backend/internal/application/watchdogapp"
+)
+
+func TestHandleWatchdog_PolicyAndIncidents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dir := t.TempDir()
+	incidents := &watchdogapp.FileIncidentStore{Path: filepath.Join(dir, "incidents.log")}
+	_ = incidents.Add(watchdogapp.Incident{ID: "1", Kind: watchdogapp.KindCrash})
+	_ = incidents.Add(watchdogapp.Incident{ID: "2", Kind: watchdogapp.KindHang})
+	a := App{Watchdog: &watchdogapp.Watchdog{
+		Incidents: incidents,
+		Policies:  &watchdogapp.FilePolicyStore{Path: filepath.Join(dir, "watchdog.json")},
+	}}
+	r := gin.New()
+	a.registerWatchdogRoutes(r)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/api/watchdog/policy", strings.NewReader(`{"max_restarts_per_hour":5}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("put: status=%d body=%s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/watchdog", nil))
+	var got struct {
+		Policy watchdogapp.Policy `json:"policy"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	// 未提交的字段保留默认值。
+	if got.Policy.MaxRestartsPerHour != 5 || got.Policy.RCONFailMinutes != watchdogapp.DefaultPolicy().RCONFailMinutes {
+		t.Fatalf("policy=%+v", got.Policy)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/api/watchdog/policy", strings.NewReader(`{"backoff_seconds":-1}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("invalid: status=%d body=%s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/watchdog/incidents?limit=1", nil))
+	var list []watchdogapp.Incident
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || len(list) != 1 || list[0].ID != "2" {
+		t.Fatalf("incidents=%+v err=%v", list, err)
+	}
+}
@@ -1,61 +1,207 @@
 package httpserver
 
 import (
-	"bufio"
+	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"pz-web-backend/internal/infra/logtail"
+	"pz-web-backend/internal/pzlog"
 )
 
-func (a App) handleStreamLogs(c *gin.Context) {
-	path := a.LogPath
+// exceptionFlushDelay 堆栈之后迟迟没有新行时，不再等待下一行直接推送挂起的异常。
+const exceptionFlushDelay = time.Second
+
+// defaultLogPath supervisord 中 pzserver 的 stdout 日志。
+const defaultLogPath = "/home/steam/pz-stdout.log"
+
+func resolveLogPath(path string) string {
 	if path == "" {
-		path = "/home/steam/pz-stdout.log"
+		return defaultLogPath
 	}
+	return path
+}
 
-	// 设置 SSE 标头
-	c.Writer.Header().Set("Content-Type", "text/event-stream")
-	c.Writer.Header().Set("Cache-Control", "no-cache")
-	c.Writer.Header().Set("Connection", "keep-alive")
-	c.Writer.Header().Set("Transfer-Encoding", "chunked")
-	c.Writer.Header().Set("Access-Control-Allow-Origin", "*") // 解决跨域
-	c.Writer.Header().Set("X-Accel-Buffering", "no")          // 解决 Nginx 缓冲
-	c.Writer.Header().Set("Content-Encoding", "identity")     // 禁用压缩
+// handleStreamLogs 通过共享的 LogHub 推送日志；每行带 id，
+// 浏览器重连时自动发送 Last-Event-ID，只补发错过的行（也可用 ?last_event_id= 指定）。
+// ?level= 只推送不低于该级别的行（堆栈等无行头的行沿用上一行的级别），?subsystem= 只推送该子系统的行。
+func (a App) handleStreamLogs(c *gin.Context) {
+	minLevel, ok := parseLevelQuery(c)
+	if !ok {
+		return
+	}
+	subsystem := strings.TrimSpace(c.Query("subsystem"))
+	filtered := minLevel != "" || subsystem != ""
+	var lastLevel pzlog.Level
+	var lastSubsystem string
+	keep := func(line logtail.Line) bool {
+		if !filtered {
+			return true
+		}
+		if parsed := pzlog.ParseLine(line.Text, nil); parsed.HasHeader() {
+			lastLevel, lastSubsystem = parsed.Level, parsed.Subsystem
+		}
+		if minLevel != "" && !lastLevel.AtLeast(minLevel) {
+			return false
+		}
+		return subsystem == "" || strings.EqualFold(lastSubsystem, subsystem)
+	}
+
+	backlog, sub, ok := a.subscribeLogs(c)
+	if !ok {
+		return
+	}
+	defer sub.Close()
+
+	for _, line := range backlog {
+		if keep(line) {
+			writeLogLine(c, line)
+		}
+	}
+	c.Writer.Flush()
 
 	ctx := c.Request.Context()
-	if a.LogTailer == nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "log tailer not configured"})
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case line, ok := <-sub.C:
+			if !ok {
+				// 被判定为慢速订阅者时直接断开，浏览器会带着 Last-Event-ID 重连补齐。
+				if err := sub.Err(); err != nil && !errors.Is(err, logtail.ErrSlowSubscriber) {
+					c.Writer.Write([]byte("event: error\ndata: " + err.Error() + "\n\n"))
+					c.Writer.Flush()
+				}
+				return
+			}
+			if keep(line) {
+				writeLogLine(c, line)
+				c.Writer.Flush()
+			}
+		}
+	}
+}
+
+// handleStreamLogEvents 把日志解析为事件推送：event 为事件类型，data 为 JSON，id 为事件最后一行的 ID，
+// 重连规则与 handleStreamLogs 相同。?type= 逗号分隔的事件类型，?level= 最低级别。
+func (a App) handleStreamLogEvents(c *gin.Context) {
+	minLevel, ok := parseLevelQuery(c)
+	if !ok {
 		return
 	}
-	rc, err := a.LogTailer.Tail(ctx, path, 100)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	types, ok := pzlog.ParseEventTypes(c.Query("type"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown event type", "types": pzlog.EventTypes})
 		return
 	}
-	defer rc.Close()
+	backlog, sub, ok := a.subscribeLogs(c)
+	if !ok {
+		return
+	}
+	defer sub.Close()
 
-	// 创建 scanner 按行读取
-	scanner := bufio.NewScanner(rc)
-	buf := make([]byte, 0, 64*1024)
-	scanner.Buffer(buf, 1024*1024)
+	var parser pzlog.Parser
+	emit := func(events []pzlog.Event) {
+		for _, ev := range events {
+			if types != nil && !types[ev.Type] {
+				continue
+			}
+			if minLevel != "" && !ev.Level.AtLeast(minLevel) {
+				continue
+			}
+			writeLogEvent(c, ev)
+		}
+	}
+	for _, line := range backlog {
+		emit(parser.Feed(line.ID, line.Text))
+	}
+	c.Writer.Flush()
 
-	// 循环读取输出并发送给前端
-	for scanner.Scan() {
-		text := scanner.Text()
-		// SSE 格式要求: "data: <content>\n\n"
-		// 可以在这里简单过滤一下空行
-		if text != "" {
-			c.Writer.Write([]byte("data: " + text + "\n\n"))
+	ctx := c.Request.Context()
+	flush := time.NewTimer(exceptionFlushDelay)
+	defer flush.Stop()
+	for {
+		// 只在有挂起的异常时等待超时。
+		var flushC <-chan time.Time
+		if parser.Pending() {
+			flush.Reset(exceptionFlushDelay)
+			flushC = flush.C
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-flushC:
+			emit(parser.Flush())
+			c.Writer.Flush()
+		case line, ok := <-sub.C:
+			if !ok {
+				emit(parser.Flush())
+				if err := sub.Err(); err != nil && !errors.Is(err, logtail.ErrSlowSubscriber) {
+					c.Writer.Write([]byte("event: error\ndata: " + err.Error() + "\n\n"))
+				}
+				c.Writer.Flush()
+				return
+			}
+			emit(parser.Feed(line.ID, line.Text))
 			c.Writer.Flush()
 		}
 	}
+}
 
-	if err := scanner.Err(); err != nil {
-		c.Writer.Write([]byte("event: error\ndata: " + err.Error() + "\n\n"))
-		c.Writer.Flush()
+// subscribeLogs 按 Last-Event-ID 订阅 LogHub 并写好 SSE 标头；失败时已写入错误响应。
+func (a App) subscribeLogs(c *gin.Context) ([]logtail.Line, *logtail.Subscription, bool) {
+	if a.LogHub == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "log hub not configured"})
+		return nil, nil, false
+	}
+	lastID, _ := strconv.ParseUint(c.GetHeader("Last-Event-ID"), 10, 64)
+	if lastID == 0 {
+		lastID, _ = strconv.ParseUint(c.Query("last_event_id"), 10, 64)
+	}
+	backlog, sub, err := a.LogHub.Subscribe(lastID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, nil, false
 	}
 
-	// Give the client a moment to receive buffered data before returning.
-	time.Sleep(10 * time.Millisecond)
+	// 设置 SSE 标头
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.Header().Set("Connection", "keep-alive")
+	c.Writer.Header().Set("Transfer-Encoding", "chunked")
+	c.Writer.Header().Set("Access-Control-Allow-Origin", "*") // 解决跨域
+	c.Writer.Header().Set("X-Accel-Buffering", "no")          // 解决 Nginx 缓冲
+	c.Writer.Header().Set("Content-Encoding", "identity")     // 禁用压缩
+	return backlog, sub, true
+}
+
+// parseLevelQuery 解析 ?level=；不合法时已写入 400。
+func parseLevelQuery(c *gin.Context) (pzlog.Level, bool) {
+	raw := c.Query("level")
+	if raw == "" {
+		return "", true
+	}
+	level := pzlog.ParseLevel(raw)
+	if level == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid level: " + raw})
+		return "", false
+	}
+	return level, true
+}
+
+// writeLogLine SSE 格式: "id: <id>\ndata: <content>\n\n"
+func writeLogLine(c *gin.Context, line logtail.Line) {
+	c.Writer.Write([]byte("id: " + strconv.FormatUint(line.ID, 10) + "\ndata: " + line.Text + "\n\n"))
+}
+
+func writeLogEvent(c *gin.Context, ev pzlog.Event) {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+	c.Writer.Write([]byte("id: " + strconv.FormatUint(ev.LineID, 10) + "\nevent: " + string(ev.Type) + "\ndata: " + string(data) + "\n\n"))
 }
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"pz-web-backend/internal/infra/logtail"
 )
 
 type staticTailer struct {
@@ -26,8 +27,8 @@ func TestHandleStreamLogs_LongLinesNotTruncated(t *testing.T) {
 	tailer := staticTailer{rc: io.NopCloser(strings.NewReader(longLine + "\n"))}
 
 	app := App{
-		LogPath:   "/does-not-matter",
-		LogTailer: tailer,
+		LogPath: "/does-not-matter",
+		LogHub:  &logtail.Hub{Tailer: tailer, Path: "/does-not-matter"},
 	}
 
 	r := gin.New()
@@ -57,3 +58,90 @@ func TestHandleStreamLogs_LongLinesNotTruncated(t *testing.T) {
 		t.Fatalf("missing suffix payload")
 	}
 }
+
+func TestHandleStreamLogs_ResumesFromLastEventID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	hub := &logtail.Hub{Tailer: staticTailer{rc: io.NopCloser(strings.NewReader("first\nsecond\n"))}, Path: "/does-not-matter"}
+	app := App{LogHub: hub}
+	r := gin.New()
+	r.GET("/api/logs/stream", app.handleStreamLogs)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/logs/stream", nil))
+	body := w.Body.String()
+	if !strings.Contains(body, "data: first\n") || !strings.Contains(body, "data: second\n") {
+		t.Fatalf("body=%q", body)
+	}
+	// 取第一行的 id。
+	firstID := strings.TrimPrefix(strings.SplitN(body, "\n", 2)[0], "id: ")
+
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/logs/stream", nil)
+	req.Header.Set("Last-Event-ID", firstID)
+	r.ServeHTTP(w, req)
+	if body := w.Body.String(); strings.Contains(body, "first") || !strings.Contains(body, "data: second\n") {
+		t.Fatalf("resumed body=%q", body)
+	}
+}
+
+const sampleConsole = `LOG  : General     , 1697038843806> 1> Loading world
+ERROR: General     , 1697038843807> 2> DebugLogStream.printException> Stack trace:
+java.lang.NullPointerException: boom
+	at zombie.GameServer.main(GameServer.java:10)
+LOG  : Network     , 1697038843808> 3> ConnectionManager: [fully-connected] "" connection: steam-id=76561198000000001 username="Bob"
+WARN : Mod         , 1697038843809> 4> required mod "Foo" not found
+`
+
+func TestHandleStreamLogs_LevelAndSubsystemFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	serve := func(query string) *httptest.ResponseRecorder {
+		app := App{LogHub: &logtail.Hub{Tailer: staticTailer{rc: io.NopCloser(strings.NewReader(sampleConsole))}, Path: "/does-not-matter"}}
+		r := gin.New()
+		r.GET("/api/logs/stream", app.handleStreamLogs)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/logs/stream"+query, nil))
+		return w
+	}
+
+	body := serve("?level=warn").Body.String()
+	if strings.Contains(body, "Loading world") || strings.Contains(body, "fully-connected") ||
+		!strings.Contains(body, "data: \tat zombie.GameServer") || !strings.Contains(body, "required mod") {
+		t.Fatalf("level body=%q", body)
+	}
+	body = serve("?subsystem=network").Body.String()
+	if strings.Count(body, "data: ") != 1 || !strings.Contains(body, "fully-connected") {
+		t.Fatalf("subsystem body=%q", body)
+	}
+	if w := serve("?level=loud"); w.Code != http.StatusBadRequest {
+		t.Fatalf("status=%d", w.Code)
+	}
+}
+
+func TestHandleStreamLogEvents_TypedEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	serve := func(query string) *httptest.ResponseRecorder {
+		app := App{LogHub: &logtail.Hub{Tailer: staticTailer{rc: io.NopCloser(strings.NewReader(sampleConsole))}, Path: "/does-not-matter"}}
+		r := gin.New()
+		r.GET("/api/logs/events", app.handleStreamLogEvents)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/logs/events"+query, nil))
+		return w
+	}
+
+	body := serve("").Body.String()
+	exc := strings.Index(body, "event: exception\ndata: {")
+	conn := strings.Index(body, "event: player_connected\n")
+	mod := strings.Index(body, "event: mod_error\n")
+	if exc < 0 || conn < exc || mod < conn || !strings.Contains(body, `"steam_id":"76561198000000001"`) ||
+		!strings.Contains(body, `"exception":"java.lang.NullPointerException"`) {
+		t.Fatalf("body=%q", body)
+	}
+
+	body = serve("?type=player_connected").Body.String()
+	if strings.Count(body, "event: ") != 1 || !strings.Contains(body, `"username":"Bob"`) {
+		t.Fatalf("filtered body=%q", body)
+	}
+	if w := serve("?type=explosion"); w.Code != http.StatusBadRequest {
+		t.Fatalf("status=%d", w.Code)
+	}
+}
@@ -1,9 +1,12 @@
 package httpserver
 
 import (
+	"context"
 	"io/fs"
 
 	"github.com/gin-gonic/gin"
+	"pz-web-backend/internal/infra/procmgr"
+	"pz-web-backend/internal/infra/steamcmd"
 )
 
 type Config struct {
@@ -14,6 +17,13 @@ type Config struct {
 	DevMode     bool
 	Build       BuildInfo
 
+	// SteamCMD 为空字段使用默认值（安装目录默认同模组扫描目录）。
+	SteamCMD steamcmd.Options
+	// Process 未填写的 direct 模式参数由 NewApp 按安装目录与日志路径补全。
+	Process procmgr.Config
+	// GameVersion 服务器的游戏版本（如 42.13.1），用于判断模组会加载哪个变体目录；为空或无法解析时视为未知。
+	GameVersion string
+
 	ContentFS fs.FS
 }
 
@@ -21,7 +31,12 @@ func NewEngine(cfg Config) *gin.Engine {
 	r := gin.Default()
 	SetupStaticAndTemplates(r, cfg.ContentFS)
 
-	app := NewApp(cfg.BaseDataDir, cfg.BaseGameDir, cfg.ServerName, cfg.LogPath, cfg.Build, cfg.DevMode)
+	app := NewApp(cfg.BaseDataDir, cfg.BaseGameDir, cfg.ServerName, cfg.LogPath, cfg.Build, cfg.DevMode, cfg.SteamCMD, cfg.Process, cfg.GameVersion)
 	app.RegisterRoutes(r)
+	// 开发模式下没有真实的服务器进程，不启动看门狗。
+	if !cfg.DevMode {
+		app.Watchdog.Start(context.Background())
+	}
+	app.Scheduler.Start(context.Background())
 	return r
 }
@@ -4,4 +4,5 @@ import "github.com/gin-gonic/gin"
 
 func (a App) registerActionRoutes(r *gin.Engine) {
 	r.POST("/api/action/update_restart", a.handleUpdateAndRestart)
+	r.POST("/api/server/update", a.handleUpdateAndRestart)
 }
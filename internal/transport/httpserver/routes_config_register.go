@@ -5,5 +5,13 @@ import "github.com/gin-gonic/gin"
 func (a App) registerConfigRoutes(r *gin.Engine) {
 	r.GET("/api/config/server", a.handleGetServerConfig)
 	r.GET("/api/config/sandbox", a.handleGetSandboxConfig)
+	r.GET("/api/config/spawnregions", a.handleGetSpawnRegions)
+	r.GET("/api/config/spawnpoints", a.handleGetSpawnPoints)
 	r.POST("/api/config/:name", a.handleSaveConfig)
+	r.POST("/api/config/:name/preview", a.handlePreviewConfig)
+
+	r.GET("/api/config/:name/history", a.handleListConfigHistory)
+	r.GET("/api/config/:name/history/:id", a.handleGetConfigSnapshot)
+	r.GET("/api/config/:name/history/:id/diff", a.handleDiffConfigSnapshot)
+	r.POST("/api/config/:name/history/:id/restore", a.handleRestoreConfigSnapshot)
 }
@@ -0,0 +1,10 @@
+package httpserver
+
+import "github.com/gin-gonic/gin"
+
+func (a App) registerJobRoutes(r *gin.Engine) {
+	r.GET("/api/jobs", a.handleListJobs)
+	r.GET("/api/jobs/:id", a.handleGetJob)
+	r.POST("/api/jobs/:id/cancel", a.handleCancelJob)
+	r.GET("/api/jobs/:id/stream", a.handleStreamJob)
+}
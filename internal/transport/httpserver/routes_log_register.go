@@ -4,4 +4,8 @@ import "github.com/gin-gonic/gin"
 
 func (a App) registerLogRoutes(r *gin.Engine) {
 	r.GET("/api/logs/stream", a.handleStreamLogs)
+	r.GET("/api/logs/events", a.handleStreamLogEvents)
+	r.GET("/api/logs/files", a.handleListLogFiles)
+	r.GET("/api/logs/search", a.handleSearchLogs)
+	r.GET("/api/logs/download", a.handleDownloadLogs)
 }
@@ -5,4 +5,9 @@ import "github.com/gin-gonic/gin"
 func (a App) registerModsRoutes(r *gin.Engine) {
 	r.GET("/api/mods/lookup", a.handleModsLookup)
 	r.GET("/api/mods", a.handleListLocalMods)
+	r.GET("/api/mods/collection", a.handleImportCollection)
+	r.GET("/api/mods/updates", a.handleModUpdates)
+	r.POST("/api/mods/resolve", a.handleResolveMods)
+	r.GET("/api/mods/health", a.handleModsHealth)
+	r.POST("/api/mods/health/fix", a.handleFixModsHealth)
 }
@@ -0,0 +1,13 @@
+package httpserver
+
+import "github.com/gin-gonic/gin"
+
+func (a App) registerRCONRoutes(r *gin.Engine) {
+	r.POST("/api/rcon/exec", a.handleRCONExec)
+	r.GET("/api/rcon/players", a.handleRCONPlayers)
+	r.POST("/api/rcon/servermsg", a.handleRCONServerMessage)
+	r.POST("/api/rcon/save", a.handleRCONSave)
+	r.POST("/api/rcon/kickuser", a.handleRCONKickUser)
+	r.POST("/api/rcon/banuser", a.handleRCONBanUser)
+	r.POST("/api/rcon/quit", a.handleRCONQuit)
+}
@@ -5,10 +5,17 @@ import "github.com/gin-gonic/gin"
 func (a App) RegisterRoutes(r *gin.Engine) {
 	a.registerIndexRoutes(r)
 	a.registerConfigRoutes(r)
+	a.registerSecretRoutes(r)
 	a.registerActionRoutes(r)
 	a.registerI18nRoutes(r)
 	a.registerModsRoutes(r)
 	a.registerSystemRoutes(r)
 	a.registerServiceRoutes(r)
+	a.registerRCONRoutes(r)
+	a.registerServerRoutes(r)
+	a.registerRestartRoutes(r)
+	a.registerJobRoutes(r)
 	a.registerLogRoutes(r)
+	a.registerWatchdogRoutes(r)
+	a.registerScheduleRoutes(r)
 }
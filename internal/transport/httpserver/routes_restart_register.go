@@ -0,0 +1,9 @@
+package httpserver
+
+import "github.com/gin-gonic/gin"
+
+func (a App) registerRestartRoutes(r *gin.Engine) {
+	r.GET("/api/server/restart", a.handleRestartStatus)
+	r.POST("/api/server/restart", a.handleStartRestart)
+	r.POST("/api/server/restart/cancel", a.handleCancelRestart)
+}
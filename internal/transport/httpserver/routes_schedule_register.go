@@ -0,0 +1,16 @@
+package httpserver
+
+import "github.com/gin-gonic/gin"
+
+func (a App) registerScheduleRoutes(r *gin.Engine) {
+	r.GET("/api/schedules", a.handleListSchedules)
+	r.POST("/api/schedules", a.handleCreateSchedule)
+	r.GET("/api/schedules/preview", a.handlePreviewSchedule)
+	r.GET("/api/schedules/state", a.handleScheduleState)
+	r.GET("/api/schedules/runs", a.handleScheduleRuns)
+	r.GET("/api/schedules/:id", a.handleGetSchedule)
+	r.PUT("/api/schedules/:id", a.handleUpdateSchedule)
+	r.DELETE("/api/schedules/:id", a.handleDeleteSchedule)
+	r.POST("/api/schedules/:id/run", a.handleRunSchedule)
+	r.GET("/api/schedules/:id/runs", a.handleScheduleRuns)
+}
@@ -0,0 +1,10 @@
+package httpserver
+
+import "github.com/gin-gonic/gin"
+
+func (a App) registerSecretRoutes(r *gin.Engine) {
+	r.GET("/api/secrets", a.handleListSecrets)
+	r.GET("/api/secrets/audit", a.handleSecretAudit)
+	r.POST("/api/secrets/:key/reveal", a.handleRevealSecret)
+	r.POST("/api/secrets/:key/rotate", a.handleRotateSecret)
+}
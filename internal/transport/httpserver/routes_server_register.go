@@ -0,0 +1,9 @@
+package httpserver
+
+import "github.com/gin-gonic/gin"
+
+func (a App) registerServerRoutes(r *gin.Engine) {
+	r.GET("/api/server/status", a.handleServerStatus)
+	r.POST("/api/server/start", a.handleStartServer)
+	r.POST("/api/server/stop", a.handleStopServer)
+}
@@ -3,5 +3,5 @@ package httpserver
 import "github.com/gin-gonic/gin"
 
 func (a App) registerServiceRoutes(r *gin.Engine) {
-	r.POST("/api/service/restart", handleRestartPanel)
+	r.POST("/api/service/restart", a.handleRestartPanel)
 }
@@ -0,0 +1,9 @@
+package httpserver
+
+import "github.com/gin-gonic/gin"
+
+func (a App) registerWatchdogRoutes(r *gin.Engine) {
+	r.GET("/api/watchdog", a.handleWatchdogStatus)
+	r.PUT("/api/watchdog/policy", a.handleSetWatchdogPolicy)
+	r.GET("/api/watchdog/incidents", a.handleWatchdogIncidents)
+}
@@ -1,20 +1,92 @@
 package httpserver
 
 import (
+	"pz-web-backend/internal/application/scheduleapp"
 	"pz-web-backend/internal/config"
 	"pz-web-backend/internal/mods"
 )
 
 type ConfigOption = config.Option
 type ConfigItem = config.Item
+type SpawnRegion = config.SpawnRegion
+type ProfessionSpawns = config.ProfessionSpawns
 type ModInfo = mods.ModInfo
 
+// ModsResolveRequest Mods 为选中的 ModID，元素中也可以是 "A;B" 形式的整行 Mods= 值。
+type ModsResolveRequest struct {
+	Mods []string `json:"mods"`
+	// AddMissing 自动补上本地已下载的依赖。
+	AddMissing bool `json:"add_missing"`
+}
+
+// ModsHealthFixRequest Issues 为要修复的问题 ID（见 /api/mods/health），为空时修复全部可修复项。
+type ModsHealthFixRequest struct {
+	Issues  []string `json:"issues"`
+	ETag    string   `json:"etag"`
+	Author  string   `json:"author"`
+	Restart bool     `json:"restart"`
+}
+
 type LanguageOption struct {
 	Code string `json:"code"`
 	Name string `json:"name"`
 }
 
 type SaveRequest struct {
-	Items   []ConfigItem `json:"items"`
-	Restart bool         `json:"restart"`
+	Items []ConfigItem `json:"items"`
+	// Regions / Professions 分别用于 spawnregions / spawnpoints。
+	Regions     []SpawnRegion      `json:"regions"`
+	Professions []ProfessionSpawns `json:"professions"`
+	Restart     bool               `json:"restart"`
+	Author      string             `json:"author"`
+	Note        string             `json:"note"`
+	// ETag 读取配置时返回的 etag，也可通过 If-Match 请求头传递。
+	ETag string `json:"etag"`
+}
+
+type RestoreRequest struct {
+	Author  string `json:"author"`
+	Restart bool   `json:"restart"`
+	// ETag 当前配置文件的 etag，也可通过 If-Match 请求头传递。
+	ETag string `json:"etag"`
+}
+
+type RotateSecretRequest struct {
+	// Value 为空时随机生成。
+	Value   string `json:"value"`
+	Author  string `json:"author"`
+	Restart bool   `json:"restart"`
+}
+
+type RCONExecRequest struct {
+	Command string `json:"command"`
+}
+
+type RCONMessageRequest struct {
+	Message string `json:"message"`
+}
+
+type RCONUserRequest struct {
+	User   string `json:"user"`
+	Reason string `json:"reason"`
+	// IP 仅用于 banuser：同时封禁 IP。
+	IP bool `json:"ip"`
+}
+
+type RestartRequest struct {
+	// Warnings 倒计时提醒点（如 "10m"、"10s"）；省略时使用默认值，空数组表示立即重启。
+	Warnings []string `json:"warnings"`
+	Message  string   `json:"message"`
+	Reason   string   `json:"reason"`
+	Author   string   `json:"author"`
+}
+
+// ScheduleTaskRequest 创建或修改定时任务；修改时整体替换。
+type ScheduleTaskRequest struct {
+	Name     string             `json:"name"`
+	Cron     string             `json:"cron"`
+	Timezone string             `json:"timezone"`
+	Action   scheduleapp.Action `json:"action"`
+	Params   scheduleapp.Params `json:"params"`
+	Enabled  bool               `json:"enabled"`
 }
@@ -9,8 +9,12 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	// 定时任务按时区触发，精简镜像中可能没有系统时区数据库。
+	_ "time/tzdata"
 
+	"pz-web-backend/internal/infra/procmgr"
 	"pz-web-backend/internal/infra/pzpaths"
+	"pz-web-backend/internal/infra/steamcmd"
 	httpserver "pz-web-backend/internal/transport/httpserver"
 )
 
@@ -49,7 +53,25 @@ func main() {
 			CommitSHA:  CommitSHA,
 			BuildTime:  BuildTime,
 		},
-		ContentFS: contentFS,
+		SteamCMD: steamcmd.Options{
+			Path:         os.Getenv("PZ_STEAMCMD_PATH"),
+			InstallDir:   os.Getenv("PZ_INSTALL_DIR"),
+			Beta:         os.Getenv("PZ_STEAM_BRANCH"),
+			BetaPassword: os.Getenv("PZ_STEAM_BRANCH_PASSWORD"),
+			Validate:     true,
+		},
+		Process: procmgr.Config{
+			Mode:               os.Getenv("PZ_PROCESS_MANAGER"),
+			SupervisorURL:      os.Getenv("PZ_SUPERVISOR_URL"),
+			SupervisorUser:     os.Getenv("PZ_SUPERVISOR_USER"),
+			SupervisorPassword: os.Getenv("PZ_SUPERVISOR_PASSWORD"),
+			SystemdUnit:        os.Getenv("PZ_SYSTEMD_UNIT"),
+			DockerSocket:       os.Getenv("PZ_DOCKER_SOCKET"),
+			DockerContainer:    os.Getenv("PZ_DOCKER_CONTAINER"),
+			Command:            os.Getenv("PZ_SERVER_COMMAND"),
+		},
+		GameVersion: os.Getenv("PZ_GAME_VERSION"),
+		ContentFS:   contentFS,
 	})
 
 	srv := &http.Server{
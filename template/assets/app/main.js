@@ -9,14 +9,17 @@ function app() {
                 sandboxConfig: [],
                 serverSections: {},
                 sandboxSections: {},
+                configETags: { server: '', sandbox: '' }, // 加载时的文件版本，保存时用于冲突检测
                 logs: '...',
-                status: { pid: 0, uptime: '0s' },
+                status: { state: '', pid: 0, uptime_seconds: 0 }, // 游戏服务器的进程状态（见 procmgr.Status）
                 toast: { show: false, message: '', type: 'success' },
                 modInput: '',
                 modLoading: false,
                 availableMods: [], // 本地库
                 activeMods: [],    // 当前启用列表
                 logConnected: false,
+                lastLogId: '', // 最后收到的日志行 id，重新连接时只补发之后的行
+                updateJob: null,   // 当前 SteamCMD 更新任务（stage / progress / state）
 
 
                 init() {
@@ -25,6 +28,7 @@ function app() {
                     this.$watch('currentTab', (val) => {
                         if (val === 'monitor') {
                             this.startLogStream();
+                            this.fetchStatus();
                         } else {
                             this.stopLogStream(); // 离开页面时断开连接，节省资源
                         }
@@ -34,7 +38,31 @@ function app() {
                 refreshAll() {
                     this.fetchConfig('server');
                     this.fetchConfig('sandbox');
-                    // 预留: this.fetchStatus();
+                },
+
+                async fetchStatus() {
+                    try {
+                        const res = await fetch('/api/server/status');
+                        const data = await res.json();
+                        this.status = res.ok ? data : { state: 'unknown', pid: 0, uptime_seconds: 0, error: data.error };
+                    } catch (e) {
+                        this.status = { state: 'unknown', pid: 0, uptime_seconds: 0, error: e.message };
+                    }
+                },
+
+                // 启动 / 停止游戏服务器（supervisord）
+                async serverAction(action) {
+                    this.loading = true;
+                    try {
+                        const res = await fetch(`/api/server/${action}`, { method: 'POST' });
+                        const data = await res.json();
+                        if (!res.ok) throw new Error(data.error || this.i18n.msg_exec_fail);
+                        this.status = data;
+                    } catch (e) {
+                        this.showToast(e.message, 'error');
+                    } finally {
+                        this.loading = false;
+                    }
                 },
 
                 async initI18n() {
@@ -69,6 +97,7 @@ function app() {
                     try {
                         const res = await fetch(`/api/config/${type}?lang=${this.lang}`);
                         const data = await res.json();
+                        this.configETags[type] = data.etag || '';
                         
                         // 按 Section 分组
                         const grouped = data.items.reduce((acc, item) => {
@@ -267,11 +296,27 @@ function app() {
                         const res = await fetch(`/api/config/${type}`, {
                             method: 'POST',
                             headers: { 'Content-Type': 'application/json' },
-                            body: JSON.stringify({ items: items, restart: restart })
+                            body: JSON.stringify({ items: items, restart: restart, etag: this.configETags[type] })
                         });
 
-                        if (!res.ok) throw new Error(this.i18n.msg_save_fail);
+                        if (res.status === 409) {
+                            // 文件已被他人修改：提示差异，确认后重新加载
+                            const data = await res.json().catch(() => ({}));
+                            const msg = (this.i18n.msg_save_conflict || 'The file was changed by someone else. Reload and discard your edits?');
+                            if (confirm(msg + (data.diff ? '\n\n' + data.diff : ''))) {
+                                await this.fetchConfig(type);
+                            }
+                            return;
+                        }
+
+                        if (!res.ok) {
+                            const data = await res.json().catch(() => ({}));
+                            const detail = (data.fields || []).map(f => `${f.key}: ${f.message}`).join('\n');
+                            throw new Error(detail || data.error || this.i18n.msg_save_fail);
+                        }
 
+                        const saved = await res.json().catch(() => ({}));
+                        if (saved.etag) this.configETags[type] = saved.etag;
                         this.showToast(this.i18n.msg_save_success, 'success');
 
                     } catch (e) {
@@ -287,6 +332,8 @@ function app() {
                         const res = await fetch(`/api/action/${action}`, { method: 'POST' });
                         if (res.ok) {
                              this.showToast((this.i18n.msg_cmd_sent || 'Command Sent') + ': ' + action, 'success');
+                             const data = await res.json();
+                             if (data.job_id) this.watchUpdateJob(data.job_id);
                         } else {
                             throw new Error(this.i18n.msg_exec_fail);
                         }
@@ -297,21 +344,42 @@ function app() {
                     }
                 },
 
+                // 订阅 SteamCMD 更新任务进度
+                watchUpdateJob(id) {
+                    const es = new EventSource(`/api/jobs/${id}/stream`);
+                    es.addEventListener('job', (e) => { this.updateJob = JSON.parse(e.data); });
+                    es.addEventListener('stage', (e) => { this.updateJob.stage = JSON.parse(e.data).stage; });
+                    es.addEventListener('progress', (e) => { this.updateJob.progress = JSON.parse(e.data).progress; });
+                    es.addEventListener('done', (e) => {
+                        es.close();
+                        this.updateJob = JSON.parse(e.data).job;
+                        if (this.updateJob.state === 'succeeded') {
+                            this.showToast(this.i18n.msg_server_update_done || 'Server update finished', 'success');
+                        } else {
+                            this.showToast(this.updateJob.error || this.i18n.msg_exec_fail, 'error');
+                        }
+                    });
+                    es.onerror = () => es.close();
+                },
+
                  // 开启 SSE 日志流
                 startLogStream() {
                     if (this.eventSource) return; // 避免重复连接
 
-                    this.logs = "Connecting...\n";
-                    
-                    // 创建 EventSource
-                    this.eventSource = new EventSource('/api/logs/stream');
+                    const resume = this.lastLogId !== '';
+                    if (!resume) this.logs = "Connecting...\n";
+
+                    // 创建 EventSource；手动重新打开时带上最后的 id，只补发错过的行
+                    const url = resume ? `/api/logs/stream?last_event_id=${encodeURIComponent(this.lastLogId)}` : '/api/logs/stream';
+                    this.eventSource = new EventSource(url);
 
                     this.eventSource.onopen = () => {
+                        if (this.lastLogId === '') this.logs = ""; // 首次连接成功后清空提示
                         this.logConnected = true;
-                        this.logs = ""; // 连接成功后清空提示
                     };
 
                     this.eventSource.onmessage = (event) => {
+                        if (event.lastEventId) this.lastLogId = event.lastEventId;
                         // 追加日志
                         this.logs += event.data + "\n";
                         
@@ -331,10 +399,11 @@ function app() {
                     this.eventSource.onerror = (err) => {
                         console.error("SSE Error:", err);
                         this.logConnected = false;
-                        this.eventSource.close();
-                        this.eventSource = null;
-                        // 可以选择自动重连，或者手动重连
-                        this.logs += "\n[disconnect...]\n";
+                        // 浏览器会自动重连并发送 Last-Event-ID，服务端只补发错过的行
+                        if (this.eventSource.readyState === EventSource.CLOSED) {
+                            this.eventSource = null;
+                            this.logs += "\n[disconnect...]\n";
+                        }
                     };
                 },
 
@@ -368,6 +437,10 @@ function app() {
                     fetch('/api/service/restart', { method: 'POST' })
                         .then(res => res.json())
                         .then(data => {
+                            if (data.error) {
+                                alert(data.error);
+                                return;
+                            }
                             alert(data.message);
                             // 3秒后刷新页面
                             setTimeout(() => location.reload(), 3000);
@@ -388,11 +461,15 @@ function app() {
                                     .replace('{1}', data.current);
 
                             if (confirm(msg)) {
-                                await fetch('/api/system/perform_update', {
+                                const upRes = await fetch('/api/system/perform_update', {
                                     method: 'POST',
                                     headers: {'Content-Type': 'application/json'},
                                     body: JSON.stringify({ url: data.download_url })
                                 });
+                                if (!upRes.ok) {
+                                    this.showToast((await upRes.json()).error || this.i18n.msg_exec_fail, 'error');
+                                    return;
+                                }
                                 // 使用 i18n
                                 alert(this.i18n.msg_update_performing || 'Update command sent, please refresh later.');
                             }